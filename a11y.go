@@ -0,0 +1,80 @@
+package svg
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Role is the value of an ARIA role attribute.
+type Role string
+
+const (
+	RoleFigure Role = "figure"
+	RoleImg    Role = "img"
+	RoleGroup  Role = "group"
+	RoleList   Role = "list"
+	RoleItem   Role = "listitem"
+)
+
+// Accessible makes the container accessible to assistive technology
+// by setting its role attribute, and adding <title> and <desc>
+// elements, if title or desc is not empty. Use RoleImg for a chart
+// that shall be announced as a single image described by title and
+// desc, or RoleFigure if its content shall remain accessible too.
+func (c *Container) Accessible(role Role, title, desc string) *Container {
+	c.Role = role
+	c.Title = title
+	c.Desc = desc
+	return c
+}
+
+// SetRole sets the object's ARIA role attribute.
+func (o *Object) SetRole(role Role) *Object {
+	o.Role = role
+	return o
+}
+
+// SetAriaLabel sets an aria-label attribute, providing an accessible
+// name for objects that have no <title>, like a data point of a chart.
+func (o *Object) SetAriaLabel(label string) *Object {
+	o.AriaLabel = label
+	return o
+}
+
+// SetAriaLabelledBy sets an aria-labelledby attribute, referring to
+// the IDs of elements, like text labels, that name the object.
+func (o *Object) SetAriaLabelledBy(ids ...string) *Object {
+	o.AriaLabelledBy = strings.Join(ids, " ")
+	return o
+}
+
+// Decorative marks the object as purely decorative, like grid lines
+// or background shapes, by setting aria-hidden, so that it is
+// ignored by assistive technology.
+func (o *Object) Decorative() *Object {
+	o.Attr("aria-hidden", "true")
+	return o
+}
+
+// SeriesSummary returns a short textual summary of a data series,
+// stating the number of values, their range and their mean,
+// which may be used as content of a <desc> element.
+func SeriesSummary(name string, values []float64) string {
+	if len(values) == 0 {
+		return name + ": no values."
+	}
+	min, max, sum := math.Inf(1), math.Inf(-1), 0.0
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+		sum += v
+	}
+	f := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', 4, 64)
+	}
+	if len(values) == 1 {
+		return name + ": 1 value, " + f(min) + "."
+	}
+	return name + ": " + strconv.Itoa(len(values)) + " values ranging from " + f(min) + " to " + f(max) + ", mean " + f(sum/float64(len(values))) + "."
+}
@@ -0,0 +1,89 @@
+package svg
+
+import (
+	"math"
+)
+
+// Angle is an angle, stored in degrees, the unit SVG uses for
+// rotations. Untyped constants are interpreted as degrees;
+// other units may be converted using Radians, Gradians, and Turns.
+type Angle float64
+
+// Degrees returns an Angle of d degrees.
+func Degrees(d float64) Angle {
+	return Angle(d)
+}
+
+// Radians returns an Angle of r radians.
+func Radians(r float64) Angle {
+	return Angle(r * 180 / math.Pi)
+}
+
+// Gradians returns an Angle of g gradians; a full circle has 400.
+func Gradians(g float64) Angle {
+	return Angle(g * 0.9)
+}
+
+// Turns returns an Angle of t full turns.
+func Turns(t float64) Angle {
+	return Angle(t * 360)
+}
+
+// Deg returns a in degrees.
+func (a Angle) Deg() float64 {
+	return float64(a)
+}
+
+// Rad returns a in radians.
+func (a Angle) Rad() float64 {
+	return float64(a) * math.Pi / 180
+}
+
+// Grad returns a in gradians.
+func (a Angle) Grad() float64 {
+	return float64(a) / 0.9
+}
+
+// Turn returns a in turns.
+func (a Angle) Turn() float64 {
+	return float64(a) / 360
+}
+
+// String returns a in CSS syntax, like "45deg".
+func (a Angle) String() string {
+	return formatFloat(float64(a)) + "deg"
+}
+
+// Rotate adds a rotation by a around the origin
+// of the current coordinate system.
+func (tl *TransformList) Rotate(a Angle) *TransformList {
+	return tl.RotateOrig(a.Deg())
+}
+
+// SetAngle sets the gradient vector in the direction of a, like
+// the angle of a CSS linear-gradient(): 0 points upwards, and positive
+// angles turn clockwise, e.g. 90 results in a gradient from left
+// to right. The vector passes through the center of the bounding
+// box, touching its edges, if the gradient units are
+// ObjectBoundingBox, which is the default.
+func (g *LinearGradient) SetAngle(a Angle) *LinearGradient {
+	sin, cos := math.Sincos(a.Rad())
+	// scale the vector so that its longer component spans the box
+	s := 0.5 / math.Max(math.Abs(sin), math.Abs(cos))
+	// round away noise, like in cos(90°)
+	n := func(f float64) Length {
+		return Number(math.Round(f*1e12) / 1e12)
+	}
+	return g.Vector(n(0.5-sin*s), n(0.5+cos*s), n(0.5+sin*s), n(0.5-cos*s))
+}
+
+// SetRotate sets the rotation of individual glyphs: the n-th angle
+// applies to the n-th character; the last angle also applies to
+// the remaining characters.
+func (t *TextObject) SetRotate(angles ...Angle) *TextObject {
+	t.Rotate = make(Floats64, len(angles))
+	for i, a := range angles {
+		t.Rotate[i] = a.Deg()
+	}
+	return t
+}
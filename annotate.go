@@ -0,0 +1,99 @@
+package svg
+
+// Annotations is a layer for annotating a plot with reference
+// lines, markers, and text flags, placed using data coordinates.
+// When encoding the document, the layer is moved after its
+// siblings, so that annotations are drawn above the data series,
+// even if these are added later.
+type Annotations struct {
+	Container *Container
+	Mapper    *CoordMapper
+
+	// Area is the plot area, which reference lines extend across.
+	Area BBox
+
+	// FontSize is used to estimate the size of flags;
+	// it defaults to 10.
+	FontSize float64
+}
+
+// Annotations appends a group with class "annotations", to be
+// populated using the methods of the returned layer.
+func (c *Container) Annotations(m *CoordMapper, area BBox) *Annotations {
+	g := c.Group()
+	g.SetClass("annotations")
+	g.onTop = true
+	return &Annotations{Container: g, Mapper: m, Area: area}
+}
+
+// HLine draws a horizontal reference line with class
+// "annotation-line" at the data value y. If label is not empty,
+// it is placed above the right end of the line, with class
+// "annotation-label".
+func (a *Annotations) HLine(y float64, label string) *ShapeObject {
+	ym := a.Mapper.Y.Map(y)
+	x0, x1 := a.Area.X, a.Area.X+a.Area.Width
+	l := a.Container.Line(x0, ym, x1, ym)
+	l.SetClass("annotation-line")
+	if label != "" {
+		t := a.Container.Text(x1-3, ym-3, label)
+		t.Anchor(AnchorEnd)
+		t.SetClass("annotation-label")
+	}
+	return l
+}
+
+// VLine draws a vertical reference line with class
+// "annotation-line" at the data value x. If label is not empty,
+// it is placed right of the top end of the line, with class
+// "annotation-label".
+func (a *Annotations) VLine(x float64, label string) *ShapeObject {
+	xm := a.Mapper.X.Map(x)
+	y0, y1 := a.Area.Y, a.Area.Y+a.Area.Height
+	l := a.Container.Line(xm, y0, xm, y1)
+	l.SetClass("annotation-line")
+	if label != "" {
+		t := a.Container.Text(xm+3, y0, label)
+		t.Dy = EmUnits(1)
+		t.SetClass("annotation-label")
+	}
+	return l
+}
+
+// Marker draws a circle of radius r, with class
+// "annotation-marker", around the data point x, y, which may
+// be used to shade or highlight the point.
+func (a *Annotations) Marker(x, y, r float64) *ShapeObject {
+	xm, ym := a.Mapper.Map(x, y)
+	m := a.Container.Circle(xm, ym, r)
+	m.SetClass("annotation-marker")
+	return m
+}
+
+// Flag draws a text flag pointing to the data point x, y,
+// consisting of a pole with class "annotation-pole", and a
+// rectangle with class "annotation-flag" containing the text,
+// of class "annotation-label". The returned group has class
+// "annotation", and its origin at the data point.
+func (a *Annotations) Flag(x, y float64, text string) *Container {
+	const (
+		pole = 24
+		pad  = 3
+	)
+	fs := a.FontSize
+	if fs == 0 {
+		fs = 10
+	}
+	xm, ym := a.Mapper.Map(x, y)
+	g := a.Container.Group()
+	g.SetClass("annotation")
+	g.Translate(xm, ym)
+	g.Line(0, 0, 0, -pole).SetClass("annotation-pole")
+	w := DefaultMeasurer.TextWidth(text, fs) + 2*pad
+	h := fs + 2*pad
+	g.Rect(0, -pole-h, w, h).SetClass("annotation-flag")
+	t := g.Text(pad, -pole-h/2, text)
+	t.Dy = EmUnits(0.35)
+	t.SetClass("annotation-label")
+	return g
+}
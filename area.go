@@ -0,0 +1,73 @@
+package svg
+
+import (
+	"strings"
+)
+
+// Area describes an area chart to be drawn using Document.Area.
+type Area struct {
+	X, Y []float64
+
+	// Baseline is the value the area extends to.
+	Baseline float64
+
+	// Smooth, if set, draws the line as a Catmull-Rom spline
+	// through the data points.
+	Smooth bool
+
+	// Color is used for the fade gradient filling the area;
+	// it defaults to "steelblue".
+	Color string
+
+	// TopOpacity and BottomOpacity are the opacities of
+	// the gradient at the top and bottom of the area;
+	// they default to 0.6 and 0.
+	TopOpacity, BottomOpacity float64
+}
+
+// Area appends a group with class "area-chart" to el, containing
+// the area below the line through the points of a, mapped using m,
+// filled with a vertical fade gradient, and the line itself.
+// The gradient is defined within the group, with an ID obtained
+// from MakeID. The area gets class "area", the line "area-line".
+func (d *Document) Area(el *ElemList, m *CoordMapper, a *Area) *Container {
+	color := a.Color
+	if color == "" {
+		color = "steelblue"
+	}
+	top := a.TopOpacity
+	if top == 0 {
+		top = 0.6
+	}
+	id := d.autoID("area-fade")
+
+	g := el.Group()
+	g.SetClass("area-chart")
+	lg := g.Defs().LinearGradient(id).Vector(nil, nil, Number(0), Number(1))
+	lg.StopOpacity(0, Paint(color), top)
+	lg.StopOpacity(1, Paint(color), a.BottomOpacity)
+
+	n := len(a.X)
+	if len(a.Y) < n {
+		n = len(a.Y)
+	}
+	if n == 0 {
+		return g
+	}
+	pts := make([][2]float64, n)
+	for i := range pts {
+		pts[i][0], pts[i][1] = m.Map(a.X[i], a.Y[i])
+	}
+	var line strings.Builder
+	if a.Smooth {
+		writeSpline(&line, pts, false)
+	} else {
+		writePolyline(&line, pts, false)
+	}
+	x0, y0 := m.Map(a.X[0], a.Baseline)
+	x1, y1 := m.Map(a.X[n-1], a.Baseline)
+	area := line.String() + " L" + formatPoint([2]float64{x1, y1}) + " L" + formatPoint([2]float64{x0, y0}) + " Z"
+	g.Path(area).SetClass("area").SetStyle("fill:" + string(lg.URL()))
+	g.Path(line.String()).SetClass("area-line")
+	return g
+}
@@ -0,0 +1,264 @@
+package svg
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// AxisPosition specifies where an axis is placed relative
+// to the plot area, which determines the direction of ticks
+// and the placement of labels.
+type AxisPosition int
+
+const (
+	AxisBottom AxisPosition = iota
+	AxisTop
+	AxisLeft
+	AxisRight
+)
+
+// Axis describes an axis to be drawn using Container.Axis.
+type Axis struct {
+	Scale    Scale
+	Position AxisPosition
+
+	// At is the coordinate across the axis, where the axis line
+	// is drawn, e.g. the y coordinate of a bottom axis.
+	At float64
+
+	// Ticks contains the values where tick marks are placed.
+	// If nil, about NumTicks values are obtained from the Scale.
+	Ticks    []float64
+	NumTicks int // defaults to 5
+
+	TickSize float64 // defaults to 5
+	LabelGap float64 // gap between ticks and labels; defaults to 3
+
+	// Format, if set, is used to create tick labels. By default,
+	// values are formatted using as many decimal places as
+	// needed to distinguish neighbouring ticks.
+	Format func(float64) string
+
+	// FormatTime, if set, is used to create tick labels for
+	// values representing time, in seconds since the Unix epoch.
+	// It takes precedence over Format. If neither is set, and the
+	// Scale implements TickFormatter, its Format method is used.
+	FormatTime func(time.Time) string
+
+	// LineStyle is applied to the axis line and the tick marks;
+	// it defaults to a black stroke.
+	LineStyle Styling
+
+	// LabelStyle is applied to the tick labels.
+	LabelStyle Styling
+
+	// LabelRotation, if nonzero, rotates the tick labels by the
+	// specified angle, in degrees clockwise, around the points
+	// they are anchored at. Anchors are chosen so that labels
+	// extend away from the axis.
+	LabelRotation float64
+
+	// Thin, if set, leaves out labels, keeping every n-th one
+	// with n as small as possible, so that neighbouring labels
+	// don't overlap. Label widths are estimated using Measurer,
+	// or DefaultMeasurer, and FontSize, which defaults to 10.
+	Thin     bool
+	FontSize float64
+	Measurer TextMeasurer
+}
+
+// Axis appends a group containing an axis line, tick marks, and
+// tick labels, as described by a.
+func (c *Container) Axis(a *Axis) *Container {
+	n := a.NumTicks
+	if n == 0 {
+		n = 5
+	}
+	ticks := a.Ticks
+	if ticks == nil {
+		ticks = a.Scale.Ticks(n)
+	}
+	tickSize := a.TickSize
+	if tickSize == 0 {
+		tickSize = 5
+	}
+	gap := a.LabelGap
+	if gap == 0 {
+		gap = 3
+	}
+	lineStyle := a.LineStyle
+	if lineStyle == (Styling{}) {
+		lineStyle.Style = "stroke:black"
+	}
+	format := a.labelFormatter(ticks, n)
+
+	g := c.Group()
+	g.SetClass("axis")
+	lines := g.Group()
+	lines.WithStyle(lineStyle)
+	labels := g.Group()
+	labels.WithStyle(a.LabelStyle)
+
+	r0, r1 := a.Scale.Range()
+	dir := 1.0
+	if a.Position == AxisTop || a.Position == AxisLeft {
+		dir = -1
+	}
+	vertical := a.Position == AxisLeft || a.Position == AxisRight
+	if vertical {
+		lines.Line(a.At, r0, a.At, r1)
+	} else {
+		lines.Line(r0, a.At, r1, a.At)
+	}
+	texts := make([]string, len(ticks))
+	for i, v := range ticks {
+		texts[i] = format(v)
+	}
+	every := 1
+	if a.Thin {
+		every = a.thinning(ticks, texts)
+	}
+	rot := a.LabelRotation
+	for i, v := range ticks {
+		pos := a.Scale.Map(v)
+		across := a.At + dir*tickSize
+		labelPos := across + dir*gap
+		if vertical {
+			lines.Line(a.At, pos, across, pos)
+		} else {
+			lines.Line(pos, a.At, pos, across)
+		}
+		if i%every != 0 {
+			continue
+		}
+		var t *TextObject
+		switch {
+		case rot != 0:
+			t = labels.Text(0, 0, texts[i])
+			if vertical {
+				t.TransformList.Translate(labelPos, pos)
+			} else {
+				t.TransformList.Translate(pos, labelPos)
+			}
+			t.RotateOrig(rot)
+			t.Dy = EmUnits(0.32)
+			if a.rotatedAnchorEnd() {
+				t.Anchor(AnchorEnd)
+			}
+		case vertical:
+			t = labels.Text(labelPos, pos, texts[i])
+			t.Dy = EmUnits(0.32)
+			if a.Position == AxisLeft {
+				t.Anchor(AnchorEnd)
+			}
+		default:
+			t = labels.Text(pos, labelPos, texts[i])
+			t.Anchor(AnchorMiddle)
+			if a.Position == AxisBottom {
+				t.Dy = EmUnits(0.71)
+			}
+		}
+	}
+	return g
+}
+
+// rotatedAnchorEnd reports whether rotated labels must be
+// anchored at their end to extend away from the axis.
+func (a *Axis) rotatedAnchorEnd() bool {
+	switch a.Position {
+	case AxisBottom:
+		return math.Sin(a.LabelRotation*math.Pi/180) < 0
+	case AxisTop:
+		return math.Sin(a.LabelRotation*math.Pi/180) > 0
+	case AxisLeft:
+		return math.Cos(a.LabelRotation*math.Pi/180) > 0
+	}
+	return math.Cos(a.LabelRotation*math.Pi/180) < 0
+}
+
+// thinning returns the smallest n, so that the labels of every
+// n-th tick don't overlap.
+func (a *Axis) thinning(ticks []float64, texts []string) int {
+	fs := a.FontSize
+	if fs == 0 {
+		fs = 10
+	}
+	m := a.Measurer
+	if m == nil {
+		m = DefaultMeasurer
+	}
+	const pad = 2
+	sin, cos := math.Sincos(a.LabelRotation * math.Pi / 180)
+	sin, cos = math.Abs(sin), math.Abs(cos)
+	vertical := a.Position == AxisLeft || a.Position == AxisRight
+	if vertical {
+		// measure along the axis as for horizontal
+		// axes with the rotation turned by 90 degrees
+		sin, cos = cos, sin
+	}
+	// extent returns the extent of label i along the axis;
+	// for rotated labels, the distance needed between
+	// parallel labels is used, if smaller.
+	extent := func(i int) float64 {
+		w := m.TextWidth(texts[i], fs)
+		e := w*cos + fs*sin
+		if sin > 1e-9 {
+			e = math.Min(e, fs/sin)
+		}
+		return e
+	}
+L:
+	for n := 1; n < len(ticks); n++ {
+		for i := n; i < len(ticks); i += n {
+			d := math.Abs(a.Scale.Map(ticks[i]) - a.Scale.Map(ticks[i-n]))
+			if d < (extent(i)+extent(i-n))/2+pad {
+				continue L
+			}
+		}
+		return n
+	}
+	return len(ticks)
+}
+
+// labelFormatter returns the function used to format tick labels.
+func (a *Axis) labelFormatter(ticks []float64, n int) func(float64) string {
+	ts, isTime := a.Scale.(*TimeScale)
+	switch {
+	case a.FormatTime != nil && isTime:
+		return func(v float64) string {
+			return a.FormatTime(ts.Time(v))
+		}
+	case a.FormatTime != nil:
+		return func(v float64) string {
+			sec, frac := math.Modf(v)
+			return a.FormatTime(time.Unix(int64(sec), int64(frac*1e9)))
+		}
+	case a.Format != nil:
+		return a.Format
+	}
+	if tf, ok := a.Scale.(TickFormatter); ok {
+		return func(v float64) string {
+			return tf.Format(v, n)
+		}
+	}
+	return decimalFormatter(ticks)
+}
+
+// decimalFormatter returns a function formatting values using as
+// many decimal places as needed to distinguish neighbouring ticks.
+func decimalFormatter(ticks []float64) func(float64) string {
+	step := math.Inf(1)
+	for i := 1; i < len(ticks); i++ {
+		if d := math.Abs(ticks[i] - ticks[i-1]); d > 0 && d < step {
+			step = d
+		}
+	}
+	prec := 0
+	if !math.IsInf(step, 1) {
+		prec = stepDecimals(step)
+	}
+	return func(v float64) string {
+		return strconv.FormatFloat(v, 'f', prec, 64)
+	}
+}
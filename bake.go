@@ -0,0 +1,206 @@
+package svg
+
+import (
+	"math"
+)
+
+// BakeTransforms applies the transformations of the container,
+// and those of its descendants, directly to the coordinates of the
+// child elements, and removes the transform attributes.
+// This is required by some toolchains, like those driving CNC
+// machines or plotters, that do not understand transformations.
+//
+// Lines, polylines, polygons and paths can be transformed
+// arbitrarily. Rectangles without rounded corners are converted to
+// polygons, circles and ellipses to paths, if the transformation
+// cannot be expressed by adjusting their attributes. The elements
+// within the list may therefore be replaced by new ones.
+// Text and <use> elements can only be translated; if they are
+// subject to other transformations, a single matrix() transformation
+// remains. Definitions and symbols are left untouched.
+// Stroke widths and other lengths specified in styles are not
+// adjusted.
+func (c *Container) BakeTransforms() {
+	m, ok := c.TransformList.matrix()
+	if ok {
+		c.TransformList = nil
+	} else {
+		m = identity
+	}
+	bakeList(c.ElemList, m)
+}
+
+func bakeList(el ElemList, m affine) {
+	for i, x := range el {
+		el[i] = bindShape(bakeElem(x, m))
+	}
+}
+
+func bakeElem(x interface{}, parent affine) interface{} {
+	e, ok := x.(element)
+	if !ok {
+		return x
+	}
+	switch x.(type) {
+	case *Defs, *Symbol, *Pattern, *Marker, *Filter:
+		return x
+	}
+	obj := e.object()
+	own, ok := obj.TransformList.matrix()
+	if !ok {
+		if parent != identity {
+			obj.TransformList = append(TransformList{parent.transform()}, obj.TransformList...)
+		}
+		return x
+	}
+	m := parent.mul(own)
+	obj.TransformList = nil
+
+	switch v := x.(type) {
+	case container:
+		bakeList(v.container().ElemList, m)
+	case *line:
+		p1 := m.apply([2]float64{v.X1, v.Y1})
+		p2 := m.apply([2]float64{v.X2, v.Y2})
+		v.X1, v.Y1, v.X2, v.Y2 = p1[0], p1[1], p2[0], p2[1]
+	case *PolyLine:
+		v.Points.transform(m)
+	case *CompactPolyLine:
+		v.Points.transform(m)
+	case *polygon:
+		v.Points.transform(m)
+	case *path:
+		segs, err := parsePath(v.D)
+		if err != nil {
+			keepMatrix(obj, m)
+			break
+		}
+		transformPath(segs, m)
+		v.D = formatPath(segs)
+	case *Rect:
+		if m.isAxisAligned() {
+			p1 := m.apply([2]float64{v.X, v.Y})
+			p2 := m.apply([2]float64{v.X + v.Width, v.Y + v.Height})
+			v.X, v.Width = math.Min(p1[0], p2[0]), math.Abs(p2[0]-p1[0])
+			v.Y, v.Height = math.Min(p1[1], p2[1]), math.Abs(p2[1]-p1[1])
+			if v.Ry == 0 {
+				v.Ry = v.Rx
+			} else if v.Rx == 0 {
+				v.Rx = v.Ry
+			}
+			v.Rx *= math.Abs(m[0])
+			v.Ry *= math.Abs(m[3])
+			break
+		}
+		if v.Rx != 0 || v.Ry != 0 {
+			keepMatrix(obj, m)
+			break
+		}
+		p := &polygon{PolyLine: PolyLine{
+			Points: Points{
+				{v.X, v.Y},
+				{v.X + v.Width, v.Y},
+				{v.X + v.Width, v.Y + v.Height},
+				{v.X, v.Y + v.Height},
+			},
+			ShapeObject: v.ShapeObject,
+		}}
+		p.Points.transform(m)
+		return p
+	case *circle:
+		if m.isSimilarity() {
+			c := m.apply([2]float64{v.X, v.Y})
+			v.X, v.Y = c[0], c[1]
+			v.R *= math.Sqrt(math.Abs(m[0]*m[3] - m[1]*m[2]))
+			break
+		}
+		return &path{D: ellipsePath(v.X, v.Y, v.R, v.R, m), ShapeObject: v.ShapeObject}
+	case *ellipse:
+		if m.isAxisAligned() {
+			c := m.apply([2]float64{v.X, v.Y})
+			v.X, v.Y = c[0], c[1]
+			v.Rx *= math.Abs(m[0])
+			v.Ry *= math.Abs(m[3])
+			break
+		}
+		return &path{D: ellipsePath(v.X, v.Y, v.Rx, v.Ry, m), ShapeObject: v.ShapeObject}
+	case *use:
+		if !m.isTranslation() {
+			keepMatrix(obj, m)
+			break
+		}
+		v.X += m[4]
+		v.Y += m[5]
+	case *text:
+		if !m.isTranslation() {
+			keepMatrix(obj, m)
+			break
+		}
+		v.TextObject.translate(m[4], m[5])
+	default:
+		keepMatrix(obj, m)
+	}
+	return x
+}
+
+func keepMatrix(obj *Object, m affine) {
+	if m != identity {
+		obj.TransformList = TransformList{m.transform()}
+	}
+}
+
+func (pts Points) transform(m affine) {
+	for i, pt := range pts {
+		pts[i] = m.apply(pt)
+	}
+}
+
+// translate moves the text object, and any <tspan> elements
+// having absolute coordinates, by dx and dy.
+func (t *TextObject) translate(dx, dy float64) {
+	t.X += dx
+	t.Y += dy
+	t.translateSpans(dx, dy)
+}
+
+func (t *TextObject) translateSpans(dx, dy float64) {
+	for _, d := range t.Data {
+		if ts, ok := d.(*tspan); ok {
+			if ts.X != 0 {
+				ts.X += dx
+			}
+			if ts.Y != 0 {
+				ts.Y += dy
+			}
+			ts.TextObject.translateSpans(dx, dy)
+		}
+	}
+}
+
+// kappa is the distance of the control points of a cubic Bézier
+// curve approximating a quarter circle of radius 1.
+const kappa = 0.5522847498307936
+
+// ellipsePath returns path data of an ellipse approximated by four
+// cubic Bézier curves, transformed by m.
+func ellipsePath(cx, cy, rx, ry float64, m affine) string {
+	segs := ellipseSegs(cx, cy, rx, ry)
+	transformPath(segs, m)
+	return formatPath(segs)
+}
+
+// ellipseSegs returns a closed subpath of four cubic Bézier curves
+// approximating an ellipse. The curves follow the direction of
+// increasing angles, i.e. they run clockwise on the screen.
+func ellipseSegs(cx, cy, rx, ry float64) []pathSeg {
+	kx := kappa * rx
+	ky := kappa * ry
+	return []pathSeg{
+		{cmd: 'M', pts: [3][2]float64{{cx + rx, cy}}},
+		{cmd: 'C', pts: [3][2]float64{{cx + rx, cy + ky}, {cx + kx, cy + ry}, {cx, cy + ry}}},
+		{cmd: 'C', pts: [3][2]float64{{cx - kx, cy + ry}, {cx - rx, cy + ky}, {cx - rx, cy}}},
+		{cmd: 'C', pts: [3][2]float64{{cx - rx, cy - ky}, {cx - kx, cy - ry}, {cx, cy - ry}}},
+		{cmd: 'C', pts: [3][2]float64{{cx + kx, cy - ry}, {cx + rx, cy - ky}, {cx + rx, cy}}},
+		{cmd: 'Z'},
+	}
+}
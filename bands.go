@@ -0,0 +1,71 @@
+package svg
+
+import (
+	"math"
+)
+
+// Band is a range of values to be shaded, like a warning
+// or critical range of a measurement.
+type Band struct {
+	// From and To may be infinite, in which case the band
+	// extends to the border of the plot area.
+	From, To float64
+
+	// Class is added to the class attribute of the band's rectangle.
+	Class string
+
+	// Label, if set, is placed in the band's top right corner.
+	Label string
+}
+
+// Bands describes bands to be drawn using Container.Bands.
+type Bands struct {
+	Bands []Band
+
+	// Vertical, if set, makes the bands represent ranges of
+	// x values, extending over the height of the plot area;
+	// otherwise, they represent ranges of y values.
+	Vertical bool
+}
+
+// Bands appends a group with class "bands", containing a rectangle
+// with class "band", plus the band's class, for each band of b,
+// with values mapped using s, and clipped to the plot area.
+// Labels get class "band-label". Since the bands are meant as
+// background, Bands should be called before drawing the content
+// of the plot. Bands lying outside of the plot area are left out.
+func (c *Container) Bands(area BBox, s Scale, b *Bands) *Container {
+	g := c.Group()
+	g.SetClass("bands")
+	clamp := func(v, lo, hi float64) float64 {
+		return math.Max(lo, math.Min(hi, v))
+	}
+	for _, band := range b.Bands {
+		p0, p1 := s.Map(band.From), s.Map(band.To)
+		r := area
+		if b.Vertical {
+			x0 := clamp(math.Min(p0, p1), area.X, area.X+area.Width)
+			x1 := clamp(math.Max(p0, p1), area.X, area.X+area.Width)
+			r.X, r.Width = x0, x1-x0
+		} else {
+			y0 := clamp(math.Min(p0, p1), area.Y, area.Y+area.Height)
+			y1 := clamp(math.Max(p0, p1), area.Y, area.Y+area.Height)
+			r.Y, r.Height = y0, y1-y0
+		}
+		if r.Width <= 0 || r.Height <= 0 || math.IsNaN(r.Width+r.Height) {
+			continue
+		}
+		class := "band"
+		if band.Class != "" {
+			class += " " + band.Class
+		}
+		g.Rect(r.X, r.Y, r.Width, r.Height).SetClass(class)
+		if band.Label != "" {
+			t := g.Text(r.X+r.Width-3, r.Y+3, band.Label)
+			t.Anchor(AnchorEnd)
+			t.Dy = EmUnits(0.8)
+			t.SetClass("band-label")
+		}
+	}
+	return g
+}
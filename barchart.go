@@ -0,0 +1,118 @@
+package svg
+
+import (
+	"math"
+	"strconv"
+)
+
+// BarChart describes a set of bar series, to be laid out
+// side by side, or stacked, within category bands.
+type BarChart struct {
+	// Series contains the values of each series:
+	// Series[i][j] is the value of series i in category j.
+	// NaN values denote missing bars.
+	Series [][]float64
+
+	// Classes, if set, contains classes added to the class
+	// attributes of the series' groups.
+	Classes []string
+
+	// Stacked, if set, stacks the bars of a category on top of
+	// each other, with positive values stacking upwards and
+	// negative values downwards from the baseline. Otherwise, the
+	// bars of a category are grouped side by side.
+	Stacked bool
+
+	// Baseline is the value bars start from.
+	Baseline float64
+
+	// GroupPadding is the fraction of a category band left empty
+	// between neighbouring categories; it defaults to 0.2.
+	// Negative values result in no padding.
+	GroupPadding float64
+
+	// BarPadding is the fraction of a bar's slot left empty between
+	// the bars of grouped series.
+	BarPadding float64
+}
+
+// Layout returns the rectangles of the bars, indexed like
+// b.Series, with the categories distributed over equally sized
+// bands between x0 and x1, and values mapped using ys.
+// The rectangles of missing bars have zero size.
+func (b *BarChart) Layout(x0, x1 float64, ys Scale) [][]BBox {
+	ncat := 0
+	for _, s := range b.Series {
+		if len(s) > ncat {
+			ncat = len(s)
+		}
+	}
+	boxes := make([][]BBox, len(b.Series))
+	for i, s := range b.Series {
+		boxes[i] = make([]BBox, len(s))
+	}
+	if ncat == 0 {
+		return boxes
+	}
+	gp := b.GroupPadding
+	if gp == 0 {
+		gp = 0.2
+	} else if gp < 0 {
+		gp = 0
+	}
+	band := (x1 - x0) / float64(ncat)
+	inner := band * (1 - gp)
+	slot := inner / float64(len(b.Series))
+
+	bar := func(x, w, from, to float64) BBox {
+		y0, y1 := ys.Map(from), ys.Map(to)
+		return BBox{X: x, Y: math.Min(y0, y1), Width: w, Height: math.Abs(y1 - y0)}
+	}
+	for j := 0; j < ncat; j++ {
+		x := x0 + float64(j)*band + band*gp/2
+		pos, neg := b.Baseline, b.Baseline
+		for i, s := range b.Series {
+			if j >= len(s) || math.IsNaN(s[j]) {
+				continue
+			}
+			v := s[j]
+			switch {
+			case !b.Stacked:
+				pad := slot * b.BarPadding
+				boxes[i][j] = bar(x+float64(i)*slot+pad/2, slot-pad, b.Baseline, b.Baseline+v)
+			case v >= 0:
+				boxes[i][j] = bar(x, inner, pos, pos+v)
+				pos += v
+			default:
+				boxes[i][j] = bar(x, inner, neg, neg+v)
+				neg += v
+			}
+		}
+	}
+	return boxes
+}
+
+// BarChart appends a group with class "bar-chart", containing the
+// bars of b, laid out using b.Layout. The bars of each series are
+// drawn as rectangles with class "bar" within a group with classes
+// "bar-series" and "bar-series-N", where N is the index of the
+// series, plus the series' class, if specified.
+func (c *Container) BarChart(x0, x1 float64, ys Scale, b *BarChart) *Container {
+	g := c.Group()
+	g.SetClass("bar-chart")
+	for i, boxes := range b.Layout(x0, x1, ys) {
+		sg := g.Group()
+		class := "bar-series bar-series-" + strconv.Itoa(i)
+		if i < len(b.Classes) && b.Classes[i] != "" {
+			class += " " + b.Classes[i]
+		}
+		sg.SetClass(class)
+		for j, bb := range boxes {
+			if math.IsNaN(b.Series[i][j]) {
+				continue
+			}
+			sg.Rect(bb.X, bb.Y, bb.Width, bb.Height).SetClass("bar")
+		}
+	}
+	return g
+}
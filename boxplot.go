@@ -0,0 +1,133 @@
+package svg
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// BoxPlot describes a box-and-whisker glyph, optionally
+// surrounded by a violin outline, drawn using Container.BoxPlot.
+type BoxPlot struct {
+	// Min and Max are the ends of the whiskers,
+	// Q1 and Q3 the ends of the box.
+	Min, Q1, Median, Q3, Max float64
+
+	// Outliers are drawn as small circles beyond the whiskers.
+	Outliers []float64
+
+	// Width is the width of the box; it defaults to 20.
+	Width float64
+
+	// Density, if set, contains a density estimate sampled at
+	// equidistant values from Min to Max; it is drawn as a violin
+	// outline, with the maximum density spanning twice the width
+	// of the box.
+	Density []float64
+
+	// Horizontal, if set, lays out the values along the x axis.
+	Horizontal bool
+}
+
+// NewBoxPlot returns a BoxPlot describing values, with quartiles
+// computed by linear interpolation, and whiskers extending to
+// the most extreme values within 1.5 times the interquartile
+// range from the box; values beyond are treated as outliers.
+// NaN values are ignored.
+func NewBoxPlot(values []float64) *BoxPlot {
+	v := make([]float64, 0, len(values))
+	for _, x := range values {
+		if !math.IsNaN(x) {
+			v = append(v, x)
+		}
+	}
+	b := new(BoxPlot)
+	if len(v) == 0 {
+		return b
+	}
+	sort.Float64s(v)
+	b.Q1 = quantile(v, 0.25)
+	b.Median = quantile(v, 0.5)
+	b.Q3 = quantile(v, 0.75)
+	lo := b.Q1 - 1.5*(b.Q3-b.Q1)
+	hi := b.Q3 + 1.5*(b.Q3-b.Q1)
+	b.Min, b.Max = b.Q1, b.Q3
+	for _, x := range v {
+		if x < lo || x > hi {
+			b.Outliers = append(b.Outliers, x)
+			continue
+		}
+		b.Min = math.Min(b.Min, x)
+		b.Max = math.Max(b.Max, x)
+	}
+	return b
+}
+
+// quantile returns the p-quantile of the sorted slice v.
+func quantile(v []float64, p float64) float64 {
+	f := p * float64(len(v)-1)
+	i := int(f)
+	if i+1 >= len(v) {
+		return v[len(v)-1]
+	}
+	return v[i] + (f-float64(i))*(v[i+1]-v[i])
+}
+
+// BoxPlot appends a group with class "boxplot", containing the
+// glyph b centered at pos, which is the x coordinate for vertical
+// plots, or the y coordinate for horizontal ones. Values are mapped
+// using s. The parts of the glyph get the classes "boxplot-violin",
+// "boxplot-whisker", "boxplot-box", "boxplot-median", and
+// "boxplot-outlier".
+func (c *Container) BoxPlot(pos float64, s Scale, b *BoxPlot) *Container {
+	w := b.Width
+	if w == 0 {
+		w = 20
+	}
+	pt := func(v, off float64) [2]float64 {
+		if b.Horizontal {
+			return [2]float64{s.Map(v), pos + off}
+		}
+		return [2]float64{pos + off, s.Map(v)}
+	}
+
+	g := c.Group()
+	g.SetClass("boxplot")
+	line := func(v0, off0, v1, off1 float64) *ShapeObject {
+		p0, p1 := pt(v0, off0), pt(v1, off1)
+		return g.Line(p0[0], p0[1], p1[0], p1[1])
+	}
+	if n := len(b.Density); n > 1 {
+		dmax := 0.0
+		for _, d := range b.Density {
+			dmax = math.Max(dmax, d)
+		}
+		if dmax > 0 {
+			pts := make([][2]float64, 0, 2*n)
+			val := func(k int) float64 {
+				return b.Min + float64(k)*(b.Max-b.Min)/float64(n-1)
+			}
+			for k, d := range b.Density {
+				pts = append(pts, pt(val(k), d/dmax*w))
+			}
+			for k := n - 1; k >= 0; k-- {
+				pts = append(pts, pt(val(k), -b.Density[k]/dmax*w))
+			}
+			var p strings.Builder
+			writePolyline(&p, pts, true)
+			g.Path(p.String()).SetClass("boxplot-violin")
+		}
+	}
+	line(b.Min, 0, b.Q1, 0).SetClass("boxplot-whisker")
+	line(b.Q3, 0, b.Max, 0).SetClass("boxplot-whisker")
+	line(b.Min, -w/4, b.Min, w/4).SetClass("boxplot-whisker")
+	line(b.Max, -w/4, b.Max, w/4).SetClass("boxplot-whisker")
+	p0, p1 := pt(b.Q1, -w/2), pt(b.Q3, w/2)
+	g.Rect(math.Min(p0[0], p1[0]), math.Min(p0[1], p1[1]), math.Abs(p1[0]-p0[0]), math.Abs(p1[1]-p0[1])).SetClass("boxplot-box")
+	line(b.Median, -w/2, b.Median, w/2).SetClass("boxplot-median")
+	for _, v := range b.Outliers {
+		p := pt(v, 0)
+		g.Circle(p[0], p[1], 2.5).SetClass("boxplot-outlier")
+	}
+	return g
+}
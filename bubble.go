@@ -0,0 +1,136 @@
+package svg
+
+import (
+	"math"
+	"strings"
+)
+
+// Bubble describes a speech bubble, or label box, to be drawn
+// using Container.Bubble.
+type Bubble struct {
+	Text     string
+	FontSize float64 // defaults to 12
+	MaxWidth float64 // maximum width of text lines; defaults to 200
+	Padding  float64 // defaults to 6
+	Radius   float64 // corner radius; defaults to 6
+
+	// TailWidth is the width of the tail's base; it defaults to 10.
+	TailWidth float64
+
+	// Measurer is used to estimate the width of the text;
+	// if nil, DefaultMeasurer is used.
+	Measurer TextMeasurer
+}
+
+// Bubble appends a group with class "bubble", containing a rounded
+// box centered at x, y, with a tail pointing at the anchor point
+// ax, ay, and the text of b, wrapped into lines. The box is sized to
+// fit the text. Its outline has the class "bubble-box", the text
+// "bubble-text".
+func (c *Container) Bubble(x, y, ax, ay float64, b *Bubble) *Container {
+	fs := b.FontSize
+	if fs == 0 {
+		fs = 12
+	}
+	maxWidth := b.MaxWidth
+	if maxWidth == 0 {
+		maxWidth = 200
+	}
+	pad := b.Padding
+	if pad == 0 {
+		pad = 6
+	}
+	r := b.Radius
+	if r == 0 {
+		r = 6
+	}
+	tw := b.TailWidth
+	if tw == 0 {
+		tw = 10
+	}
+	m := b.Measurer
+	if m == nil {
+		m = DefaultMeasurer
+	}
+
+	lines := WrapText(b.Text, maxWidth, fs, m)
+	w := 0.0
+	for _, l := range lines {
+		w = math.Max(w, m.TextWidth(l, fs))
+	}
+	lineHeight := 1.2 * fs
+	w += 2 * pad
+	h := float64(len(lines))*lineHeight + 2*pad
+	x0, y0 := x-w/2, y-h/2
+	x1, y1 := x0+w, y0+h
+	r = math.Min(r, math.Min(w, h)/2)
+
+	// select the edge the tail is attached to
+	side := 0 // 1: top, 2: right, 3: bottom, 4: left
+	switch {
+	case ay > y1:
+		side = 3
+	case ay < y0:
+		side = 1
+	case ax > x1:
+		side = 2
+	case ax < x0:
+		side = 4
+	}
+	tw = math.Min(tw, math.Max(math.Min(w, h)-2*r, 0))
+	bx := math.Max(x0+r+tw/2, math.Min(ax, x1-r-tw/2))
+	by := math.Max(y0+r+tw/2, math.Min(ay, y1-r-tw/2))
+
+	var p strings.Builder
+	pt := func(cmd string, x, y float64) {
+		p.WriteString(cmd + formatFloat(x) + "," + formatFloat(y))
+	}
+	rs := formatFloat(r)
+	corner := func(x, y float64) {
+		p.WriteString("A" + rs + "," + rs + " 0 0,1 " + formatFloat(x) + "," + formatFloat(y))
+	}
+	tail := func(bx0, by0, bx1, by1 float64) {
+		pt("L", bx0, by0)
+		pt("L", ax, ay)
+		pt("L", bx1, by1)
+	}
+	pt("M", x0+r, y0)
+	if side == 1 {
+		tail(bx-tw/2, y0, bx+tw/2, y0)
+	}
+	pt("L", x1-r, y0)
+	corner(x1, y0+r)
+	if side == 2 {
+		tail(x1, by-tw/2, x1, by+tw/2)
+	}
+	pt("L", x1, y1-r)
+	corner(x1-r, y1)
+	if side == 3 {
+		tail(bx+tw/2, y1, bx-tw/2, y1)
+	}
+	pt("L", x0+r, y1)
+	corner(x0, y1-r)
+	if side == 4 {
+		tail(x0, by+tw/2, x0, by-tw/2)
+	}
+	pt("L", x0, y0+r)
+	corner(x0+r, y0)
+	p.WriteString("Z")
+
+	g := c.Group()
+	g.SetClass("bubble")
+	g.Path(p.String()).SetClass("bubble-box")
+	t := g.Text(x0+pad, y0+pad+fs, "")
+	t.SetClass("bubble-text")
+	t.SetStyle("font-size:" + formatFloat(fs) + "px")
+	for i, l := range lines {
+		if i == 0 {
+			t.AddText(l)
+			continue
+		}
+		s := t.AddSpan(l)
+		s.X = x0 + pad
+		s.Dy = EmUnits(1.2)
+	}
+	return g
+}
@@ -0,0 +1,75 @@
+package svg
+
+import (
+	"encoding/xml"
+)
+
+// calcLength is a Length marshaled as a CSS calc() expression;
+// it contains the expression without the calc() wrapper.
+type calcLength string
+
+func (c calcLength) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: "calc(" + string(c) + ")"}, nil
+}
+
+// CalcAdd returns a Length representing the sum of a and b. If their
+// units are compatible, see AddLengths, the sum is computed directly;
+// otherwise, like for a percentage and px, a Length is returned that
+// will be marshaled as CSS calc() expression, like "calc(50% + 10px)",
+// resolved by the renderer. Such lengths are supported by browsers
+// for attributes that are geometry properties in SVG 2, like the
+// width and height of rectangles, but not by most other renderers.
+// Numbers are treated as px.
+func CalcAdd(a, b Length) Length {
+	if l, ok := AddLengths(a, b); ok {
+		return l
+	}
+	return calcLength(calcTerm(a, true) + " + " + calcTerm(b, true))
+}
+
+// CalcSub returns a Length representing a minus b, see CalcAdd.
+func CalcSub(a, b Length) Length {
+	if nb, ok := ScaleLength(b, -1); ok {
+		if l, ok := AddLengths(a, nb); ok {
+			return l
+		}
+	}
+	return calcLength(calcTerm(a, true) + " - " + calcTerm(b, true))
+}
+
+// CalcMul returns a Length representing a multiplied by f,
+// which may be a calc() expression, see CalcAdd.
+func CalcMul(a Length, f float64) Length {
+	if l, ok := ScaleLength(a, f); ok {
+		return l
+	}
+	return calcLength(calcTerm(a, false) + " * " + formatFloat(f))
+}
+
+// CalcDiv returns a Length representing a divided by f,
+// which may be a calc() expression, see CalcAdd.
+func CalcDiv(a Length, f float64) Length {
+	if l, ok := ScaleLength(a, 1/f); ok && f != 0 {
+		return l
+	}
+	return calcLength(calcTerm(a, false) + " / " + formatFloat(f))
+}
+
+// calcTerm returns the representation of l as an operand within
+// a calc() expression. If sum is set, l is an operand of an
+// addition or subtraction, which requires numbers to have a unit.
+func calcTerm(l Length, sum bool) string {
+	switch l := l.(type) {
+	case calcLength:
+		return "(" + string(l) + ")"
+	case number:
+		if sum {
+			return formatFloat(float64(l)) + "px"
+		}
+		return formatFloat(float64(l))
+	case nil:
+		return "0px"
+	}
+	a, _ := l.MarshalXMLAttr(xml.Name{})
+	return a.Value
+}
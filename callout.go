@@ -0,0 +1,80 @@
+package svg
+
+import (
+	"math"
+	"strconv"
+)
+
+// Callouts is a group of numbered callouts, i.e. circles containing
+// numbers, with leader lines pointing at locations of a drawing,
+// used to annotate screenshots or illustrations.
+// The elements are assigned classes, which should be used to style
+// them: "callout-leader" for leader lines, "callout-circle" for
+// the circles, and "callout-number" for the numbers.
+type Callouts struct {
+	// Radius of the circles; it defaults to 8.
+	Radius float64
+
+	leaders *Container
+	circles *Container
+	notes   []string
+}
+
+// Callouts appends a group with class "callouts", to which
+// callouts can be added using the Add and AddFor methods.
+func (c *Container) Callouts() *Callouts {
+	g := c.Group()
+	g.SetClass("callouts")
+	return &Callouts{leaders: g.Group(), circles: g.Group()}
+}
+
+// Add places a callout at x, y, with a leader line pointing at
+// tx, ty. Its number is one more than that of the previous callout.
+// The note is used by Legend. The callout's circle group is returned.
+func (co *Callouts) Add(x, y, tx, ty float64, note string) *Container {
+	r := co.Radius
+	if r == 0 {
+		r = 8
+	}
+	co.notes = append(co.notes, note)
+	if d := dist(tx-x, ty-y); d > r {
+		sx := x + (tx-x)/d*r
+		sy := y + (ty-y)/d*r
+		co.leaders.Line(sx, sy, tx, ty).SetClass("callout-leader")
+	}
+	g := co.circles.Group()
+	c := &circle{X: x, Y: y, R: r}
+	c.SetClass("callout-circle")
+	g.append(c)
+	t := g.Text(x, y, strconv.Itoa(len(co.notes)))
+	t.Anchor(AnchorMiddle)
+	t.Dy = EmUnits(0.35)
+	t.SetClass("callout-number")
+	return g
+}
+
+// AddFor places a callout at x, y, with a leader line pointing at
+// the point of the bounding box bb nearest to x, y, or at its
+// center, if x, y lies within the box. The bounding box of an
+// element can be obtained using Container.ElementBBox.
+func (co *Callouts) AddFor(x, y float64, bb BBox, note string) *Container {
+	tx := math.Max(bb.X, math.Min(x, bb.X+bb.Width))
+	ty := math.Max(bb.Y, math.Min(y, bb.Y+bb.Height))
+	if tx == x && ty == y {
+		tx = bb.X + bb.Width/2
+		ty = bb.Y + bb.Height/2
+	}
+	return co.Add(x, y, tx, ty, note)
+}
+
+// Legend appends a group with class "callout-legend" to el,
+// listing the numbers and notes of all callouts added so far,
+// one per line, starting at x, y, with the specified line height.
+func (co *Callouts) Legend(el *ElemList, x, y, lineHeight float64) *Container {
+	g := el.Group()
+	g.SetClass("callout-legend")
+	for i, note := range co.notes {
+		g.Text(x, y+float64(i)*lineHeight, strconv.Itoa(i+1)+". "+note)
+	}
+	return g
+}
@@ -0,0 +1,88 @@
+package svg
+
+import (
+	"math"
+	"time"
+)
+
+// OHLC contains the open, high, low, and close values
+// of a time period, as used in financial charts.
+type OHLC struct {
+	Time                   time.Time
+	Open, High, Low, Close float64
+}
+
+// Candlesticks describes a series to be drawn using
+// Document.Candlesticks.
+type Candlesticks struct {
+	Data []OHLC
+
+	// Bars, if set, draws OHLC bars, with ticks for the opening
+	// and closing values at the left and right side, instead of
+	// candlesticks.
+	Bars bool
+
+	// Width is the width of candle bodies or bars; it defaults to
+	// 70 % of the smallest distance between neighbouring periods.
+	Width float64
+
+	// UpStyle and DownStyle are used for periods where the
+	// close value is at least the open value, or below it.
+	// They default to green and red strokes and fills.
+	UpStyle, DownStyle string
+}
+
+// Candlesticks appends a group with class "candlesticks" to el,
+// containing the series c, with times mapped using ts, and values
+// using ys. Styles for rising and falling periods are created
+// with MakeStyle, named "candle-up" and "candle-down".
+// Each period is drawn as a path for the wick, or the bar,
+// and, for candlesticks, a rectangle for the body.
+func (d *Document) Candlesticks(el *ElemList, ts *TimeScale, ys Scale, c *Candlesticks) *Container {
+	upStyle := c.UpStyle
+	if upStyle == "" {
+		upStyle = "fill:#2a2;stroke:#2a2"
+	}
+	downStyle := c.DownStyle
+	if downStyle == "" {
+		downStyle = "fill:#c22;stroke:#c22"
+	}
+	up := d.MakeStyle("candle-up", upStyle)
+	down := d.MakeStyle("candle-down", downStyle)
+
+	w := c.Width
+	if w == 0 {
+		dmin := math.Inf(1)
+		for i := 1; i < len(c.Data); i++ {
+			dx := math.Abs(ts.MapTime(c.Data[i].Time) - ts.MapTime(c.Data[i-1].Time))
+			if dx > 0 {
+				dmin = math.Min(dmin, dx)
+			}
+		}
+		w = 5
+		if !math.IsInf(dmin, 1) {
+			w = 0.7 * dmin
+		}
+	}
+
+	g := el.Group()
+	g.SetClass("candlesticks")
+	for _, p := range c.Data {
+		st := up
+		if p.Close < p.Open {
+			st = down
+		}
+		x := ts.MapTime(p.Time)
+		yo, yc := ys.Map(p.Open), ys.Map(p.Close)
+		wick := "M" + formatPoint([2]float64{x, ys.Map(p.High)}) + " V" + formatFloat(ys.Map(p.Low))
+		if c.Bars {
+			wick += " M" + formatPoint([2]float64{x - w/2, yo}) + " H" + formatFloat(x) +
+				" M" + formatPoint([2]float64{x, yc}) + " H" + formatFloat(x+w/2)
+			g.Path(wick).WithStyle(st)
+			continue
+		}
+		g.Path(wick).WithStyle(st)
+		g.Rect(x-w/2, math.Min(yo, yc), w, math.Abs(yc-yo)).WithStyle(st)
+	}
+	return g
+}
@@ -0,0 +1,45 @@
+package svg
+
+// footerMargin is the distance of captions and attributions
+// from the edges of the viewBox.
+const footerMargin = 4
+
+// Caption appends a caption text at the bottom left corner of the
+// viewBox, which must have been set before. The text is styled
+// using the class "caption", as returned by MakeStyle, with a small
+// font size and a gray color. Callers should leave room of about
+// 1.5em at the bottom of the drawing.
+func (d *Document) Caption(text string) *TextObject {
+	x, y := d.footerPos()
+	t := d.Text(x, y, text)
+	t.WithStyle(d.MakeStyle("caption", "font-size:12px;fill:#444"))
+	return t
+}
+
+// Attribution appends an attribution text, like a copyright notice
+// or a data source, at the bottom right corner of the viewBox, which
+// must have been set before. If href is not empty, the text is
+// linked to it. The text is styled using the class "attribution",
+// as returned by MakeStyle, with a small font size and a gray color.
+func (d *Document) Attribution(text, href string) *TextObject {
+	x, y := d.footerPos()
+	if len(d.ViewBox) == 4 {
+		x = float64(d.ViewBox[0]+d.ViewBox[2]) - footerMargin
+	}
+	el := &d.ElemList
+	if href != "" {
+		el = &el.Link(href).ElemList
+	}
+	t := el.Text(x, y, text)
+	t.Anchor(AnchorEnd)
+	t.WithStyle(d.MakeStyle("attribution", "font-size:10px;fill:#666"))
+	return t
+}
+
+func (d *Document) footerPos() (x, y float64) {
+	if len(d.ViewBox) != 4 {
+		return footerMargin, 0
+	}
+	vb := d.ViewBox
+	return float64(vb[0]) + footerMargin, float64(vb[1]+vb[3]) - footerMargin
+}
@@ -0,0 +1,129 @@
+package svg
+
+// Checkpoint records the state of a document's element tree, as
+// returned by Document.Checkpoint, to be restored using Rollback.
+type Checkpoint struct {
+	lists    map[*Container]int
+	style    string
+	defMap   map[string]string
+	classMap map[string]string
+	nAutoIDs int
+
+	// copies of the document's lists of layers
+	// and levels of detail
+	layers      []layer
+	namedLayers []namedLayer
+	lods        []*LODGroup
+}
+
+// Checkpoint records the number of child elements of the document
+// and of each container within it, as well as the styles created so
+// far, so that elements added later, for example speculatively while
+// trying out positions of a label, can be removed using Rollback.
+// Its costs are proportional to the number of containers, styles,
+// and layers, not to the number of elements.
+func (d *Document) Checkpoint() *Checkpoint {
+	cp := &Checkpoint{
+		lists:    make(map[*Container]int),
+		style:    d.Style,
+		nAutoIDs: d.nAutoIDs,
+
+		layers:      append([]layer(nil), d.layers...),
+		namedLayers: append([]namedLayer(nil), d.namedLayers...),
+		lods:        append([]*LODGroup(nil), d.lods...),
+	}
+	if d.styles.classMap != nil {
+		cp.defMap = make(map[string]string, len(d.styles.defMap))
+		for k, v := range d.styles.defMap {
+			cp.defMap[k] = v
+		}
+		cp.classMap = make(map[string]string, len(d.styles.classMap))
+		for k, v := range d.styles.classMap {
+			cp.classMap[k] = v
+		}
+	}
+	cp.record(&d.Container)
+	return cp
+}
+
+func (cp *Checkpoint) record(c *Container) {
+	cp.lists[c] = len(c.ElemList)
+	for _, x := range c.ElemList {
+		if cc, ok := x.(container); ok {
+			cp.record(cc.container())
+		}
+	}
+}
+
+// Rollback removes the elements and styles that have been added
+// to the document since the checkpoint cp has been recorded, and
+// unregisters layers, including those created by Layer, and levels
+// of detail created meanwhile.
+// Changes to the attributes of elements that already existed at
+// the time of the checkpoint are not reverted, nor is text added
+// to existing text elements. A checkpoint may be rolled back
+// to more than once, and checkpoints may be rolled back to in
+// any order.
+func (d *Document) Rollback(cp *Checkpoint) {
+	cp.restore(&d.Container)
+	d.Style = cp.style
+	d.styles.defMap, d.styles.classMap = nil, nil
+	if cp.classMap != nil {
+		d.styles.defMap = make(map[string]string, len(cp.defMap))
+		for k, v := range cp.defMap {
+			d.styles.defMap[k] = v
+		}
+		d.styles.classMap = make(map[string]string, len(cp.classMap))
+		for k, v := range cp.classMap {
+			d.styles.classMap[k] = v
+		}
+	}
+	d.nAutoIDs = cp.nAutoIDs
+
+	// Layers registered at the time of the checkpoint may have been
+	// removed since, by rolling back to an earlier checkpoint; as
+	// their elements are gone, they must not be restored. The lists
+	// are therefore truncated to the entries they share with the
+	// checkpoint's copies.
+	n := commonPrefix(len(d.layers), len(cp.layers), func(i int) bool {
+		return d.layers[i] == cp.layers[i]
+	})
+	d.layers = d.layers[:n]
+	n = commonPrefix(len(d.namedLayers), len(cp.namedLayers), func(i int) bool {
+		return d.namedLayers[i] == cp.namedLayers[i]
+	})
+	d.namedLayers = d.namedLayers[:n]
+	n = commonPrefix(len(d.lods), len(cp.lods), func(i int) bool {
+		return d.lods[i] == cp.lods[i]
+	})
+	d.lods = d.lods[:n]
+}
+
+// commonPrefix returns the number of leading elements that
+// two lists of lengths n1 and n2 have in common, comparing
+// the elements at index i using eq.
+func commonPrefix(n1, n2 int, eq func(i int) bool) int {
+	i := 0
+	for i < n1 && i < n2 && eq(i) {
+		i++
+	}
+	return i
+}
+
+func (cp *Checkpoint) restore(c *Container) {
+	n, ok := cp.lists[c]
+	if !ok {
+		return
+	}
+	if n < len(c.ElemList) {
+		for i := n; i < len(c.ElemList); i++ {
+			c.ElemList[i] = nil
+		}
+		c.ElemList = c.ElemList[:n]
+	}
+	for _, x := range c.ElemList {
+		if cc, ok := x.(container); ok {
+			cp.restore(cc.container())
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package svg
+
+import (
+	"testing"
+)
+
+func TestRollback(t *testing.T) {
+	d := NewDocument(nil)
+	d.Rect(0, 0, 1, 1)
+	cp := d.Checkpoint()
+	g := d.Group()
+	g.Circle(0, 0, 1)
+	d.Layer("data")
+	d.Rollback(cp)
+	if n := len(d.ElemList); n != 1 {
+		t.Errorf("got %d elements, want 1", n)
+	}
+	if len(d.namedLayers) != 0 {
+		t.Error("named layer not removed")
+	}
+
+	// a checkpoint may be rolled back to more than once
+	d.Line(0, 0, 1, 1)
+	d.Rollback(cp)
+	if n := len(d.ElemList); n != 1 {
+		t.Errorf("got %d elements after second rollback, want 1", n)
+	}
+}
+
+func TestRollbackOrder(t *testing.T) {
+	d := NewDocument(nil)
+	cp1 := d.Checkpoint()
+	data := d.Layer("data")
+	d.ToggleLayer(d.Group(), "grid")
+	d.LevelOfDetail(&d.ElemList, "detail")
+	cp2 := d.Checkpoint()
+	d.Layer("notes")
+
+	d.Rollback(cp1)
+	d.Rollback(cp2)
+	if len(d.layers) != 0 || len(d.namedLayers) != 0 || len(d.lods) != 0 {
+		t.Errorf("got %d layers, %d named layers, %d levels of detail, want none",
+			len(d.layers), len(d.namedLayers), len(d.lods))
+	}
+	if n := len(d.ElemList); n != 0 {
+		t.Errorf("got %d elements, want none", n)
+	}
+	if d.Layer("data") == data {
+		t.Error("layer removed by rolling back to the first checkpoint has been restored")
+	}
+
+	// rolling back to the later checkpoint first keeps
+	// the layers registered before it
+	d = NewDocument(nil)
+	cp1 = d.Checkpoint()
+	data = d.Layer("data")
+	cp2 = d.Checkpoint()
+	d.Layer("notes")
+	d.Rollback(cp2)
+	if d.Layer("data") != data || len(d.namedLayers) != 1 {
+		t.Error("layer registered before the checkpoint not kept")
+	}
+	d.Rollback(cp1)
+	if len(d.namedLayers) != 0 {
+		t.Error("layer not removed")
+	}
+}
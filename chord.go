@@ -0,0 +1,213 @@
+package svg
+
+import (
+	"math"
+	"strings"
+)
+
+// GraphLink is an edge between two nodes of a CircularGraph.
+type GraphLink struct {
+	From, To string
+
+	// Class is added to the class attribute of the link's path.
+	Class string
+}
+
+// CircularGraph describes a graph whose nodes are placed
+// equidistantly on a circle, drawn using Container.CircularGraph.
+type CircularGraph struct {
+	Nodes []string
+	Links []GraphLink
+
+	Radius float64
+
+	// StartAngle is the angle, in degrees clockwise
+	// from the positive x axis, of the first node.
+	StartAngle float64
+
+	// NodeRadius is the radius of the circles representing the
+	// nodes; it defaults to 4.
+	NodeRadius float64
+
+	// Bundling, between 0 and 1, controls how far links are bent
+	// towards the center of the circle: with 0, links are straight
+	// lines; with 1, they are quadratic Bézier curves having the
+	// center as control point.
+	Bundling float64
+}
+
+// CircularGraph appends a group with class "circular-graph",
+// containing the graph g centered at cx, cy. Links are drawn
+// first, as paths with class "graph-link", plus the link's own
+// class; nodes are drawn as circles with class "graph-node",
+// with labels of class "graph-label" placed outside of the circle.
+// Links referring to unknown nodes are ignored.
+func (c *Container) CircularGraph(cx, cy float64, g *CircularGraph) *Container {
+	nr := g.NodeRadius
+	if nr == 0 {
+		nr = 4
+	}
+	n := len(g.Nodes)
+	angle := make(map[string]float64, n)
+	for i, name := range g.Nodes {
+		angle[name] = g.StartAngle + float64(i)*360/float64(n)
+	}
+
+	gg := c.Group()
+	gg.SetClass("circular-graph")
+	links := gg.Group()
+	for _, l := range g.Links {
+		a0, ok0 := angle[l.From]
+		a1, ok1 := angle[l.To]
+		if !ok0 || !ok1 {
+			continue
+		}
+		x0, y0 := polarPoint(cx, cy, g.Radius, a0)
+		x1, y1 := polarPoint(cx, cy, g.Radius, a1)
+		mx, my := (x0+x1)/2, (y0+y1)/2
+		qx, qy := mx+(cx-mx)*g.Bundling, my+(cy-my)*g.Bundling
+		class := "graph-link"
+		if l.Class != "" {
+			class += " " + l.Class
+		}
+		links.Path("M" + formatPoint([2]float64{x0, y0}) +
+			" Q" + formatPoint([2]float64{qx, qy}) +
+			" " + formatPoint([2]float64{x1, y1})).SetClass(class)
+	}
+	nodes := gg.Group()
+	for _, name := range g.Nodes {
+		a := angle[name]
+		x, y := polarPoint(cx, cy, g.Radius, a)
+		nodes.Circle(x, y, nr).SetClass("graph-node")
+		circularLabel(&nodes.ElemList, cx, cy, g.Radius+nr+4, a, name).SetClass("graph-label")
+	}
+	return gg
+}
+
+// circularLabel places a label at radius r and angle a, anchored
+// so that it extends away from the center.
+func circularLabel(el *ElemList, cx, cy, r, a float64, label string) *TextObject {
+	x, y := polarPoint(cx, cy, r, a)
+	t := el.Text(x, y, label)
+	t.Dy = EmUnits(0.35)
+	if cos := math.Cos(a * math.Pi / 180); cos < -1e-9 {
+		t.Anchor(AnchorEnd)
+	} else if cos < 1e-9 {
+		t.Anchor(AnchorMiddle)
+	}
+	return t
+}
+
+// Chord describes a chord diagram to be drawn using Container.Chord.
+type Chord struct {
+	// Matrix contains the flows between the groups:
+	// Matrix[i][j] is the amount flowing from group i to group j.
+	Matrix [][]float64
+	Labels []string
+
+	// Classes, if set, contains classes added to the class
+	// attributes of the groups' arcs, and of the ribbons
+	// originating from them.
+	Classes []string
+
+	Radius float64
+
+	// BandWidth is the width of the arcs representing the groups;
+	// it defaults to a tenth of the radius.
+	BandWidth float64
+
+	// PadAngle is the gap between adjacent groups, in degrees;
+	// it defaults to 2.
+	PadAngle float64
+}
+
+// Chord appends a group with class "chord", containing a chord
+// diagram centered at cx, cy. Each group is drawn as an annular
+// sector with class "chord-group", its size proportional to the sum
+// of its outgoing flows, and labeled with class "chord-label".
+// The flows between two groups are drawn as a ribbon with class
+// "chord-ribbon", whose ends are proportional to the amounts
+// flowing in each direction.
+func (c *Container) Chord(cx, cy float64, ch *Chord) *Container {
+	n := len(ch.Matrix)
+	r := ch.Radius
+	bw := ch.BandWidth
+	if bw == 0 {
+		bw = r / 10
+	}
+	pad := ch.PadAngle
+	if pad == 0 {
+		pad = 2
+	}
+	total := 0.0
+	for _, row := range ch.Matrix {
+		for _, v := range row {
+			total += v
+		}
+	}
+	k := 0.0
+	if total > 0 {
+		k = math.Max(360-float64(n)*pad, 0) / total
+	}
+	classOf := func(base string, i int) string {
+		if i < len(ch.Classes) && ch.Classes[i] != "" {
+			return base + " " + ch.Classes[i]
+		}
+		return base
+	}
+
+	// sub[i][j] holds the start and end angle of the
+	// part of group i occupied by the flow to group j
+	sub := make([][][2]float64, n)
+	groups := make([][2]float64, n)
+	a := -90.0
+	for i, row := range ch.Matrix {
+		sub[i] = make([][2]float64, n)
+		groups[i][0] = a
+		for j := 0; j < n; j++ {
+			v := 0.0
+			if j < len(row) {
+				v = row[j]
+			}
+			sub[i][j] = [2]float64{a, a + v*k}
+			a += v * k
+		}
+		groups[i][1] = a
+		a += pad
+	}
+
+	g := c.Group()
+	g.SetClass("chord")
+	ribbons := g.Group()
+	center := formatPoint([2]float64{cx, cy})
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			s, t := sub[i][j], sub[j][i]
+			if s[0] == s[1] && t[0] == t[1] {
+				continue
+			}
+			var b strings.Builder
+			x, y := polarPoint(cx, cy, r, s[0])
+			b.WriteString("M" + formatPoint([2]float64{x, y}))
+			writeArc(&b, cx, cy, r, s[0], s[1])
+			if i != j {
+				x, y = polarPoint(cx, cy, r, t[0])
+				b.WriteString(" Q" + center + " " + formatPoint([2]float64{x, y}))
+				writeArc(&b, cx, cy, r, t[0], t[1])
+			}
+			x, y = polarPoint(cx, cy, r, s[0])
+			b.WriteString(" Q" + center + " " + formatPoint([2]float64{x, y}) + " Z")
+			ribbons.Path(b.String()).SetClass(classOf("chord-ribbon", i))
+		}
+	}
+	for i, ga := range groups {
+		if ga[0] == ga[1] {
+			continue
+		}
+		g.Sector(cx, cy, r, r+bw, ga[0], ga[1]).SetClass(classOf("chord-group", i))
+		if i < len(ch.Labels) {
+			circularLabel(&g.ElemList, cx, cy, r+bw+4, (ga[0]+ga[1])/2, ch.Labels[i]).SetClass("chord-label")
+		}
+	}
+	return g
+}
@@ -0,0 +1,29 @@
+package svg
+
+import (
+	"encoding/xml"
+)
+
+type clipPath struct {
+	XMLName xml.Name `xml:"clipPath"`
+	Container
+}
+
+// PlotSeries appends a group to el that is clipped to the plot
+// area of p, so that data series drawn into it cannot overdraw
+// axes and titles outside of the area. The rectangle defining the
+// clip path is placed into a <defs> element preceding the group,
+// with an ID obtained from MakeID.
+func (d *Document) PlotSeries(el *ElemList, p *Plot) *Container {
+	id := d.autoID("plot-clip")
+	cp := new(clipPath)
+	cp.ID = id
+	a := p.Area
+	cp.Rect(a.X, a.Y, a.Width, a.Height)
+	el.Defs().append(cp)
+
+	g := el.Group()
+	g.SetClass("plot-series")
+	g.Attr("clip-path", "url(#"+id+")")
+	return g
+}
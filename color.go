@@ -0,0 +1,316 @@
+package svg
+
+import (
+	"errors"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Color is an sRGB color with an alpha channel, not premultiplied.
+// It implements color.Color, and converts into a Paint value using
+// its Paint method.
+type Color struct {
+	R, G, B, A uint8
+}
+
+// RGBA implements color.Color.
+func (c Color) RGBA() (r, g, b, a uint32) {
+	return color.NRGBA{c.R, c.G, c.B, c.A}.RGBA()
+}
+
+// ColorOf converts c into a Color.
+func ColorOf(c color.Color) Color {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return Color{n.R, n.G, n.B, n.A}
+}
+
+// HSL returns the opaque color having hue h, in degrees, and
+// saturation s and lightness l, between 0 and 1.
+func HSL(h, s, l float64) Color {
+	c := hslToRGB(math.Mod(math.Mod(h, 360)+360, 360), clamp01(s), clamp01(l))
+	return c.color(1)
+}
+
+// NamedColor returns the color of one of the named colors
+// defined by SVG, like "steelblue".
+func NamedColor(name string) (Color, bool) {
+	v, ok := svgColors[strings.ToLower(name)]
+	if !ok {
+		return Color{}, false
+	}
+	return Color{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}, true
+}
+
+// ParseColor parses a CSS color, which may be given as one of
+// the named colors of SVG, in hexadecimal notation, like #rgb,
+// or #rrggbb, with an optional alpha component, or as rgb(),
+// rgba(), hsl(), or hsla() function.
+func ParseColor(s string) (Color, error) {
+	c, alpha, ok := parseRGBA(s)
+	if !ok {
+		return Color{}, errors.New("svg: invalid color: " + s)
+	}
+	return c.color(alpha), nil
+}
+
+// Paint returns c in hexadecimal notation, or, if it is not
+// opaque, as rgba() function.
+func (c Color) Paint() Paint {
+	if c.A == 255 {
+		return RGB(c.R, c.G, c.B)
+	}
+	return Paint("rgba(" + strconv.Itoa(int(c.R)) + "," + strconv.Itoa(int(c.G)) + "," +
+		strconv.Itoa(int(c.B)) + "," + formatFloat(math.Round(float64(c.A)/255*1000)/1000) + ")")
+}
+
+// Decl returns a CSS declaration of property, like "fill", set to c.
+func (c Color) Decl(property string) string {
+	return c.Paint().Decl(property)
+}
+
+// color converts c, with the specified alpha
+// between 0 and 1, into a Color.
+func (c rgb) color(alpha float64) Color {
+	conv := func(f float64) uint8 {
+		return uint8(math.Round(clamp01(f) * 255))
+	}
+	return Color{conv(c.r), conv(c.g), conv(c.b), conv(alpha)}
+}
+
+func clamp01(f float64) float64 {
+	return math.Max(0, math.Min(1, f))
+}
+
+// parseRGBA parses a CSS color in one of the notations
+// supported by ParseColor, returning its alpha channel separately.
+func parseRGBA(s string) (c rgb, alpha float64, ok bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if v, ok := svgColors[s]; ok {
+		return rgb{float64(v>>16) / 255, float64(v>>8&0xff) / 255, float64(v&0xff) / 255}, 1, true
+	}
+	if strings.HasPrefix(s, "#") {
+		h := s[1:]
+		if len(h) == 3 || len(h) == 4 {
+			long := make([]byte, 0, 8)
+			for i := 0; i < len(h); i++ {
+				long = append(long, h[i], h[i])
+			}
+			h = string(long)
+		}
+		if len(h) == 6 {
+			h += "ff"
+		}
+		if len(h) != 8 {
+			return rgb{}, 0, false
+		}
+		v, err := strconv.ParseUint(h, 16, 32)
+		if err != nil {
+			return rgb{}, 0, false
+		}
+		return rgb{float64(v>>24) / 255, float64(v>>16&0xff) / 255, float64(v>>8&0xff) / 255}, float64(v&0xff) / 255, true
+	}
+	i := strings.IndexByte(s, '(')
+	if i == -1 || !strings.HasSuffix(s, ")") {
+		return rgb{}, 0, false
+	}
+	fn := s[:i]
+	args := strings.FieldsFunc(s[i+1:len(s)-1], func(r rune) bool {
+		return r == ',' || r == ' ' || r == '/'
+	})
+	if len(args) != 3 && len(args) != 4 {
+		return rgb{}, 0, false
+	}
+	// value parses an argument, a number,
+	// or a percentage, which is scaled to 0..1
+	value := func(a string, scale float64) (float64, bool) {
+		if strings.HasSuffix(a, "%") {
+			a, scale = a[:len(a)-1], 100
+		}
+		f, err := strconv.ParseFloat(a, 64)
+		return f / scale, err == nil
+	}
+	alpha = 1
+	if len(args) == 4 {
+		if alpha, ok = value(args[3], 1); !ok {
+			return rgb{}, 0, false
+		}
+		alpha = clamp01(alpha)
+	}
+	var comp [3]float64
+	switch fn {
+	case "rgb", "rgba":
+		for i := range comp {
+			if comp[i], ok = value(args[i], 255); !ok {
+				return rgb{}, 0, false
+			}
+		}
+		return rgb{comp[0], comp[1], comp[2]}, alpha, true
+	case "hsl", "hsla":
+		h, err := strconv.ParseFloat(strings.TrimSuffix(args[0], "deg"), 64)
+		if err != nil {
+			return rgb{}, 0, false
+		}
+		for i := 1; i < 3; i++ {
+			if comp[i], ok = value(args[i], 1); !ok {
+				return rgb{}, 0, false
+			}
+		}
+		h = math.Mod(math.Mod(h, 360)+360, 360)
+		return hslToRGB(h, clamp01(comp[1]), clamp01(comp[2])), alpha, true
+	}
+	return rgb{}, 0, false
+}
+
+// svgColors contains the named colors of SVG, see
+// https://www.w3.org/TR/SVG11/types.html#ColorKeywords
+var svgColors = map[string]uint32{
+	"aliceblue":            0xf0f8ff,
+	"antiquewhite":         0xfaebd7,
+	"aqua":                 0x00ffff,
+	"aquamarine":           0x7fffd4,
+	"azure":                0xf0ffff,
+	"beige":                0xf5f5dc,
+	"bisque":               0xffe4c4,
+	"black":                0x000000,
+	"blanchedalmond":       0xffebcd,
+	"blue":                 0x0000ff,
+	"blueviolet":           0x8a2be2,
+	"brown":                0xa52a2a,
+	"burlywood":            0xdeb887,
+	"cadetblue":            0x5f9ea0,
+	"chartreuse":           0x7fff00,
+	"chocolate":            0xd2691e,
+	"coral":                0xff7f50,
+	"cornflowerblue":       0x6495ed,
+	"cornsilk":             0xfff8dc,
+	"crimson":              0xdc143c,
+	"cyan":                 0x00ffff,
+	"darkblue":             0x00008b,
+	"darkcyan":             0x008b8b,
+	"darkgoldenrod":        0xb8860b,
+	"darkgray":             0xa9a9a9,
+	"darkgreen":            0x006400,
+	"darkgrey":             0xa9a9a9,
+	"darkkhaki":            0xbdb76b,
+	"darkmagenta":          0x8b008b,
+	"darkolivegreen":       0x556b2f,
+	"darkorange":           0xff8c00,
+	"darkorchid":           0x9932cc,
+	"darkred":              0x8b0000,
+	"darksalmon":           0xe9967a,
+	"darkseagreen":         0x8fbc8f,
+	"darkslateblue":        0x483d8b,
+	"darkslategray":        0x2f4f4f,
+	"darkslategrey":        0x2f4f4f,
+	"darkturquoise":        0x00ced1,
+	"darkviolet":           0x9400d3,
+	"deeppink":             0xff1493,
+	"deepskyblue":          0x00bfff,
+	"dimgray":              0x696969,
+	"dimgrey":              0x696969,
+	"dodgerblue":           0x1e90ff,
+	"firebrick":            0xb22222,
+	"floralwhite":          0xfffaf0,
+	"forestgreen":          0x228b22,
+	"fuchsia":              0xff00ff,
+	"gainsboro":            0xdcdcdc,
+	"ghostwhite":           0xf8f8ff,
+	"gold":                 0xffd700,
+	"goldenrod":            0xdaa520,
+	"gray":                 0x808080,
+	"grey":                 0x808080,
+	"green":                0x008000,
+	"greenyellow":          0xadff2f,
+	"honeydew":             0xf0fff0,
+	"hotpink":              0xff69b4,
+	"indianred":            0xcd5c5c,
+	"indigo":               0x4b0082,
+	"ivory":                0xfffff0,
+	"khaki":                0xf0e68c,
+	"lavender":             0xe6e6fa,
+	"lavenderblush":        0xfff0f5,
+	"lawngreen":            0x7cfc00,
+	"lemonchiffon":         0xfffacd,
+	"lightblue":            0xadd8e6,
+	"lightcoral":           0xf08080,
+	"lightcyan":            0xe0ffff,
+	"lightgoldenrodyellow": 0xfafad2,
+	"lightgray":            0xd3d3d3,
+	"lightgreen":           0x90ee90,
+	"lightgrey":            0xd3d3d3,
+	"lightpink":            0xffb6c1,
+	"lightsalmon":          0xffa07a,
+	"lightseagreen":        0x20b2aa,
+	"lightskyblue":         0x87cefa,
+	"lightslategray":       0x778899,
+	"lightslategrey":       0x778899,
+	"lightsteelblue":       0xb0c4de,
+	"lightyellow":          0xffffe0,
+	"lime":                 0x00ff00,
+	"limegreen":            0x32cd32,
+	"linen":                0xfaf0e6,
+	"magenta":              0xff00ff,
+	"maroon":               0x800000,
+	"mediumaquamarine":     0x66cdaa,
+	"mediumblue":           0x0000cd,
+	"mediumorchid":         0xba55d3,
+	"mediumpurple":         0x9370db,
+	"mediumseagreen":       0x3cb371,
+	"mediumslateblue":      0x7b68ee,
+	"mediumspringgreen":    0x00fa9a,
+	"mediumturquoise":      0x48d1cc,
+	"mediumvioletred":      0xc71585,
+	"midnightblue":         0x191970,
+	"mintcream":            0xf5fffa,
+	"mistyrose":            0xffe4e1,
+	"moccasin":             0xffe4b5,
+	"navajowhite":          0xffdead,
+	"navy":                 0x000080,
+	"oldlace":              0xfdf5e6,
+	"olive":                0x808000,
+	"olivedrab":            0x6b8e23,
+	"orange":               0xffa500,
+	"orangered":            0xff4500,
+	"orchid":               0xda70d6,
+	"palegoldenrod":        0xeee8aa,
+	"palegreen":            0x98fb98,
+	"paleturquoise":        0xafeeee,
+	"palevioletred":        0xdb7093,
+	"papayawhip":           0xffefd5,
+	"peachpuff":            0xffdab9,
+	"peru":                 0xcd853f,
+	"pink":                 0xffc0cb,
+	"plum":                 0xdda0dd,
+	"powderblue":           0xb0e0e6,
+	"purple":               0x800080,
+	"red":                  0xff0000,
+	"rosybrown":            0xbc8f8f,
+	"royalblue":            0x4169e1,
+	"saddlebrown":          0x8b4513,
+	"salmon":               0xfa8072,
+	"sandybrown":           0xf4a460,
+	"seagreen":             0x2e8b57,
+	"seashell":             0xfff5ee,
+	"sienna":               0xa0522d,
+	"silver":               0xc0c0c0,
+	"skyblue":              0x87ceeb,
+	"slateblue":            0x6a5acd,
+	"slategray":            0x708090,
+	"slategrey":            0x708090,
+	"snow":                 0xfffafa,
+	"springgreen":          0x00ff7f,
+	"steelblue":            0x4682b4,
+	"tan":                  0xd2b48c,
+	"teal":                 0x008080,
+	"thistle":              0xd8bfd8,
+	"tomato":               0xff6347,
+	"turquoise":            0x40e0d0,
+	"violet":               0xee82ee,
+	"wheat":                0xf5deb3,
+	"white":                0xffffff,
+	"whitesmoke":           0xf5f5f5,
+	"yellow":               0xffff00,
+	"yellowgreen":          0x9acd32,
+}
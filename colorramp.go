@@ -0,0 +1,268 @@
+package svg
+
+import (
+	"math"
+)
+
+// rgb is a color with sRGB components between 0 and 1.
+type rgb struct {
+	r, g, b float64
+}
+
+// parseColor parses the sRGB color of p, which may be given in
+// any of the notations supported by ParseColor.
+// The alpha channel is ignored.
+func parseColor(p Paint) (rgb, bool) {
+	c, _, ok := parseRGBA(string(p.Fallback()))
+	return c, ok
+}
+
+func (c rgb) paint() Paint {
+	conv := func(f float64) uint8 {
+		return uint8(math.Round(math.Max(0, math.Min(1, f)) * 255))
+	}
+	return RGB(conv(c.r), conv(c.g), conv(c.b))
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// lerpHue interpolates between two hues, in degrees,
+// along the shorter way around the circle.
+func lerpHue(a, b, t float64) float64 {
+	d := math.Mod(b-a+540, 360) - 180
+	return math.Mod(a+d*t+360, 360)
+}
+
+// interpolate parses a and b, and interpolates them using f.
+// If a color cannot be parsed, the nearer of a and b is returned.
+func interpolate(a, b Paint, t float64, f func(ca, cb rgb, t float64) rgb) Paint {
+	ca, okA := parseColor(a)
+	cb, okB := parseColor(b)
+	if !okA || !okB {
+		if t < 0.5 {
+			return a
+		}
+		return b
+	}
+	return f(ca, cb, t).paint()
+}
+
+// Lerp interpolates linearly between the sRGB components of the
+// colors a and b, with t between 0 and 1. Colors may be given in
+// the notations supported by ParseColor; if either color cannot
+// be parsed, the one nearer to t is returned. The result is in hexadecimal notation.
+func Lerp(a, b Paint, t float64) Paint {
+	return interpolate(a, b, t, func(ca, cb rgb, t float64) rgb {
+		return rgb{lerp(ca.r, cb.r, t), lerp(ca.g, cb.g, t), lerp(ca.b, cb.b, t)}
+	})
+}
+
+// LerpHSL interpolates between a and b like Lerp, but within the
+// HSL color space, taking the shorter way around the hue circle.
+func LerpHSL(a, b Paint, t float64) Paint {
+	return interpolate(a, b, t, func(ca, cb rgb, t float64) rgb {
+		ha, sa, la := ca.hsl()
+		hb, sb, lb := cb.hsl()
+		if sa == 0 {
+			ha = hb
+		} else if sb == 0 {
+			hb = ha
+		}
+		return hslToRGB(lerpHue(ha, hb, t), lerp(sa, sb, t), lerp(la, lb, t))
+	})
+}
+
+// LerpHCL interpolates between a and b like Lerp, but within the
+// perceptually uniform HCL (CIE LCh) color space, resulting in
+// ramps of even perceived brightness steps.
+func LerpHCL(a, b Paint, t float64) Paint {
+	return interpolate(a, b, t, func(ca, cb rgb, t float64) rgb {
+		ha, cha, la := ca.hcl()
+		hb, chb, lb := cb.hcl()
+		if cha < 1e-4 {
+			ha = hb
+		} else if chb < 1e-4 {
+			hb = ha
+		}
+		return hclToRGB(lerpHue(ha, hb, t), lerp(cha, chb, t), lerp(la, lb, t))
+	})
+}
+
+// Ramp is a multi-stop color ramp.
+type Ramp struct {
+	Stops []Paint
+
+	// Positions, if set, contains the positions of the stops,
+	// in increasing order; by default, stops are distributed
+	// evenly between 0 and 1.
+	Positions []float64
+
+	// Interpolate is used to interpolate between neighbouring
+	// stops; it defaults to LerpHCL.
+	Interpolate func(a, b Paint, t float64) Paint
+}
+
+// NewRamp returns a ramp with evenly distributed stops.
+func NewRamp(stops ...Paint) *Ramp {
+	return &Ramp{Stops: stops}
+}
+
+func (r *Ramp) position(i int) float64 {
+	if i < len(r.Positions) {
+		return r.Positions[i]
+	}
+	if len(r.Stops) < 2 {
+		return 0
+	}
+	return float64(i) / float64(len(r.Stops)-1)
+}
+
+// At returns the color of the ramp at position t;
+// values outside of the ramp are clamped.
+func (r *Ramp) At(t float64) Paint {
+	n := len(r.Stops)
+	switch {
+	case n == 0:
+		return ""
+	case t <= r.position(0):
+		return r.Stops[0]
+	case t >= r.position(n-1):
+		return r.Stops[n-1]
+	}
+	f := r.Interpolate
+	if f == nil {
+		f = LerpHCL
+	}
+	i := 1
+	for i < n-1 && t > r.position(i) {
+		i++
+	}
+	p0, p1 := r.position(i-1), r.position(i)
+	if p1 <= p0 {
+		return r.Stops[i]
+	}
+	return f(r.Stops[i-1], r.Stops[i], (t-p0)/(p1-p0))
+}
+
+// Colors returns n colors sampled evenly from the ramp,
+// including both ends.
+func (r *Ramp) Colors(n int) []Paint {
+	colors := make([]Paint, n)
+	p0, p1 := r.position(0), r.position(len(r.Stops)-1)
+	for i := range colors {
+		t := p0
+		if n > 1 {
+			t = lerp(p0, p1, float64(i)/float64(n-1))
+		}
+		colors[i] = r.At(t)
+	}
+	return colors
+}
+
+// hsl returns hue, in degrees, saturation, and lightness of c.
+func (c rgb) hsl() (h, s, l float64) {
+	max := math.Max(c.r, math.Max(c.g, c.b))
+	min := math.Min(c.r, math.Min(c.g, c.b))
+	l = (max + min) / 2
+	d := max - min
+	if d == 0 {
+		return 0, 0, l
+	}
+	s = d / (1 - math.Abs(2*l-1))
+	switch max {
+	case c.r:
+		h = math.Mod((c.g-c.b)/d+6, 6)
+	case c.g:
+		h = (c.b-c.r)/d + 2
+	default:
+		h = (c.r-c.g)/d + 4
+	}
+	return h * 60, s, l
+}
+
+func hslToRGB(h, s, l float64) rgb {
+	c := (1 - math.Abs(2*l-1)) * s
+	hp := h / 60
+	x := c * (1 - math.Abs(math.Mod(hp, 2)-1))
+	var r, g, b float64
+	switch {
+	case hp < 1:
+		r, g = c, x
+	case hp < 2:
+		r, g = x, c
+	case hp < 3:
+		g, b = c, x
+	case hp < 4:
+		g, b = x, c
+	case hp < 5:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	m := l - c/2
+	return rgb{r + m, g + m, b + m}
+}
+
+// D65 white point
+const (
+	whiteX = 0.95047
+	whiteY = 1.0
+	whiteZ = 1.08883
+)
+
+func srgbToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) float64 {
+	if v <= 0.0031308 {
+		return 12.92 * v
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+func labF(t float64) float64 {
+	if t > 216.0/24389 {
+		return math.Cbrt(t)
+	}
+	return t*24389/27/116 + 16.0/116
+}
+
+func labFInv(t float64) float64 {
+	if t3 := t * t * t; t3 > 216.0/24389 {
+		return t3
+	}
+	return (116*t - 16) * 27 / 24389
+}
+
+// hcl returns hue, in degrees, chroma, and luminance
+// of c in the CIE LCh(ab) color space.
+func (c rgb) hcl() (h, chroma, l float64) {
+	r, g, b := srgbToLinear(c.r), srgbToLinear(c.g), srgbToLinear(c.b)
+	x := 0.4124564*r + 0.3575761*g + 0.1804375*b
+	y := 0.2126729*r + 0.7151522*g + 0.0721750*b
+	z := 0.0193339*r + 0.1191920*g + 0.9503041*b
+	fx, fy, fz := labF(x/whiteX), labF(y/whiteY), labF(z/whiteZ)
+	l = 116*fy - 16
+	la, lb := 500*(fx-fy), 200*(fy-fz)
+	h = math.Mod(math.Atan2(lb, la)*180/math.Pi+360, 360)
+	return h, math.Hypot(la, lb), l
+}
+
+func hclToRGB(h, chroma, l float64) rgb {
+	sin, cos := math.Sincos(h * math.Pi / 180)
+	la, lb := chroma*cos, chroma*sin
+	fy := (l + 16) / 116
+	x := whiteX * labFInv(fy+la/500)
+	y := whiteY * labFInv(fy)
+	z := whiteZ * labFInv(fy-lb/200)
+	r := 3.2404542*x - 1.5371385*y - 0.4985314*z
+	g := -0.9692660*x + 1.8760108*y + 0.0415560*z
+	b := 0.0556434*x - 0.2040259*y + 1.0572252*z
+	return rgb{linearToSRGB(r), linearToSRGB(g), linearToSRGB(b)}
+}
@@ -0,0 +1,104 @@
+package svg
+
+import (
+	"encoding/xml"
+	"math"
+)
+
+// CompactPoints is a list of 2D coordinates, stored as consecutive
+// x and y values in a single slice, avoiding the overhead of
+// Points for polylines with millions of points. It marshals,
+// like Points, into a list of space separated pairs of
+// comma separated numbers, without building intermediate strings.
+type CompactPoints []float64
+
+func (pts CompactPoints) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	for _, f := range pts {
+		if !validNumber(f) {
+			return xml.Attr{}, &NumberError{Attr: name.Local, Value: f}
+		}
+	}
+	buf := pts.appendAttr(make([]byte, 0, len(pts)*4))
+	return xml.Attr{Name: name, Value: string(buf)}, nil
+}
+
+func (pts CompactPoints) appendAttr(buf []byte) []byte {
+	for i := 0; i+1 < len(pts); i += 2 {
+		if i != 0 {
+			buf = append(buf, ' ')
+		}
+		buf = appendFloat(buf, pts[i])
+		buf = append(buf, ',')
+		buf = appendFloat(buf, pts[i+1])
+	}
+	return buf
+}
+
+// Add appends a point.
+func (pts *CompactPoints) Add(x, y float64) {
+	*pts = append(*pts, x, y)
+}
+
+// Len returns the number of points.
+func (pts CompactPoints) Len() int {
+	return len(pts) / 2
+}
+
+// At returns the i-th point.
+func (pts CompactPoints) At(i int) (x, y float64) {
+	return pts[2*i], pts[2*i+1]
+}
+
+// CompactPolyLine is a <polyline> element storing its points
+// as CompactPoints.
+type CompactPolyLine struct {
+	XMLName xml.Name      `xml:"polyline"`
+	Points  CompactPoints `xml:"points,attr"`
+	ShapeObject
+}
+
+// CompactPolyLine adds a polyline element to the ElemList, with
+// points stored compactly, which is suitable for long tracks or
+// traces. The slice pts, containing alternating x and y values,
+// is used directly, not copied.
+func (el *ElemList) CompactPolyLine(pts []float64) *CompactPolyLine {
+	line := &CompactPolyLine{Points: pts}
+	el.append(line)
+	return line
+}
+
+// PreAlloc preallocates memory for n points.
+func (line *CompactPolyLine) PreAlloc(n int) *CompactPolyLine {
+	if line.Points == nil {
+		line.Points = make(CompactPoints, 0, 2*n)
+	}
+	return line
+}
+
+func (pts CompactPoints) transform(m affine) {
+	for i := 0; i+1 < len(pts); i += 2 {
+		p := m.apply([2]float64{pts[i], pts[i+1]})
+		pts[i], pts[i+1] = p[0], p[1]
+	}
+}
+
+func (pts CompactPoints) points() Points {
+	list := make(Points, pts.Len())
+	for i := range list {
+		list[i][0], list[i][1] = pts.At(i)
+	}
+	return list
+}
+
+// bbox returns the minimum and maximum coordinates of the points,
+// transformed by m.
+func (pts CompactPoints) bbox(m affine) (min, max [2]float64, ok bool) {
+	min = [2]float64{math.Inf(1), math.Inf(1)}
+	max = [2]float64{math.Inf(-1), math.Inf(-1)}
+	for i := 0; i+1 < len(pts); i += 2 {
+		p := m.apply([2]float64{pts[i], pts[i+1]})
+		min[0], min[1] = math.Min(min[0], p[0]), math.Min(min[1], p[1])
+		max[0], max[1] = math.Max(max[0], p[0]), math.Max(max[1], p[1])
+	}
+	return min, max, len(pts) >= 2
+}
@@ -0,0 +1,202 @@
+package svg
+
+import (
+	"math"
+	"strings"
+)
+
+// ConnectorKind selects the routing of a connector.
+type ConnectorKind int
+
+const (
+	// Straight connectors are drawn as straight lines.
+	Straight ConnectorKind = iota
+
+	// SCurve connectors are drawn as cubic Bézier curves leaving
+	// and entering the end points horizontally, or, if that would
+	// cross one of the rectangles to avoid, vertically.
+	SCurve
+
+	// Elbow connectors consist of horizontal and vertical lines,
+	// joined by rounded corners.
+	Elbow
+)
+
+// Connector describes the routing of a connector, or leader line,
+// drawn using ElemList.Connect.
+type Connector struct {
+	Kind ConnectorKind
+
+	// Radius of rounded corners of elbow connectors;
+	// it defaults to 6.
+	Radius float64
+
+	// Avoid contains rectangles the connector should not cross,
+	// like labels or other crowded regions of a drawing. If the
+	// preferred route of a connector would cross one of them,
+	// alternative routes are tried, falling back to elbow
+	// connectors. The rectangles must not contain the end points.
+	Avoid []BBox
+
+	// Margin is the clearance kept around the rectangles to
+	// avoid; it defaults to 4.
+	Margin float64
+}
+
+// Connect appends a path with class "connector", connecting
+// x1, y1 and x2, y2 as described by c, which may be nil,
+// resulting in a straight line.
+func (el *ElemList) Connect(x1, y1, x2, y2 float64, c *Connector) *ShapeObject {
+	if c == nil {
+		c = &Connector{}
+	}
+	p1 := [2]float64{x1, y1}
+	p2 := [2]float64{x2, y2}
+	var d string
+	switch c.Kind {
+	case Straight:
+		if !c.collides([][2]float64{p1, p2}) {
+			d = "M" + formatPoint(p1) + " L" + formatPoint(p2)
+		}
+	case SCurve:
+		mx := (x1 + x2) / 2
+		my := (y1 + y2) / 2
+		for _, ctrl := range [][2][2]float64{
+			{{mx, y1}, {mx, y2}},
+			{{x1, my}, {x2, my}},
+		} {
+			segs := []pathSeg{
+				{cmd: 'M', pts: [3][2]float64{p1}},
+				{cmd: 'C', pts: [3][2]float64{ctrl[0], ctrl[1], p2}},
+			}
+			if !c.collides(flattenPath(segs, 0.5)[0].pts) {
+				d = formatPath(segs)
+				break
+			}
+		}
+	}
+	if d == "" {
+		d = c.elbowPath(c.elbowRoute(p1, p2))
+	}
+	p := el.Path(d)
+	p.SetClass("connector")
+	return p
+}
+
+func formatPoint(pt [2]float64) string {
+	return formatFloat(pt[0]) + "," + formatFloat(pt[1])
+}
+
+func (c *Connector) margin() float64 {
+	if c.Margin == 0 {
+		return 4
+	}
+	return c.Margin
+}
+
+// elbowRoute returns the shortest route consisting of horizontal
+// and vertical lines that does not cross the rectangles to avoid.
+func (c *Connector) elbowRoute(p1, p2 [2]float64) [][2]float64 {
+	m := c.margin()
+	xs := []float64{(p1[0] + p2[0]) / 2}
+	ys := []float64{(p1[1] + p2[1]) / 2}
+	for _, r := range c.Avoid {
+		xs = append(xs, r.X-m, r.X+r.Width+m)
+		ys = append(ys, r.Y-m, r.Y+r.Height+m)
+	}
+	candidates := [][][2]float64{
+		{p1, {p2[0], p1[1]}, p2},
+		{p1, {p1[0], p2[1]}, p2},
+	}
+	for _, x := range xs {
+		candidates = append(candidates, [][2]float64{p1, {x, p1[1]}, {x, p2[1]}, p2})
+	}
+	for _, y := range ys {
+		candidates = append(candidates, [][2]float64{p1, {p1[0], y}, {p2[0], y}, p2})
+	}
+	best := candidates[2]
+	bestLen := math.Inf(1)
+	for _, route := range candidates {
+		if c.collides(route) {
+			continue
+		}
+		l := 0.0
+		for i := 1; i < len(route); i++ {
+			l += math.Abs(route[i][0]-route[i-1][0]) + math.Abs(route[i][1]-route[i-1][1])
+		}
+		// prefer fewer corners
+		l += float64(len(route)) * 1e-6
+		if l < bestLen {
+			best, bestLen = route, l
+		}
+	}
+	return best
+}
+
+// elbowPath converts a route into path data, with rounded corners.
+func (c *Connector) elbowPath(route [][2]float64) string {
+	r := c.Radius
+	if r == 0 {
+		r = 6
+	}
+	var b strings.Builder
+	b.WriteString("M" + formatPoint(route[0]))
+	for i := 1; i < len(route)-1; i++ {
+		prev, v, next := route[i-1], route[i], route[i+1]
+		lin := dist(v[0]-prev[0], v[1]-prev[1])
+		lout := dist(next[0]-v[0], next[1]-v[1])
+		if lin == 0 || lout == 0 {
+			continue
+		}
+		rr := math.Min(r, math.Min(lin, lout)/2)
+		a := [2]float64{v[0] - (v[0]-prev[0])/lin*rr, v[1] - (v[1]-prev[1])/lin*rr}
+		e := [2]float64{v[0] + (next[0]-v[0])/lout*rr, v[1] + (next[1]-v[1])/lout*rr}
+		b.WriteString(" L" + formatPoint(a) + " Q" + formatPoint(v) + " " + formatPoint(e))
+	}
+	b.WriteString(" L" + formatPoint(route[len(route)-1]))
+	return b.String()
+}
+
+// collides reports whether the polyline pts crosses one
+// of the rectangles to avoid.
+func (c *Connector) collides(pts [][2]float64) bool {
+	m := c.margin() / 2
+	for _, r := range c.Avoid {
+		r = BBox{X: r.X - m, Y: r.Y - m, Width: r.Width + 2*m, Height: r.Height + 2*m}
+		for i := 1; i < len(pts); i++ {
+			if segmentIntersectsBox(pts[i-1], pts[i], r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// segmentIntersectsBox reports whether the line segment p→q
+// intersects the box, using Liang-Barsky clipping.
+func segmentIntersectsBox(p, q [2]float64, r BBox) bool {
+	t0, t1 := 0.0, 1.0
+	dx, dy := q[0]-p[0], q[1]-p[1]
+	clip := func(den, num float64) bool {
+		if den == 0 {
+			return num >= 0
+		}
+		t := num / den
+		if den > 0 {
+			if t < t0 {
+				return false
+			}
+			t1 = math.Min(t1, t)
+		} else {
+			if t > t1 {
+				return false
+			}
+			t0 = math.Max(t0, t)
+		}
+		return t0 <= t1
+	}
+	return clip(-dx, p[0]-r.X) &&
+		clip(dx, r.X+r.Width-p[0]) &&
+		clip(-dy, p[1]-r.Y) &&
+		clip(dy, r.Y+r.Height-p[1])
+}
@@ -0,0 +1,272 @@
+package svg
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Contours describes isolines of a two-dimensional scalar field,
+// to be drawn using Container.Contours.
+type Contours struct {
+	// Grid contains the field's values, sampled at equidistant
+	// points: Grid[i][j] is the value in row i, column j.
+	// All rows must have the same length.
+	// Cells containing NaN values are skipped.
+	Grid [][]float64
+
+	// Levels are the values for which isolines are created.
+	Levels []float64
+
+	// Smooth, if set, makes the lines pass through the
+	// computed points as Catmull-Rom splines, converted to
+	// cubic Bézier curves, instead of as polylines.
+	Smooth bool
+}
+
+// Contours appends a group with class "contours", containing
+// the isolines of ct, computed using the marching squares
+// algorithm, with the grid stretched to the rectangle at x, y of
+// the specified size. For each level, a path is created, with
+// classes "contour" and "contour-N", where N is the level's index.
+// Lines that don't touch the grid's border are closed.
+func (c *Container) Contours(x, y, w, h float64, ct *Contours) *Container {
+	g := c.Group()
+	g.SetClass("contours")
+	rows := len(ct.Grid)
+	if rows < 2 || len(ct.Grid[0]) < 2 {
+		return g
+	}
+	cols := len(ct.Grid[0])
+	sx := w / float64(cols-1)
+	sy := h / float64(rows-1)
+	for i, level := range ct.Levels {
+		var b strings.Builder
+		for _, line := range isolines(ct.Grid, level) {
+			pts := line.pts
+			for k := range pts {
+				pts[k] = [2]float64{x + pts[k][0]*sx, y + pts[k][1]*sy}
+			}
+			if b.Len() != 0 {
+				b.WriteString(" ")
+			}
+			if ct.Smooth {
+				writeSpline(&b, pts, line.closed)
+			} else {
+				writePolyline(&b, pts, line.closed)
+			}
+		}
+		if b.Len() == 0 {
+			continue
+		}
+		g.Path(b.String()).SetClass("contour contour-" + strconv.Itoa(i))
+	}
+	return g
+}
+
+// gridEdge identifies an edge between two neighbouring grid points;
+// vertical edges connect (r, c) and (r+1, c), horizontal
+// edges connect (r, c) and (r, c+1).
+type gridEdge struct {
+	r, c int
+	vert bool
+}
+
+// isolines returns the lines where the field in grid crosses level,
+// with coordinates in units of grid columns and rows. For closed
+// lines, the first point is not repeated at the end.
+func isolines(grid [][]float64, level float64) []subpath {
+	var segs [][2]gridEdge
+	for r := 0; r < len(grid)-1; r++ {
+		for c := 0; c < len(grid[r])-1 && c < len(grid[r+1])-1; c++ {
+			tl, tr := grid[r][c], grid[r][c+1]
+			bl, br := grid[r+1][c], grid[r+1][c+1]
+			if math.IsNaN(tl + tr + bl + br) {
+				continue
+			}
+			idx := 0
+			if tl >= level {
+				idx |= 8
+			}
+			if tr >= level {
+				idx |= 4
+			}
+			if br >= level {
+				idx |= 2
+			}
+			if bl >= level {
+				idx |= 1
+			}
+			top := gridEdge{r, c, false}
+			bottom := gridEdge{r + 1, c, false}
+			left := gridEdge{r, c, true}
+			right := gridEdge{r, c + 1, true}
+			add := func(a, b gridEdge) {
+				segs = append(segs, [2]gridEdge{a, b})
+			}
+			center := (tl+tr+bl+br)/4 >= level
+			switch idx {
+			case 1, 14:
+				add(left, bottom)
+			case 2, 13:
+				add(bottom, right)
+			case 3, 12:
+				add(left, right)
+			case 4, 11:
+				add(top, right)
+			case 6, 9:
+				add(top, bottom)
+			case 7, 8:
+				add(left, top)
+			case 5:
+				if center {
+					add(left, top)
+					add(bottom, right)
+				} else {
+					add(top, right)
+					add(left, bottom)
+				}
+			case 10:
+				if center {
+					add(top, right)
+					add(left, bottom)
+				} else {
+					add(left, top)
+					add(bottom, right)
+				}
+			}
+		}
+	}
+
+	point := func(e gridEdge) [2]float64 {
+		a := grid[e.r][e.c]
+		var b float64
+		if e.vert {
+			b = grid[e.r+1][e.c]
+		} else {
+			b = grid[e.r][e.c+1]
+		}
+		t := 0.5
+		if a != b {
+			t = (level - a) / (b - a)
+		}
+		if e.vert {
+			return [2]float64{float64(e.c), float64(e.r) + t}
+		}
+		return [2]float64{float64(e.c) + t, float64(e.r)}
+	}
+
+	// join segments sharing an edge into lines
+	adj := make(map[gridEdge][]int, 2*len(segs))
+	for i, s := range segs {
+		adj[s[0]] = append(adj[s[0]], i)
+		adj[s[1]] = append(adj[s[1]], i)
+	}
+	used := make([]bool, len(segs))
+	walk := func(i int, e gridEdge) []gridEdge {
+		chain := []gridEdge{e}
+		for {
+			used[i] = true
+			s := segs[i]
+			if s[0] == e {
+				e = s[1]
+			} else {
+				e = s[0]
+			}
+			chain = append(chain, e)
+			next := -1
+			for _, j := range adj[e] {
+				if !used[j] {
+					next = j
+					break
+				}
+			}
+			if next == -1 {
+				return chain
+			}
+			i = next
+		}
+	}
+	var lines []subpath
+	emit := func(chain []gridEdge, closed bool) {
+		if closed {
+			chain = chain[:len(chain)-1]
+		}
+		pts := make([][2]float64, 0, len(chain))
+		for _, e := range chain {
+			// values equal to level at grid points
+			// result in duplicate points
+			pt := point(e)
+			if n := len(pts); n == 0 || pts[n-1] != pt {
+				pts = append(pts, pt)
+			}
+		}
+		if len(pts) > 1 {
+			lines = append(lines, subpath{pts: pts, closed: closed})
+		}
+	}
+	// open lines start at the grid's border, or next to NaN cells
+	for i, s := range segs {
+		for _, e := range s {
+			if !used[i] && len(adj[e]) == 1 {
+				emit(walk(i, e), false)
+			}
+		}
+	}
+	for i, s := range segs {
+		if !used[i] {
+			chain := walk(i, s[0])
+			emit(chain, chain[len(chain)-1] == chain[0])
+		}
+	}
+	return lines
+}
+
+func writePolyline(b *strings.Builder, pts [][2]float64, closed bool) {
+	for i, pt := range pts {
+		if i == 0 {
+			b.WriteString("M")
+		} else {
+			b.WriteString(" L")
+		}
+		b.WriteString(formatPoint(pt))
+	}
+	if closed {
+		b.WriteString(" Z")
+	}
+}
+
+// writeSpline writes a Catmull-Rom spline through pts,
+// expressed as cubic Bézier curves.
+func writeSpline(b *strings.Builder, pts [][2]float64, closed bool) {
+	n := len(pts)
+	if n < 3 {
+		writePolyline(b, pts, closed)
+		return
+	}
+	at := func(i int) [2]float64 {
+		if closed {
+			return pts[(i+n)%n]
+		}
+		if i < 0 {
+			i = 0
+		} else if i >= n {
+			i = n - 1
+		}
+		return pts[i]
+	}
+	b.WriteString("M" + formatPoint(pts[0]))
+	last := n - 1
+	if closed {
+		last = n
+	}
+	for i := 0; i < last; i++ {
+		p0, p1, p2, p3 := at(i-1), at(i), at(i+1), at(i+2)
+		c1 := [2]float64{p1[0] + (p2[0]-p0[0])/6, p1[1] + (p2[1]-p0[1])/6}
+		c2 := [2]float64{p2[0] - (p3[0]-p1[0])/6, p2[1] - (p3[1]-p1[1])/6}
+		b.WriteString(" C" + formatPoint(c1) + " " + formatPoint(c2) + " " + formatPoint(p2))
+	}
+	if closed {
+		b.WriteString(" Z")
+	}
+}
@@ -0,0 +1,61 @@
+package svg
+
+import (
+	"math"
+	"strings"
+)
+
+// luminance returns the relative luminance of c, as defined by WCAG.
+func (c rgb) luminance() float64 {
+	return 0.2126*srgbToLinear(c.r) + 0.7152*srgbToLinear(c.g) + 0.0722*srgbToLinear(c.b)
+}
+
+// Contrast returns the WCAG contrast ratio between the colors a
+// and b, ranging from 1 to 21. WCAG level AA requires a ratio of
+// at least 4.5 for normal text, and 3 for large text.
+// If either color cannot be parsed, false is returned.
+func Contrast(a, b Paint) (float64, bool) {
+	ca, okA := parseColor(a)
+	cb, okB := parseColor(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	la, lb := ca.luminance(), cb.luminance()
+	return (math.Max(la, lb) + 0.05) / (math.Min(la, lb) + 0.05), true
+}
+
+// TextColor returns black or white, whichever has the higher
+// contrast to the background color bg. If bg cannot be
+// parsed, black is returned.
+func TextColor(bg Paint) Paint {
+	c, ok := parseColor(bg)
+	if !ok {
+		return "black"
+	}
+	// black and white have equal contrast at this luminance
+	if c.luminance() > math.Sqrt(1.05*0.05)-0.05 {
+		return "black"
+	}
+	return "white"
+}
+
+// ClassPaint returns the value of property, like "fill",
+// within the style of class, as created by MakeStyle with
+// GenerateEmbeddedStylesheet enabled.
+func (d *Document) ClassPaint(class, property string) (Paint, bool) {
+	style, ok := d.styles.classMap[class]
+	if !ok {
+		return "", false
+	}
+	var p Paint
+	found := false
+	for _, decl := range strings.Split(style, ";") {
+		i := strings.IndexByte(decl, ':')
+		if i == -1 || strings.TrimSpace(decl[:i]) != property {
+			continue
+		}
+		// later declarations take precedence
+		p, found = Paint(strings.TrimSpace(decl[i+1:])), true
+	}
+	return p, found
+}
@@ -0,0 +1,99 @@
+package svg
+
+import (
+	"math"
+	"strings"
+)
+
+// Crisp marks the container for pixel-exact rendering, as needed
+// for barcodes, or other scannable content: when encoding the
+// document, the container gets the attribute
+// shape-rendering="crispEdges", the coordinates of its descendants
+// are rounded to integers, and strokes of rectangles are disabled,
+// so that they are drawn as pure fills. Transformations are not
+// taken into account, so the container should be drawn at a
+// scale of one user unit per device pixel, or integer multiples.
+func (c *Container) Crisp() *Container {
+	c.crisp = true
+	return c
+}
+
+// crispContainer adds the shape-rendering attribute to
+// the copy c of a container.
+func crispContainer(c *Container) {
+	c.ExtraAttr = append(c.ExtraAttr[:len(c.ExtraAttr):len(c.ExtraAttr)],
+		&extraAttr{name: "shape-rendering", value: "crispEdges"})
+}
+
+// crispElem returns a copy of x with its coordinates rounded.
+func crispElem(x interface{}) interface{} {
+	r := math.Round
+	switch v := x.(type) {
+	case *Rect:
+		cp := *v
+		x0, y0 := r(v.X), r(v.Y)
+		cp.X, cp.Y = x0, y0
+		cp.Width = r(v.X+v.Width) - x0
+		cp.Height = r(v.Y+v.Height) - y0
+		cp.Rx, cp.Ry = r(v.Rx), r(v.Ry)
+		cp.Style = strings.TrimPrefix(cp.Style+";stroke:none", ";")
+		return &cp
+	case *line:
+		cp := *v
+		cp.X1, cp.Y1, cp.X2, cp.Y2 = r(v.X1), r(v.Y1), r(v.X2), r(v.Y2)
+		return &cp
+	case *PolyLine:
+		cp := *v
+		cp.Points = roundPoints(v.Points)
+		return &cp
+	case *polygon:
+		cp := *v
+		cp.Points = roundPoints(v.Points)
+		return &cp
+	case *CompactPolyLine:
+		cp := *v
+		cp.Points = make(CompactPoints, len(v.Points))
+		for i, f := range v.Points {
+			cp.Points[i] = r(f)
+		}
+		return &cp
+	case *circle:
+		cp := *v
+		cp.X, cp.Y, cp.R = r(v.X), r(v.Y), r(v.R)
+		return &cp
+	case *ellipse:
+		cp := *v
+		cp.X, cp.Y, cp.Rx, cp.Ry = r(v.X), r(v.Y), r(v.Rx), r(v.Ry)
+		return &cp
+	case *path:
+		segs, err := parsePath(v.D)
+		if err != nil {
+			return x
+		}
+		for i := range segs {
+			for j := range segs[i].pts {
+				segs[i].pts[j] = [2]float64{r(segs[i].pts[j][0]), r(segs[i].pts[j][1])}
+			}
+		}
+		cp := *v
+		cp.D = formatPath(segs)
+		return &cp
+	case *text:
+		cp := *v
+		cp.X, cp.Y = r(v.X), r(v.Y)
+		return &cp
+	case *use:
+		cp := *v
+		cp.X, cp.Y = r(v.X), r(v.Y)
+		return &cp
+	}
+	return x
+}
+
+func roundPoints(pts Points) Points {
+	rounded := make(Points, len(pts))
+	for i, pt := range pts {
+		rounded[i] = [2]float64{math.Round(pt[0]), math.Round(pt[1])}
+	}
+	return rounded
+}
@@ -0,0 +1,200 @@
+package svg
+
+import (
+	"sort"
+	"strings"
+)
+
+// CSSDependencies describes the parts of a document that depend on
+// CSS support of the renderer, as reported by Document.CSSDependencies.
+// Some renderers, like converters and office suites, ignore embedded
+// stylesheets, or support only presentation attributes.
+type CSSDependencies struct {
+	// Classes lists, sorted, the class names that are referenced
+	// by elements and defined within the embedded stylesheet.
+	Classes []string
+
+	// ClassElems is the number of elements, including tspans,
+	// that are styled by rules of the embedded stylesheet.
+	ClassElems int
+
+	// StyleAttrs is the number of elements, including tspans,
+	// that have a style attribute.
+	StyleAttrs int
+
+	// Variables lists, sorted, the names of custom properties,
+	// like "--accent", referenced using var() within the
+	// stylesheet or style attributes.
+	Variables []string
+
+	// MediaQueries lists the conditions of @media rules within
+	// the embedded stylesheet, including those generated for
+	// levels of detail.
+	MediaQueries []string
+}
+
+// Empty reports whether the document does not depend on CSS,
+// i.e. it is styled using presentation attributes only.
+func (deps *CSSDependencies) Empty() bool {
+	return deps.ClassElems == 0 && deps.StyleAttrs == 0 &&
+		len(deps.Variables) == 0 && len(deps.MediaQueries) == 0
+}
+
+// CSSDependencies reports which features of the document, as it
+// would be encoded, depend on the embedded stylesheet, or on CSS
+// support in general, so that they can be inlined, or replaced by
+// presentation attributes, if strict renderers are targeted.
+func (d *Document) CSSDependencies() *CSSDependencies {
+	doc := d.prepared()
+	s := &cssScanner{
+		defined: make(map[string]bool),
+		used:    make(map[string]bool),
+		vars:    make(map[string]bool),
+	}
+	s.stylesheet(doc.Style)
+	s.list(doc.ElemList)
+	return &CSSDependencies{
+		Classes:      sortedKeys(s.used),
+		ClassElems:   s.nClassElems,
+		StyleAttrs:   s.nStyleAttrs,
+		Variables:    sortedKeys(s.vars),
+		MediaQueries: s.media,
+	}
+}
+
+type cssScanner struct {
+	defined map[string]bool
+	used    map[string]bool
+	vars    map[string]bool
+	media   []string
+
+	nClassElems int
+	nStyleAttrs int
+}
+
+// stylesheet collects the class selectors, @media conditions,
+// and references to custom properties of a stylesheet.
+func (s *cssScanner) stylesheet(css string) {
+	for {
+		i := strings.IndexAny(css, "{}")
+		if i == -1 {
+			return
+		}
+		if css[i] == '}' {
+			// end of a group rule, like @media
+			css = css[i+1:]
+			continue
+		}
+		prelude := strings.TrimSpace(css[:i])
+		css = css[i+1:]
+		if strings.HasPrefix(prelude, "@") {
+			if strings.HasPrefix(prelude, "@media") {
+				s.media = append(s.media, strings.TrimSpace(prelude[len("@media"):]))
+			}
+			continue
+		}
+		s.selector(prelude)
+		end := strings.IndexByte(css, '}')
+		if end == -1 {
+			end = len(css) - 1
+		}
+		s.varRefs(css[:end+1])
+		css = css[end+1:]
+	}
+}
+
+// selector collects the class names of a selector list.
+func (s *cssScanner) selector(sel string) {
+	for {
+		i := strings.IndexByte(sel, '.')
+		if i == -1 {
+			return
+		}
+		sel = sel[i+1:]
+		n := 0
+		for n < len(sel) && isNameByte(sel[n]) {
+			n++
+		}
+		if n != 0 {
+			s.defined[sel[:n]] = true
+		}
+		sel = sel[n:]
+	}
+}
+
+// varRefs collects the names of custom properties referenced
+// within a declaration list.
+func (s *cssScanner) varRefs(decls string) {
+	for {
+		i := strings.Index(decls, "var(")
+		if i == -1 {
+			return
+		}
+		decls = decls[i+len("var("):]
+		end := strings.IndexAny(decls, ",)")
+		if end == -1 {
+			return
+		}
+		if name := strings.TrimSpace(decls[:end]); name != "" {
+			s.vars[name] = true
+		}
+		decls = decls[end:]
+	}
+}
+
+func (s *cssScanner) list(el ElemList) {
+	for _, x := range el {
+		if e, ok := x.(element); ok {
+			s.styling(&e.object().Styling)
+		}
+		switch v := x.(type) {
+		case container:
+			s.list(v.container().ElemList)
+		case *text:
+			s.textData(v.Data)
+		}
+	}
+}
+
+func (s *cssScanner) textData(data TextData) {
+	for _, x := range data {
+		if ts, ok := x.(*tspan); ok {
+			s.styling(&ts.Styling)
+			s.textData(ts.Data)
+		}
+	}
+}
+
+func (s *cssScanner) styling(st *Styling) {
+	styled := false
+	for _, class := range strings.Fields(st.Class) {
+		if s.defined[class] {
+			s.used[class] = true
+			styled = true
+		}
+	}
+	if styled {
+		s.nClassElems++
+	}
+	if st.Style != "" {
+		s.nStyleAttrs++
+		s.varRefs(st.Style)
+	}
+}
+
+func isNameByte(c byte) bool {
+	return c == '-' || c == '_' || c >= 0x80 ||
+		'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+func sortedKeys(m map[string]bool) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
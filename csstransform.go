@@ -0,0 +1,185 @@
+package svg
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// CSSTransformList is a list of CSS transform functions, which,
+// unlike the SVG transform attribute, include 3D transformations,
+// like rotateX(). It is intended for documents displayed by
+// browsers. An element's CSS transformations are encoded as
+// transform property within its style attribute, preceded by
+// its SVG transformations, if any, which are converted to their
+// CSS equivalents, as a transform property overrides the
+// transform attribute. The CSS transformations of the Document
+// itself are ignored.
+type CSSTransformList []Transform
+
+func (tl *CSSTransformList) append(name string, args ...string) *CSSTransformList {
+	t := Transform{Name: name, Args: make([]TransformArg, len(args))}
+	for i, a := range args {
+		t.Args[i] = cssArg(a)
+	}
+	*tl = append(*tl, t)
+	return tl
+}
+
+// cssArg is an argument of a CSS transform function,
+// formatted already.
+type cssArg string
+
+func (a cssArg) String() string { return string(a) }
+
+// cssLength formats l as a CSS length: plain numbers get
+// a "px" suffix, which CSS requires for non-zero lengths.
+func cssLength(l Length) string {
+	if v, unit, ok := lengthValue(l); ok {
+		if unit == "" && v != 0 {
+			unit = "px"
+		}
+		return formatFloat(v) + unit
+	}
+	a, err := l.MarshalXMLAttr(xml.Name{})
+	if err != nil {
+		return "0"
+	}
+	return a.Value
+}
+
+// Translate adds a translation by x and y.
+func (tl *CSSTransformList) Translate(x, y Length) *CSSTransformList {
+	return tl.append("translate", cssLength(x), cssLength(y))
+}
+
+// Translate3d adds a translation by x, y, and z.
+func (tl *CSSTransformList) Translate3d(x, y, z Length) *CSSTransformList {
+	return tl.append("translate3d", cssLength(x), cssLength(y), cssLength(z))
+}
+
+// TranslateZ adds a translation along the z axis.
+func (tl *CSSTransformList) TranslateZ(z Length) *CSSTransformList {
+	return tl.append("translateZ", cssLength(z))
+}
+
+// Scale adds a scale transformation by x and y.
+func (tl *CSSTransformList) Scale(x, y float64) *CSSTransformList {
+	return tl.append("scale", formatFloat(x), formatFloat(y))
+}
+
+// Scale3d adds a scale transformation by x, y, and z.
+func (tl *CSSTransformList) Scale3d(x, y, z float64) *CSSTransformList {
+	return tl.append("scale3d", formatFloat(x), formatFloat(y), formatFloat(z))
+}
+
+// Rotate adds a rotation by a within the xy plane.
+func (tl *CSSTransformList) Rotate(a Angle) *CSSTransformList {
+	return tl.append("rotate", a.String())
+}
+
+// RotateX adds a rotation by a around the x axis.
+func (tl *CSSTransformList) RotateX(a Angle) *CSSTransformList {
+	return tl.append("rotateX", a.String())
+}
+
+// RotateY adds a rotation by a around the y axis.
+func (tl *CSSTransformList) RotateY(a Angle) *CSSTransformList {
+	return tl.append("rotateY", a.String())
+}
+
+// RotateZ adds a rotation by a around the z axis.
+func (tl *CSSTransformList) RotateZ(a Angle) *CSSTransformList {
+	return tl.append("rotateZ", a.String())
+}
+
+// Rotate3d adds a rotation by a around the vector [x y z].
+func (tl *CSSTransformList) Rotate3d(x, y, z float64, a Angle) *CSSTransformList {
+	return tl.append("rotate3d", formatFloat(x), formatFloat(y), formatFloat(z), a.String())
+}
+
+// Skew adds a skew transformation by ax along the x axis,
+// and by ay along the y axis.
+func (tl *CSSTransformList) Skew(ax, ay Angle) *CSSTransformList {
+	return tl.append("skew", ax.String(), ay.String())
+}
+
+// Perspective sets the distance of the viewer from the z=0 plane
+// for the transformations that follow.
+func (tl *CSSTransformList) Perspective(d Length) *CSSTransformList {
+	return tl.append("perspective", cssLength(d))
+}
+
+// Matrix3d adds the transformation specified by the 4x4 matrix
+// m, in column-major order, like the arguments of matrix3d().
+func (tl *CSSTransformList) Matrix3d(m [16]float64) *CSSTransformList {
+	args := make([]string, len(m))
+	for i, f := range m {
+		args[i] = formatFloat(f)
+	}
+	return tl.append("matrix3d", args...)
+}
+
+// String returns the value of a CSS transform property.
+func (tl CSSTransformList) String() string {
+	s := make([]string, len(tl))
+	for i, t := range tl {
+		args := make([]string, len(t.Args))
+		for ia := range t.Args {
+			args[ia] = t.Args[ia].String()
+		}
+		s[i] = t.Name + "(" + strings.Join(args, ",") + ")"
+	}
+	return strings.Join(s, " ")
+}
+
+// cssTransforms converts the SVG transformations of tl
+// into CSS transform functions.
+func (tl TransformList) cssTransforms() CSSTransformList {
+	var list CSSTransformList
+	for _, t := range tl {
+		a, ok := t.floatArgs()
+		if !ok {
+			list = append(list, t)
+			continue
+		}
+		switch {
+		case t.Name == "translate" && len(a) == 1:
+			list.Translate(Number(a[0]), Number(0))
+		case t.Name == "translate" && len(a) == 2:
+			list.Translate(Number(a[0]), Number(a[1]))
+		case t.Name == "rotate" && len(a) == 1:
+			list.Rotate(Angle(a[0]))
+		case t.Name == "rotate" && len(a) == 3:
+			list.Translate(Number(a[1]), Number(a[2]))
+			list.Rotate(Angle(a[0]))
+			list.Translate(Number(-a[1]), Number(-a[2]))
+		case t.Name == "skewX" && len(a) == 1, t.Name == "skewY" && len(a) == 1:
+			list.append(t.Name, Angle(a[0]).String())
+		default:
+			// scale and matrix take plain numbers in CSS too
+			list = append(list, t)
+		}
+	}
+	return list
+}
+
+// withCSSTransform returns a copy of element x, having its SVG and
+// CSS transformations moved into its style attribute, or x itself,
+// if it has no CSS transformations.
+func withCSSTransform(x interface{}) interface{} {
+	e, ok := x.(element)
+	if !ok || len(e.object().CSSTransform) == 0 {
+		return x
+	}
+	x = shallowCopy(x)
+	obj := x.(element).object()
+	tl := append(obj.TransformList.cssTransforms(), obj.CSSTransform...)
+	decl := "transform:" + tl.String()
+	if obj.Style != "" {
+		decl = obj.Style + ";" + decl
+	}
+	obj.Style = decl
+	obj.TransformList = nil
+	obj.CSSTransform = nil
+	return x
+}
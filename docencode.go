@@ -0,0 +1,132 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+)
+
+// EncodeOption configures Document.Encode.
+type EncodeOption func(*encodeOptions)
+
+type encodeOptions struct {
+	header    bool
+	prefix    string
+	indent    string
+	selfClose bool
+}
+
+// XMLHeader makes Encode write an XML declaration, xml.Header,
+// in front of the document.
+func XMLHeader() EncodeOption {
+	return func(o *encodeOptions) {
+		o.header = true
+	}
+}
+
+// Indent makes Encode indent elements, as with xml.Encoder.Indent:
+// each element starts on a new line, beginning with prefix and
+// one or more copies of indent, according to the nesting depth.
+func Indent(prefix, indent string) EncodeOption {
+	return func(o *encodeOptions) {
+		o.prefix = prefix
+		o.indent = indent
+	}
+}
+
+// SelfClose makes Encode write empty elements, like <rect>,
+// using self-closing tags, see SelfCloseEmptyElements.
+// The document is buffered in memory in this case.
+func SelfClose() EncodeOption {
+	return func(o *encodeOptions) {
+		o.selfClose = true
+	}
+}
+
+// Encode writes the document to w, configured by options,
+// followed by a newline. If the document is indented, the content
+// of <text> elements is not, so that no white space is added
+// around <tspan> elements; see TextObject.XMLIndentHint, which
+// is not needed when using Encode. Like MarshalXML, Encode fails
+// with a NumberError if the document contains invalid numbers.
+func (d *Document) Encode(w io.Writer, options ...EncodeOption) error {
+	var o encodeOptions
+	for _, opt := range options {
+		opt(&o)
+	}
+	out := w
+	var buf bytes.Buffer
+	if o.selfClose {
+		out = &buf
+	}
+	doc := d.prepared()
+	if err := firstNumberError(doc.ElemList); err != nil {
+		return err
+	}
+	if o.header {
+		if _, err := io.WriteString(out, xml.Header); err != nil {
+			return err
+		}
+	}
+	enc := xml.NewEncoder(out)
+	if o.prefix != "" || o.indent != "" {
+		enc.Indent(o.prefix, o.indent)
+		indentHints(doc.ElemList, o.prefix, o.indent)
+	}
+	formatNumbers(doc.ElemList)
+	if err := enc.Encode((*plainDocument)(doc)); err != nil {
+		return err
+	}
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(out, "\n"); err != nil {
+		return err
+	}
+	if o.selfClose {
+		_, err := w.Write(SelfCloseEmptyElements(buf.Bytes()))
+		return err
+	}
+	return nil
+}
+
+// indentHints replaces text elements within el, a list of the
+// prepared document, by copies having the indentation hint set on
+// their tspans. Containers of the prepared document are copies
+// already, so their lists may be modified.
+func indentHints(el ElemList, prefix, indent string) {
+	for i, x := range el {
+		switch v := x.(type) {
+		case container:
+			indentHints(v.container().ElemList, prefix, indent)
+		case *text:
+			if data := hintedTextData(v.Data, prefix, indent); data != nil {
+				t := *v
+				t.Data = data
+				el[i] = &t
+			}
+		}
+	}
+}
+
+// hintedTextData returns a copy of data with copies of its tspans
+// having the indentation hint set, or nil, if data has no tspans.
+func hintedTextData(data TextData, prefix, indent string) TextData {
+	var cp TextData
+	for i, x := range data {
+		ts, ok := x.(*tspan)
+		if !ok {
+			continue
+		}
+		if cp == nil {
+			cp = append(TextData(nil), data...)
+		}
+		ts2 := *ts
+		ts2.XMLIndentHint(prefix, indent)
+		if sub := hintedTextData(ts.Data, prefix, indent); sub != nil {
+			ts2.Data = sub
+		}
+		cp[i] = &ts2
+	}
+	return cp
+}
@@ -0,0 +1,390 @@
+package svg
+
+import (
+	"encoding/xml"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MarshalXML encodes the document. Before encoding, a shallow copy of
+// the element tree is created, leaving out elements that have been
+// disabled using Object.When or Container.WhenFunc, and, if
+// Conf.CullToViewBox is set, shapes outside of the viewBox.
+// Annotation layers are moved to the end of their parent's
+// list of children, so that they are drawn above their siblings.
+// Rules controlling the display of levels of detail are appended
+// to the embedded stylesheet. If the document contains numbers that
+// cannot be encoded safely, a NumberError is returned, unless
+// Conf.ClampNonFinite is set.
+func (d *Document) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	doc := d.prepared()
+	if err := firstNumberError(doc.ElemList); err != nil {
+		return err
+	}
+	formatNumbers(doc.ElemList)
+	return e.Encode((*plainDocument)(doc))
+}
+
+// prepared returns the copy of the document to be encoded.
+func (d *Document) prepared() *Document {
+	p, m := d.preparer()
+	doc := *d
+	doc.TransformList = expandTransforms(d.TransformList, d.transforms)
+	doc.ElemList = p.list(d.ElemList, m, p.cull, false)
+	if len(d.lods) != 0 {
+		doc.Style = strings.TrimPrefix(doc.Style+d.lodStyle(), " ")
+	}
+	return &doc
+}
+
+// preparer returns a preparer configured for the document, and the
+// matrix of the document's transformation, to be passed to its
+// list method.
+func (d *Document) preparer() (*preparer, affine) {
+	p := &preparer{root: d.ElemList, transforms: d.transforms}
+	if c := d.conf; c != nil {
+		p.explicitZeros = c.ExplicitZeros
+		p.circlesAsPaths = c.CirclesAsPaths
+		if c.Precision > 0 || c.ClampNonFinite {
+			p.numbers = &numberMapper{clamp: c.ClampNonFinite}
+			if c.Precision > 0 {
+				p.numbers.scale = math.Pow(10, float64(c.Precision))
+			}
+		}
+		if c.PresentationAttributes {
+			p.presAttrs = &presAttrConverter{
+				classes: d.styles.classMap,
+				fields:  make(map[reflect.Type]map[string][]int),
+			}
+		}
+	}
+	m, ok := expandTransforms(d.TransformList, d.transforms).matrix()
+	if c := d.conf; c != nil && c.CullToViewBox && len(d.ViewBox) == 4 && ok {
+		p.cull = true
+		vb := d.ViewBox
+		p.view = [4]float64{float64(vb[0]), float64(vb[1]), float64(vb[0] + vb[2]), float64(vb[1] + vb[3])}
+	}
+	return p, m
+}
+
+// plainDocument has the same layout as Document, but lacks its
+// MarshalXML method, so it can be encoded using the default
+// encoding/xml rules.
+type plainDocument Document
+
+// preparer creates copies of element lists, containing only
+// the elements that shall be encoded.
+type preparer struct {
+	cull           bool
+	explicitZeros  bool
+	circlesAsPaths bool
+	presAttrs      *presAttrConverter
+	numbers        *numberMapper
+
+	// transforms contains the named transformations
+	// of the document
+	transforms map[string]TransformList
+
+	// view contains the minimum and maximum
+	// coordinates of the viewBox
+	view [4]float64
+
+	// root is the document's element list, used for looking
+	// up the targets of <use> elements to be expanded; ids
+	// is filled from it on demand.
+	root     ElemList
+	ids      map[string]interface{}
+	useDepth int
+}
+
+// list returns a copy of el containing only the elements that shall
+// be encoded. Containers are copied recursively; elements are ordered
+// by their z-index, and containers to be kept on top of their
+// siblings are moved to the end. Matrix m contains
+// the transformation from the elements' parent to the
+// root coordinate system; it is only used if cull is true.
+// If crisp is set, the coordinates of elements are rounded,
+// see Container.Crisp.
+func (p *preparer) list(el ElemList, m affine, cull, crisp bool) ElemList {
+	if el == nil {
+		return nil
+	}
+	list := make(ElemList, 0, len(el))
+	var top ElemList
+	for _, x := range el {
+		if !included(x) {
+			continue
+		}
+		x = p.withNamedTransforms(x)
+		em := m
+		ecull := cull
+		if cull {
+			if e, ok := x.(element); ok {
+				own, ok := e.object().TransformList.matrix()
+				em = m.mul(own)
+				ecull = ok && len(e.object().CSSTransform) == 0
+			}
+		}
+		switch x.(type) {
+		case *Defs, *Symbol, *Pattern, *Marker, *Filter:
+			ecull = false
+		}
+		if ecull && p.outside(x, em) {
+			continue
+		}
+		if u, ok := x.(*use); ok && u.expand {
+			if g := p.expandUse(u); g != nil {
+				p.useDepth++
+				g.ElemList = p.list(g.ElemList, em.mul(affine{1, 0, 0, 1, u.X, u.Y}), cull, crisp)
+				p.useDepth--
+				list = append(list, g)
+				continue
+			}
+		}
+		if p.presAttrs != nil {
+			x = p.presAttrs.elem(x)
+		}
+		x = withCSSTransform(x)
+		if p.numbers != nil {
+			x = p.numbers.elem(x)
+		}
+		x = withZeros(x, p.explicitZeros)
+		if c, ok := x.(container); ok {
+			onTop := c.container().onTop
+			x = shallowCopy(x)
+			c := x.(container).container()
+			if c.crisp && !crisp {
+				crispContainer(c)
+			}
+			c.ElemList = p.list(c.ElemList, em, ecull, crisp || c.crisp)
+			if onTop {
+				top = append(top, x)
+				continue
+			}
+		} else {
+			if crisp {
+				x = crispElem(x)
+			}
+			if p.circlesAsPaths {
+				x = arcPath(x)
+			}
+		}
+		list = append(list, x)
+	}
+	sortByZ(list)
+	sortByZ(top)
+	return append(list, top...)
+}
+
+// sortByZ sorts the elements of el by their z-index, see
+// Object.SetZ, keeping the order of elements with equal indices.
+func sortByZ(el ElemList) {
+	z := func(x interface{}) int {
+		if e, ok := x.(element); ok {
+			return e.object().z
+		}
+		return 0
+	}
+	for _, x := range el {
+		if z(x) != 0 {
+			sort.SliceStable(el, func(i, j int) bool {
+				return z(el[i]) < z(el[j])
+			})
+			return
+		}
+	}
+}
+
+// expandUse returns a group replacing the <use> element u,
+// containing a copy of the element it references.
+// It returns nil if the target cannot be found.
+func (p *preparer) expandUse(u *use) *Group {
+	if p.useDepth == maxUseDepth {
+		return nil
+	}
+	if p.ids == nil {
+		p.ids = make(map[string]interface{})
+		collectIDs(p.root, p.ids)
+	}
+	target, ok := p.ids[strings.TrimPrefix(u.Href, "#")]
+	if !ok {
+		return nil
+	}
+	g := &Group{Container: Container{Object: u.Object}}
+	g.expand = false
+	if u.X != 0 || u.Y != 0 {
+		g.TransformList = append(TransformList(nil), u.TransformList...)
+		g.TransformList.Translate(u.X, u.Y)
+	}
+	if s, ok := target.(*Symbol); ok {
+		for _, x := range s.ElemList {
+			g.ElemList = append(g.ElemList, copyWithoutIDs(x))
+		}
+	} else {
+		g.ElemList = ElemList{copyWithoutIDs(target)}
+	}
+	return g
+}
+
+// copyWithoutIDs returns a copy of element x, and of its
+// descendants, with IDs removed.
+func copyWithoutIDs(x interface{}) interface{} {
+	x = shallowCopy(x)
+	if e, ok := x.(element); ok {
+		e.object().ID = ""
+	}
+	if c, ok := x.(container); ok {
+		c := c.container()
+		list := make(ElemList, len(c.ElemList))
+		for i, child := range c.ElemList {
+			list[i] = copyWithoutIDs(child)
+		}
+		c.ElemList = list
+	}
+	return x
+}
+
+// zeroCoordElem reports whether x is one of the elements whose x and
+// y attributes are emitted even if zero, if Conf.ExplicitZeros is set.
+func zeroCoordElem(x interface{}) bool {
+	switch x.(type) {
+	case *Rect, *text, *use, *Symbol, *Pattern, *Image:
+		return true
+	}
+	return false
+}
+
+// withZeros returns a copy of x with extra attributes for numeric
+// attributes that would be left out because they are zero, but
+// have been marked using Object.KeepZero, or, if all is set, for
+// the coordinates of the elements affected by Conf.ExplicitZeros;
+// otherwise x is returned. As it is applied after numbers have
+// been rounded, values that are rounded to zero are included.
+func withZeros(x interface{}, all bool) interface{} {
+	e, ok := x.(element)
+	if !ok {
+		return x
+	}
+	names := e.object().zeros
+	if all && zeroCoordElem(x) {
+		names = append([]string{"x", "y"}, names...)
+	}
+	if len(names) == 0 {
+		return x
+	}
+	v := reflect.ValueOf(x).Elem()
+	fields := make(map[string][]int)
+	attrFields(v.Type(), nil, fields)
+	var zero []string
+	for _, name := range names {
+		idx, ok := fields[name]
+		if !ok {
+			continue
+		}
+		switch f := v.FieldByIndex(idx); f.Kind() {
+		case reflect.Float32, reflect.Float64:
+			if f.Float() == 0 && !contains(zero, name) {
+				zero = append(zero, name)
+			}
+		}
+	}
+	if len(zero) == 0 {
+		return x
+	}
+	x = shallowCopy(x)
+	obj := x.(element).object()
+	obj.ExtraAttr = append([]xml.MarshalerAttr(nil), obj.ExtraAttr...)
+	for _, name := range zero {
+		obj.Attr(name, "0")
+	}
+	return x
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// arcPath returns a path consisting of two arcs, if x is a circle
+// or an ellipse with positive radii; otherwise x is returned.
+func arcPath(x interface{}) interface{} {
+	var cx, cy, rx, ry float64
+	var so ShapeObject
+	switch v := x.(type) {
+	case *circle:
+		cx, cy, rx, ry, so = v.X, v.Y, v.R, v.R, v.ShapeObject
+	case *ellipse:
+		cx, cy, rx, ry, so = v.X, v.Y, v.Rx, v.Ry, v.ShapeObject
+	default:
+		return x
+	}
+	if rx <= 0 || ry <= 0 {
+		return x
+	}
+	var b PathBuilder
+	b.MoveTo(cx-rx, cy)
+	b.ArcTo(rx, ry, 0, true, false, cx+rx, cy)
+	b.ArcTo(rx, ry, 0, true, false, cx-rx, cy)
+	b.Close()
+	return &path{D: b.String(), ShapeObject: so}
+}
+
+// outside reports whether the shape x, transformed by m,
+// lies completely outside of the viewBox.
+func (p *preparer) outside(x interface{}, m affine) bool {
+	if pl, ok := x.(*CompactPolyLine); ok {
+		min, max, ok := pl.Points.bbox(m)
+		return ok && (max[0] < p.view[0] || max[1] < p.view[1] || min[0] > p.view[2] || min[1] > p.view[3])
+	}
+	segs, ok := shapeSegs(x)
+	if !ok {
+		return false
+	}
+	transformPath(segs, m)
+	min, max, ok := segsBBox(segs)
+	if !ok {
+		return false
+	}
+	return max[0] < p.view[0] || max[1] < p.view[1] || min[0] > p.view[2] || min[1] > p.view[3]
+}
+
+// shallowCopy returns a copy of the struct x points to.
+func shallowCopy(x interface{}) interface{} {
+	v := reflect.ValueOf(x)
+	if v.Kind() != reflect.Ptr {
+		return x
+	}
+	cp := reflect.New(v.Elem().Type())
+	cp.Elem().Set(v.Elem())
+	return cp.Interface()
+}
+
+// included reports whether element x shall be part of the
+// encoded document.
+func included(x interface{}) bool {
+	if o, ok := x.(element); ok && o.object().omit {
+		return false
+	}
+	if c, ok := x.(container); ok {
+		if f := c.container().when; f != nil && !f() {
+			return false
+		}
+	}
+	return true
+}
+
+// element is implemented by all element types embedding an Object.
+type element interface {
+	object() *Object
+}
+
+// container is implemented by all element types embedding a Container.
+type container interface {
+	container() *Container
+}
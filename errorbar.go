@@ -0,0 +1,71 @@
+package svg
+
+import (
+	"strings"
+)
+
+// ErrorPoint is a data point with an error range extending
+// from the value minus Minus to the value plus Plus.
+// For symmetric errors, both are set to the same value.
+type ErrorPoint struct {
+	X, Y        float64
+	Minus, Plus float64
+}
+
+// ErrorBars describes error bars to be drawn using
+// Container.ErrorBars.
+type ErrorBars struct {
+	Points []ErrorPoint
+
+	// Horizontal, if set, makes the error ranges apply to
+	// the x values; by default, they apply to the y values.
+	Horizontal bool
+
+	// CapWidth is the width of the caps at the ends of
+	// the bars; it defaults to 6. Negative values suppress the caps.
+	CapWidth float64
+
+	// Class is added to the class attribute of the group.
+	Class string
+}
+
+// ErrorBars appends a group with class "error-bars", plus
+// e.Class, containing a path with class "error-bar" for each
+// point of e, mapped using m. Since the bars are placed using
+// the same CoordMapper as the markers of a data series,
+// they may be drawn before or after them.
+func (c *Container) ErrorBars(m *CoordMapper, e *ErrorBars) *Container {
+	cw := e.CapWidth
+	if cw == 0 {
+		cw = 6
+	}
+	g := c.Group()
+	class := "error-bars"
+	if e.Class != "" {
+		class += " " + e.Class
+	}
+	g.SetClass(class)
+	for _, p := range e.Points {
+		var x0, y0, x1, y1 float64
+		if e.Horizontal {
+			x0, y0 = m.Map(p.X-p.Minus, p.Y)
+			x1, y1 = m.Map(p.X+p.Plus, p.Y)
+		} else {
+			x0, y0 = m.Map(p.X, p.Y-p.Minus)
+			x1, y1 = m.Map(p.X, p.Y+p.Plus)
+		}
+		var b strings.Builder
+		b.WriteString("M" + formatPoint([2]float64{x0, y0}) + " L" + formatPoint([2]float64{x1, y1}))
+		if cw > 0 {
+			for _, end := range [][2]float64{{x0, y0}, {x1, y1}} {
+				if e.Horizontal {
+					b.WriteString(" M" + formatPoint([2]float64{end[0], end[1] - cw/2}) + " v" + formatFloat(cw))
+				} else {
+					b.WriteString(" M" + formatPoint([2]float64{end[0] - cw/2, end[1]}) + " h" + formatFloat(cw))
+				}
+			}
+		}
+		g.Path(b.String()).SetClass("error-bar")
+	}
+	return g
+}
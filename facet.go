@@ -0,0 +1,117 @@
+package svg
+
+import (
+	"math"
+)
+
+// Facets describes a grid of small multiples, i.e. panels drawn by
+// the same function for different subsets of data, combined
+// into one document by Facets.Document.
+type Facets struct {
+	// N is the number of panels, which are arranged
+	// in rows of Columns panels each.
+	N       int
+	Columns int
+
+	// Width and Height are the size of each panel's drawing area.
+	Width, Height float64
+
+	// Gap is the space between neighbouring panels,
+	// and between the panels and the document's border.
+	Gap float64
+
+	// Titles, if set, are placed above the panels,
+	// within a space of TitleHeight, which defaults to 16.
+	Titles      []string
+	TitleHeight float64
+
+	// Draw is called for each panel. To use scales shared
+	// by all panels, create them once outside of Draw; to use
+	// independent scales, create them within Draw.
+	Draw func(p *Panel)
+
+	// Legend, if set, is called to draw a legend shared by all
+	// panels into a space of LegendHeight below the grid.
+	Legend       func(c *Container, width, height float64)
+	LegendHeight float64
+}
+
+// Panel is a sub-viewport of Facets.
+type Panel struct {
+	Index    int
+	Row, Col int
+
+	// Container has its origin at the top left corner of
+	// the panel's drawing area.
+	Container *Container
+
+	Width, Height float64
+
+	// Doc is the combined document, which may be used to create
+	// styles and IDs shared by all panels.
+	Doc *Document
+}
+
+// Document returns a new document containing the panels of f, each
+// within a group with class "facet" translated to the panel's
+// position, and with titles of class "facet-title". The legend is
+// drawn into a group with class "facet-legend". The document's
+// viewBox is set to cover all panels and the legend.
+func (f *Facets) Document(c *Conf) *Document {
+	d := NewDocument(c)
+	cols := f.Columns
+	if cols <= 0 {
+		cols = int(math.Ceil(math.Sqrt(float64(f.N))))
+	}
+	if cols == 0 {
+		cols = 1
+	}
+	rows := (f.N + cols - 1) / cols
+	th := 0.0
+	if len(f.Titles) != 0 {
+		th = f.TitleHeight
+		if th == 0 {
+			th = 16
+		}
+	}
+	cellW := f.Width + f.Gap
+	cellH := f.Height + th + f.Gap
+	for i := 0; i < f.N; i++ {
+		row, col := i/cols, i%cols
+		x := f.Gap + float64(col)*cellW
+		y := f.Gap + float64(row)*cellH
+		g := d.Group()
+		g.SetClass("facet")
+		g.Translate(x, y)
+		if i < len(f.Titles) {
+			t := g.Text(f.Width/2, th/2, f.Titles[i])
+			t.Anchor(AnchorMiddle)
+			t.Dy = EmUnits(0.35)
+			t.SetClass("facet-title")
+		}
+		area := g.Group()
+		area.Translate(0, th)
+		if f.Draw != nil {
+			f.Draw(&Panel{
+				Index:     i,
+				Row:       row,
+				Col:       col,
+				Container: area,
+				Width:     f.Width,
+				Height:    f.Height,
+				Doc:       d,
+			})
+		}
+	}
+	w := f.Gap + float64(cols)*cellW
+	h := f.Gap + float64(rows)*cellH
+	if f.Legend != nil {
+		g := d.Group()
+		g.SetClass("facet-legend")
+		g.Translate(f.Gap, h)
+		f.Legend(g, w-2*f.Gap, f.LegendHeight)
+		h += f.LegendHeight + f.Gap
+	}
+	d.ViewBox = Ints{0, 0, int(math.Ceil(w)), int(math.Ceil(h))}
+	return d
+}
@@ -0,0 +1,249 @@
+package svg
+
+import (
+	"encoding/xml"
+)
+
+// Standard inputs of filter primitives.
+const (
+	SourceGraphic   = "SourceGraphic"
+	SourceAlpha     = "SourceAlpha"
+	BackgroundImage = "BackgroundImage"
+	FillPaint       = "FillPaint"
+	StrokePaint     = "StrokePaint"
+)
+
+// Filter is a <filter> element, a container for filter primitives,
+// which are applied to elements referencing the filter using
+// Object.SetFilter. Primitives are added using the methods of Filter;
+// their results may be referred to by later primitives, as input,
+// using the names given by SetResult.
+type Filter struct {
+	XMLName xml.Name `xml:"filter"`
+
+	// The filter region; by default it extends the bounding box
+	// of the element the filter is applied to by 10% on each side.
+	X      Length `xml:"x,attr,omitempty"`
+	Y      Length `xml:"y,attr,omitempty"`
+	Width  Length `xml:"width,attr,omitempty"`
+	Height Length `xml:"height,attr,omitempty"`
+
+	Units          GradientUnits `xml:"filterUnits,attr,omitempty"`
+	PrimitiveUnits GradientUnits `xml:"primitiveUnits,attr,omitempty"`
+
+	Container
+}
+
+// Filter appends a <filter> element with the specified id
+// to el, which is usually a <defs> container.
+func (el *ElemList) Filter(id string) *Filter {
+	f := new(Filter)
+	f.ID = id
+	el.append(f)
+	return f
+}
+
+// Region sets the filter region.
+func (f *Filter) Region(x, y, w, h Length) *Filter {
+	f.X, f.Y, f.Width, f.Height = x, y, w, h
+	return f
+}
+
+// SetFilter applies the filter with the specified id to the object.
+func (o *Object) SetFilter(id string) *Object {
+	o.FilterRef = "url(#" + id + ")"
+	return o
+}
+
+// FilterPrimitive contains properties common to filter primitives.
+type FilterPrimitive struct {
+	// Result names the output of the primitive.
+	Result string `xml:"result,attr,omitempty"`
+	Object
+}
+
+// SetResult sets the name of the primitive's output.
+func (p *FilterPrimitive) SetResult(name string) *FilterPrimitive {
+	p.Result = name
+	return p
+}
+
+// FeGaussianBlur is a <feGaussianBlur> filter primitive.
+type FeGaussianBlur struct {
+	XMLName      xml.Name `xml:"feGaussianBlur"`
+	In           string   `xml:"in,attr,omitempty"`
+	StdDeviation float64  `xml:"stdDeviation,attr"`
+	FilterPrimitive
+}
+
+// GaussianBlur appends a blur of input in with
+// the specified standard deviation.
+func (f *Filter) GaussianBlur(in string, stdDev float64) *FeGaussianBlur {
+	p := &FeGaussianBlur{In: in, StdDeviation: stdDev}
+	f.append(p)
+	return p
+}
+
+// FeOffset is a <feOffset> filter primitive.
+type FeOffset struct {
+	XMLName xml.Name `xml:"feOffset"`
+	In      string   `xml:"in,attr,omitempty"`
+	Dx      float64  `xml:"dx,attr,omitempty"`
+	Dy      float64  `xml:"dy,attr,omitempty"`
+	FilterPrimitive
+}
+
+// Offset appends a primitive shifting input in by dx, dy.
+func (f *Filter) Offset(in string, dx, dy float64) *FeOffset {
+	p := &FeOffset{In: in, Dx: dx, Dy: dy}
+	f.append(p)
+	return p
+}
+
+// ColorMatrixType is the type of a <feColorMatrix> primitive.
+type ColorMatrixType string
+
+const (
+	MatrixValues     ColorMatrixType = "matrix"
+	Saturate         ColorMatrixType = "saturate"
+	HueRotate        ColorMatrixType = "hueRotate"
+	LuminanceToAlpha ColorMatrixType = "luminanceToAlpha"
+)
+
+// FeColorMatrix is a <feColorMatrix> filter primitive.
+type FeColorMatrix struct {
+	XMLName xml.Name        `xml:"feColorMatrix"`
+	In      string          `xml:"in,attr,omitempty"`
+	Type    ColorMatrixType `xml:"type,attr,omitempty"`
+	Values  Floats64        `xml:"values,attr,omitempty"`
+	FilterPrimitive
+}
+
+// ColorMatrix appends a color transformation of input in. Depending
+// on typ, values contains the 20 elements of a 5×4 matrix, in row
+// major order, a saturation, or an angle in degrees.
+func (f *Filter) ColorMatrix(in string, typ ColorMatrixType, values ...float64) *FeColorMatrix {
+	p := &FeColorMatrix{In: in, Type: typ, Values: values}
+	f.append(p)
+	return p
+}
+
+// FeMerge is a <feMerge> filter primitive.
+type FeMerge struct {
+	XMLName xml.Name `xml:"feMerge"`
+	FilterPrimitive
+	Nodes []FeMergeNode
+}
+
+// FeMergeNode is an input of a <feMerge> primitive.
+type FeMergeNode struct {
+	XMLName xml.Name `xml:"feMergeNode"`
+	In      string   `xml:"in,attr,omitempty"`
+}
+
+// Merge appends a primitive compositing its inputs on top of each
+// other, the first one at the bottom.
+func (f *Filter) Merge(in ...string) *FeMerge {
+	p := new(FeMerge)
+	for _, s := range in {
+		p.Nodes = append(p.Nodes, FeMergeNode{In: s})
+	}
+	f.append(p)
+	return p
+}
+
+// BlendMode is the mode of a <feBlend> primitive.
+type BlendMode string
+
+const (
+	BlendNormal   BlendMode = "normal"
+	BlendMultiply BlendMode = "multiply"
+	BlendScreen   BlendMode = "screen"
+	BlendDarken   BlendMode = "darken"
+	BlendLighten  BlendMode = "lighten"
+)
+
+// FeBlend is a <feBlend> filter primitive.
+type FeBlend struct {
+	XMLName xml.Name  `xml:"feBlend"`
+	In      string    `xml:"in,attr,omitempty"`
+	In2     string    `xml:"in2,attr,omitempty"`
+	Mode    BlendMode `xml:"mode,attr,omitempty"`
+	FilterPrimitive
+}
+
+// Blend appends a primitive blending input in over in2.
+func (f *Filter) Blend(in, in2 string, mode BlendMode) *FeBlend {
+	p := &FeBlend{In: in, In2: in2, Mode: mode}
+	f.append(p)
+	return p
+}
+
+// FeFlood is a <feFlood> filter primitive.
+type FeFlood struct {
+	XMLName xml.Name `xml:"feFlood"`
+	Color   Paint    `xml:"flood-color,attr,omitempty"`
+	Opacity Length   `xml:"flood-opacity,attr,omitempty"`
+	FilterPrimitive
+}
+
+// Flood appends a primitive filling the filter region with color.
+func (f *Filter) Flood(color Paint, opacity float64) *FeFlood {
+	p := &FeFlood{Color: color, Opacity: Number(opacity)}
+	f.append(p)
+	return p
+}
+
+// CompositeOperator is the operator of a <feComposite> primitive.
+type CompositeOperator string
+
+const (
+	CompositeOver       CompositeOperator = "over"
+	CompositeIn         CompositeOperator = "in"
+	CompositeOut        CompositeOperator = "out"
+	CompositeAtop       CompositeOperator = "atop"
+	CompositeXor        CompositeOperator = "xor"
+	CompositeArithmetic CompositeOperator = "arithmetic"
+)
+
+// FeComposite is a <feComposite> filter primitive. The
+// coefficients K1 to K4 are used by the arithmetic operator.
+type FeComposite struct {
+	XMLName  xml.Name          `xml:"feComposite"`
+	In       string            `xml:"in,attr,omitempty"`
+	In2      string            `xml:"in2,attr,omitempty"`
+	Operator CompositeOperator `xml:"operator,attr,omitempty"`
+	K1       float64           `xml:"k1,attr,omitempty"`
+	K2       float64           `xml:"k2,attr,omitempty"`
+	K3       float64           `xml:"k3,attr,omitempty"`
+	K4       float64           `xml:"k4,attr,omitempty"`
+	FilterPrimitive
+}
+
+// Composite appends a primitive combining inputs in and in2
+// using a Porter-Duff operator.
+func (f *Filter) Composite(in, in2 string, op CompositeOperator) *FeComposite {
+	p := &FeComposite{In: in, In2: in2, Operator: op}
+	f.append(p)
+	return p
+}
+
+// FeDropShadow is a <feDropShadow> filter primitive.
+type FeDropShadow struct {
+	XMLName      xml.Name `xml:"feDropShadow"`
+	In           string   `xml:"in,attr,omitempty"`
+	Dx           float64  `xml:"dx,attr"`
+	Dy           float64  `xml:"dy,attr"`
+	StdDeviation float64  `xml:"stdDeviation,attr"`
+	Color        Paint    `xml:"flood-color,attr,omitempty"`
+	Opacity      Length   `xml:"flood-opacity,attr,omitempty"`
+	FilterPrimitive
+}
+
+// DropShadow appends a drop shadow of the input in,
+// offset by dx, dy, and blurred by stdDev.
+func (f *Filter) DropShadow(in string, dx, dy, stdDev float64) *FeDropShadow {
+	p := &FeDropShadow{In: in, Dx: dx, Dy: dy, StdDeviation: stdDev}
+	f.append(p)
+	return p
+}
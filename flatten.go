@@ -0,0 +1,124 @@
+package svg
+
+import (
+	"strings"
+)
+
+// Flatten prepares the document for plotter and CAM pipelines,
+// like those generating HPGL or DXF output: All basic shapes and
+// paths are converted into paths consisting of absolute straight
+// line segments only, with curves approximated so that the
+// deviation does not exceed tolerance.
+// Transformations are applied to the coordinates and removed.
+// <use> elements are replaced by copies of the elements they
+// reference; symbols are treated like groups, their viewBox is
+// ignored, though. Definitions, symbols, text, and all other
+// elements that are not shapes are removed.
+// Styling, IDs and titles of the elements are kept, except for the
+// IDs within copies of referenced elements.
+func (d *Document) Flatten(tolerance float64) {
+	f := &flattener{tol: tolerance, ids: make(map[string]interface{})}
+	collectIDs(d.ElemList, f.ids)
+	m, ok := d.TransformList.matrix()
+	if ok {
+		d.TransformList = nil
+	} else {
+		m = identity
+	}
+	d.ElemList = f.list(d.ElemList, m, 0)
+}
+
+// collectIDs adds the elements of el that have an ID,
+// and those of their descendants, to the ids map.
+func collectIDs(el ElemList, ids map[string]interface{}) {
+	for _, x := range el {
+		if e, ok := x.(element); ok {
+			if id := e.object().ID; id != "" {
+				ids[id] = x
+			}
+		}
+		if c, ok := x.(container); ok {
+			collectIDs(c.container().ElemList, ids)
+		}
+	}
+}
+
+type flattener struct {
+	tol float64
+	ids map[string]interface{}
+}
+
+// maxUseDepth limits the nesting of <use> elements, avoiding
+// endless recursion in case of circular references.
+const maxUseDepth = 16
+
+func (f *flattener) list(el ElemList, m affine, depth int) ElemList {
+	list := make(ElemList, 0, len(el))
+	for _, x := range el {
+		if x = f.elem(x, m, depth); x != nil {
+			list = append(list, x)
+		}
+	}
+	return list
+}
+
+func (f *flattener) elem(x interface{}, parent affine, depth int) interface{} {
+	e, ok := x.(element)
+	if !ok {
+		return nil
+	}
+	switch x.(type) {
+	case *Defs, *Symbol, *Pattern, *Marker, *Filter, *text:
+		return nil
+	}
+	obj := *e.object()
+	own, ok := obj.TransformList.matrix()
+	if !ok {
+		return nil
+	}
+	m := parent.mul(own)
+	obj.TransformList = nil
+	if depth > 0 {
+		// avoid duplicate IDs within copies of referenced elements
+		obj.ID = ""
+	}
+
+	switch v := x.(type) {
+	case *use:
+		target, ok := f.ids[strings.TrimPrefix(v.Href, "#")]
+		if !ok || depth == maxUseDepth {
+			return nil
+		}
+		m = m.mul(affine{1, 0, 0, 1, v.X, v.Y})
+		g := &Group{Container: Container{Object: obj}}
+		if s, ok := target.(*Symbol); ok {
+			g.ElemList = f.list(s.ElemList, m, depth+1)
+		} else if t := f.elem(target, m, depth+1); t != nil {
+			g.ElemList = ElemList{t}
+		}
+		return g
+	case container:
+		c := shallowCopy(x)
+		cc := c.(container).container()
+		cc.Object = obj
+		cc.ElemList = f.list(v.container().ElemList, m, depth)
+		return c
+	}
+	segs, ok := shapeSegs(x)
+	if !ok {
+		return nil
+	}
+	transformPath(segs, m)
+	p := &path{D: formatSubpaths(flattenPath(segs, f.tol))}
+	p.Object = obj
+	return p
+}
+
+// formatSubpaths converts flattened subpaths into path data.
+func formatSubpaths(list []subpath) string {
+	var segs []pathSeg
+	for _, s := range list {
+		segs = append(segs, pointSegs(s.pts, s.closed)...)
+	}
+	return formatPath(segs)
+}
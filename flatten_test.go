@@ -0,0 +1,67 @@
+package svg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFlattenPath(t *testing.T) {
+	const r = 100
+	for _, tol := range []float64{1, 0.1, 0.01} {
+		list := flattenPath(ellipseSegs(0, 0, r, r), tol)
+		if len(list) != 1 || !list[0].closed {
+			t.Fatalf("tolerance %v: got %d subpaths, want one closed subpath", tol, len(list))
+		}
+		pts := list[0].pts
+		for i := 1; i < len(pts); i++ {
+			mid := [2]float64{(pts[i-1][0] + pts[i][0]) / 2, (pts[i-1][1] + pts[i][1]) / 2}
+			if d := r - dist(mid[0], mid[1]); d > tol {
+				t.Errorf("tolerance %v: line %d deviates by %v", tol, i, d)
+			}
+		}
+	}
+
+	segs, err := parsePath("M0,0 L10,0 M20,0 Q25,10 30,0 Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := flattenPath(segs, 0.1)
+	if len(list) != 2 || list[0].closed || !list[1].closed {
+		t.Fatalf("got %+v, want an open and a closed subpath", list)
+	}
+	if n := len(list[0].pts); n != 2 {
+		t.Errorf("got %d points within the first subpath, want 2", n)
+	}
+	if pt := list[1].pts[len(list[1].pts)-1]; pt != [2]float64{30, 0} {
+		t.Errorf("got end point %v, want 30,0", pt)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	d := NewDocument(nil)
+	defs := d.Defs()
+	defs.Circle(0, 0, 5).SetID("dot")
+	g := d.Group()
+	g.Translate(10, 10)
+	g.Rect(0, 0, 2, 3).SetID("r")
+	g.UseObject(1, 1, "dot")
+	g.Text(0, 0, "label")
+	d.Path("M0,0 C10,10 20,10 30,0").SetID("curve")
+	d.Flatten(0.5)
+
+	got := encodeString(t, d)
+	for _, want := range []string{
+		`<g><path d="M10,10 L12,10 L12,13 L10,13 Z" id="r"></path><g><path d="M16,11 L`,
+		`<path d="M0,0 L`,
+		`" id="curve"></path>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got %s, want it to contain %s", got, want)
+		}
+	}
+	for _, s := range []string{"<text", "<defs", "<use", "<circle", "transform", "C", "Q", `id="dot"`} {
+		if strings.Contains(got, s) {
+			t.Errorf("got %s, want it not to contain %s", got, s)
+		}
+	}
+}
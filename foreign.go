@@ -0,0 +1,37 @@
+package svg
+
+import (
+	"encoding/xml"
+)
+
+const xhtmlNameSpace = "http://www.w3.org/1999/xhtml"
+
+// ForeignObject is a <foreignObject> element, containing
+// XHTML content that is rendered by browsers within the
+// rectangle specified by X, Y, Width and Height.
+type ForeignObject struct {
+	XMLName xml.Name `xml:"foreignObject"`
+	X       float64  `xml:"x,attr,omitempty"`
+	Y       float64  `xml:"y,attr,omitempty"`
+	Width   Length   `xml:"width,attr,omitempty"`
+	Height  Length   `xml:"height,attr,omitempty"`
+	Object
+
+	// Content is the markup of the element's children,
+	// which is written verbatim. If it is not well-formed,
+	// encoding the document fails.
+	Content string `xml:",innerxml"`
+}
+
+// ForeignObject appends a <foreignObject> element placing the XHTML
+// markup xhtml, like the output of an html/template, into the
+// rectangle at x, y of size w×h. The markup is wrapped into a
+// <div> element declaring the XHTML namespace. It must be
+// well-formed XML, i.e. empty elements like <br/> must be closed,
+// and entities other than those predefined by XML must not be used.
+func (el *ElemList) ForeignObject(x, y, w, h float64, xhtml string) *ForeignObject {
+	f := &ForeignObject{X: x, Y: y, Width: Number(w), Height: Number(h)}
+	f.Content = `<div xmlns="` + xhtmlNameSpace + `">` + xhtml + `</div>`
+	el.append(f)
+	return f
+}
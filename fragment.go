@@ -0,0 +1,69 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+)
+
+// EncodeFragment returns the markup of a single element of the
+// document, including its children, without the surrounding <svg>
+// element, e.g. to be injected into an existing DOM. Elem is the
+// value returned when the element was created, like a *Container,
+// a *ShapeObject, or a *TextObject. Elements disabled using
+// Object.When or Container.WhenFunc are left out. Unless the
+// document is configured as Embedded, the SVG namespace is declared
+// at the fragment's root element, so that the fragment is also
+// valid when parsed as standalone XML.
+// The result may be post-processed using SelfCloseEmptyElements.
+func (d *Document) EncodeFragment(elem interface{}) ([]byte, error) {
+	e, ok := elem.(element)
+	if !ok {
+		return nil, errors.New("svg: fragment root is not an element")
+	}
+	x := findElement(d.ElemList, e.object())
+	if x == nil {
+		return nil, errors.New("svg: fragment root not found in document")
+	}
+	p, _ := d.preparer()
+	list := p.list(ElemList{x}, identity, false, false)
+	if len(list) == 0 {
+		return nil, nil
+	}
+	if err := firstNumberError(list); err != nil {
+		return nil, err
+	}
+	formatNumbers(list)
+	var b bytes.Buffer
+	enc := xml.NewEncoder(&b)
+	var err error
+	if d.conf.Embedded {
+		err = enc.Encode(list[0])
+	} else {
+		start := xml.StartElement{
+			Name: xml.Name{Local: elemName(list[0])},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: nameSpace}},
+		}
+		err = enc.EncodeElement(list[0], start)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// findElement returns the element within el, or its descendants,
+// whose Object is o.
+func findElement(el ElemList, o *Object) interface{} {
+	for _, x := range el {
+		if e, ok := x.(element); ok && e.object() == o {
+			return x
+		}
+		if c, ok := x.(container); ok {
+			if found := findElement(c.container().ElemList, o); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
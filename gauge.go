@@ -0,0 +1,116 @@
+package svg
+
+import (
+	"math"
+)
+
+// Gauge describes a dial gauge to be drawn using Container.Gauge.
+type Gauge struct {
+	Min, Max float64
+	Value    float64
+	Radius   float64
+
+	// StartAngle and EndAngle specify the angles, in degrees
+	// clockwise from the positive x axis, corresponding to Min and
+	// Max. If both are zero, the scale extends over 270 degrees,
+	// leaving the bottom part open.
+	StartAngle, EndAngle float64
+
+	// Bands are colored ranges along the outer edge of the gauge.
+	Bands []GaugeBand
+
+	// BandWidth is the width of the bands; it defaults to
+	// an eighth of the radius.
+	BandWidth float64
+
+	// Ticks contains the values where tick marks and labels are
+	// placed. If nil, about NumTicks values are computed.
+	Ticks    []float64
+	NumTicks int // defaults to 5
+
+	// Format, if set, is used to create tick labels.
+	Format func(float64) string
+}
+
+// GaugeBand is a colored range of a Gauge.
+type GaugeBand struct {
+	From, To float64
+
+	// Class is added to the band's class attribute,
+	// and may be used to specify its fill color.
+	Class string
+}
+
+// Gauge appends a group with class "gauge", containing a dial gauge
+// centered at cx, cy. The elements of the gauge are assigned
+// classes, which should be used to style them:
+// "gauge-band" for the bands, together with the band's own class,
+// "gauge-tick" for tick marks, "gauge-label" for tick labels,
+// "gauge-needle" for the needle, and "gauge-hub" for the circle
+// at its center.
+func (c *Container) Gauge(cx, cy float64, g *Gauge) *Container {
+	a0, a1 := g.StartAngle, g.EndAngle
+	if a0 == 0 && a1 == 0 {
+		a0, a1 = 135, 405
+	}
+	r := g.Radius
+	bw := g.BandWidth
+	if bw == 0 {
+		bw = r / 8
+	}
+	m := &PolarMapper{
+		CX:    cx,
+		CY:    cy,
+		R:     NewLinearScale(0, r, 0, r),
+		Theta: NewLinearScale(g.Min, g.Max, a0, a1),
+	}
+	clamp := func(v float64) float64 {
+		return math.Max(math.Min(v, math.Max(g.Min, g.Max)), math.Min(g.Min, g.Max))
+	}
+
+	gg := c.Group()
+	gg.SetClass("gauge")
+	for _, b := range g.Bands {
+		class := "gauge-band"
+		if b.Class != "" {
+			class += " " + b.Class
+		}
+		m.Sector(&gg.ElemList, r-bw, r, clamp(b.From), clamp(b.To)).SetClass(class)
+	}
+
+	ticks := g.Ticks
+	if ticks == nil {
+		n := g.NumTicks
+		if n == 0 {
+			n = 5
+		}
+		ticks = niceTicks(g.Min, g.Max, n)
+	}
+	format := g.Format
+	if format == nil {
+		format = decimalFormatter(ticks)
+	}
+	for _, v := range ticks {
+		x1, y1 := m.MapPolar(r-bw, v)
+		x2, y2 := m.MapPolar(r, v)
+		gg.Line(x1, y1, x2, y2).SetClass("gauge-tick")
+		x, y := m.MapPolar(r-bw-r/6, v)
+		t := gg.Text(x, y, format(v))
+		t.Anchor(AnchorMiddle)
+		t.Dy = EmUnits(0.32)
+		t.SetClass("gauge-label")
+	}
+
+	// needle, pointing to the value
+	theta := m.Theta.Map(clamp(g.Value))
+	tx, ty := polarPoint(cx, cy, r-bw/2, theta)
+	lx, ly := polarPoint(cx, cy, r/25, theta-90)
+	rx, ry := polarPoint(cx, cy, r/25, theta+90)
+	needle := gg.Polygon()
+	needle.Points = Points{{tx, ty}, {lx, ly}, {rx, ry}}
+	needle.SetClass("gauge-needle")
+	hub := &circle{X: cx, Y: cy, R: r / 15}
+	gg.append(hub)
+	hub.SetClass("gauge-hub")
+	return gg
+}
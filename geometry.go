@@ -0,0 +1,235 @@
+package svg
+
+import (
+	"math"
+)
+
+func (s *ShapeObject) shape() *ShapeObject {
+	return s
+}
+
+// shape is implemented by all element types embedding a ShapeObject.
+type shape interface {
+	shape() *ShapeObject
+}
+
+// shapeSegs returns the outline of a basic shape or path,
+// expressed as normalized path segments in the shape's local
+// coordinate system. If x is not a shape, or if its path data
+// cannot be parsed, false is returned.
+func shapeSegs(x interface{}) ([]pathSeg, bool) {
+	switch v := x.(type) {
+	case *line:
+		return []pathSeg{
+			{cmd: 'M', pts: [3][2]float64{{v.X1, v.Y1}}},
+			{cmd: 'L', pts: [3][2]float64{{v.X2, v.Y2}}},
+		}, true
+	case *PolyLine:
+		return pointSegs(v.Points, false), true
+	case *CompactPolyLine:
+		return pointSegs(v.Points.points(), false), true
+	case *polygon:
+		return pointSegs(v.Points, true), true
+	case *Rect:
+		return rectSegs(v), true
+	case *circle:
+		return ellipseSegs(v.X, v.Y, v.R, v.R), true
+	case *ellipse:
+		return ellipseSegs(v.X, v.Y, v.Rx, v.Ry), true
+	case *path:
+		segs, err := parsePath(v.D)
+		return segs, err == nil
+	}
+	return nil, false
+}
+
+func pointSegs(pts Points, closed bool) []pathSeg {
+	segs := make([]pathSeg, 0, len(pts)+1)
+	for i, pt := range pts {
+		cmd := byte('L')
+		if i == 0 {
+			cmd = 'M'
+		}
+		segs = append(segs, pathSeg{cmd: cmd, pts: [3][2]float64{pt}})
+	}
+	if closed && len(pts) != 0 {
+		segs = append(segs, pathSeg{cmd: 'Z'})
+	}
+	return segs
+}
+
+func rectSegs(r *Rect) []pathSeg {
+	x, y, w, h := r.X, r.Y, r.Width, r.Height
+	rx, ry := r.Rx, r.Ry
+	if ry == 0 {
+		ry = rx
+	} else if rx == 0 {
+		rx = ry
+	}
+	rx = math.Min(rx, w/2)
+	ry = math.Min(ry, h/2)
+	if rx <= 0 || ry <= 0 {
+		return pointSegs(Points{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}, true)
+	}
+	corner := func(segs []pathSeg, from, to [2]float64) []pathSeg {
+		return append(segs, arcToCubic(from, rx, ry, 0, false, true, to)...)
+	}
+	segs := []pathSeg{{cmd: 'M', pts: [3][2]float64{{x + rx, y}}}}
+	segs = append(segs, pathSeg{cmd: 'L', pts: [3][2]float64{{x + w - rx, y}}})
+	segs = corner(segs, [2]float64{x + w - rx, y}, [2]float64{x + w, y + ry})
+	segs = append(segs, pathSeg{cmd: 'L', pts: [3][2]float64{{x + w, y + h - ry}}})
+	segs = corner(segs, [2]float64{x + w, y + h - ry}, [2]float64{x + w - rx, y + h})
+	segs = append(segs, pathSeg{cmd: 'L', pts: [3][2]float64{{x + rx, y + h}}})
+	segs = corner(segs, [2]float64{x + rx, y + h}, [2]float64{x, y + h - ry})
+	segs = append(segs, pathSeg{cmd: 'L', pts: [3][2]float64{{x, y + ry}}})
+	segs = corner(segs, [2]float64{x, y + ry}, [2]float64{x + rx, y})
+	return append(segs, pathSeg{cmd: 'Z'})
+}
+
+// segsBBox returns the bounding box of the points within segs.
+// As the control points of Bézier curves are included, the box
+// may be larger than the actual bounding box of the path.
+func segsBBox(segs []pathSeg) (min, max [2]float64, ok bool) {
+	for i := range segs {
+		s := &segs[i]
+		for j := 0; j < s.npts(); j++ {
+			pt := s.pts[j]
+			if !ok {
+				min, max, ok = pt, pt, true
+				continue
+			}
+			min[0] = math.Min(min[0], pt[0])
+			min[1] = math.Min(min[1], pt[1])
+			max[0] = math.Max(max[0], pt[0])
+			max[1] = math.Max(max[1], pt[1])
+		}
+	}
+	return
+}
+
+// BBox is an axis-aligned bounding box.
+type BBox struct {
+	X, Y, Width, Height float64
+}
+
+func makeBBox(min, max [2]float64) BBox {
+	return BBox{X: min[0], Y: min[1], Width: max[0] - min[0], Height: max[1] - min[1]}
+}
+
+// BBox returns the bounding box of the shapes within the container,
+// and within its descendants, in the coordinate system of the
+// container's parent, i.e. including the container's own
+// transformation. Text and <use> elements, definitions, and elements
+// left out using When or WhenFunc are not taken into account, as
+// well as stroke widths. As the control points of curves are
+// included, the box may be larger than the actual bounding box.
+// If there are no shapes, false is returned.
+func (c *Container) BBox() (BBox, bool) {
+	m, ok := c.TransformList.matrix()
+	if !ok {
+		return BBox{}, false
+	}
+	var b bboxBuilder
+	b.list(c.ElemList, m, "")
+	return makeBBox(b.min, b.max), b.ok
+}
+
+// ElementBBox returns the bounding box of the element with the
+// specified ID, within the container or its descendants, in the
+// coordinate system of the container's parent, like BBox.
+func (c *Container) ElementBBox(id string) (BBox, bool) {
+	m, ok := c.TransformList.matrix()
+	if !ok {
+		return BBox{}, false
+	}
+	var b bboxBuilder
+	b.list(c.ElemList, m, id)
+	return makeBBox(b.min, b.max), b.ok
+}
+
+type bboxBuilder struct {
+	min, max [2]float64
+	ok       bool
+
+	// If fit is set, as by FitViewBox, <use> elements are resolved
+	// using ids, the sizes of texts are estimated, and named
+	// transformations are expanded. Elements that cannot be taken
+	// into account are reported by setting skipped.
+	fit      bool
+	doc      *Document
+	ids      map[string]interface{}
+	useDepth int
+	font     fontProps
+	skipped  bool
+}
+
+// list extends the box by the shapes within el, transformed by m.
+// If id is not empty, only the element with that ID, and its
+// descendants, are taken into account.
+func (b *bboxBuilder) list(el ElemList, parent affine, id string) {
+	for _, x := range el {
+		e, ok := x.(element)
+		if !ok || !included(x) {
+			continue
+		}
+		switch x.(type) {
+		case *Defs, *Symbol, *Pattern, *Marker, *Filter:
+			continue
+		}
+		obj := e.object()
+		tl := obj.TransformList
+		if b.fit {
+			tl = expandTransforms(tl, b.doc.transforms)
+		}
+		own, ok := tl.matrix()
+		if !ok || b.fit && len(obj.CSSTransform) != 0 {
+			b.skipped = b.fit
+			continue
+		}
+		m := parent.mul(own)
+		sel := id
+		if obj.ID == id {
+			sel = ""
+		}
+		if b.fit {
+			font := b.font
+			b.font = b.doc.fontProps(obj, font)
+			b.fitElem(x, m, sel)
+			b.font = font
+			continue
+		}
+		if c, ok := x.(container); ok {
+			b.list(c.container().ElemList, m, sel)
+			continue
+		}
+		if sel != "" {
+			continue
+		}
+		b.shape(x, m)
+	}
+}
+
+// shape extends the box by shape x, transformed by m.
+func (b *bboxBuilder) shape(x interface{}, m affine) {
+	segs, ok := shapeSegs(x)
+	if !ok {
+		return
+	}
+	transformPath(segs, m)
+	min, max, ok := segsBBox(segs)
+	if !ok {
+		return
+	}
+	b.add(min, max)
+}
+
+func (b *bboxBuilder) add(min, max [2]float64) {
+	if !b.ok {
+		b.min, b.max, b.ok = min, max, true
+		return
+	}
+	b.min[0] = math.Min(b.min[0], min[0])
+	b.min[1] = math.Min(b.min[1], min[1])
+	b.max[0] = math.Max(b.max[0], max[0])
+	b.max[1] = math.Max(b.max[1], max[1])
+}
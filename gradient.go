@@ -0,0 +1,189 @@
+package svg
+
+import (
+	"encoding/xml"
+)
+
+// GradientUnits defines the coordinate system of a gradient's
+// geometry attributes.
+type GradientUnits string
+
+const (
+	UserSpaceOnUse    GradientUnits = "userSpaceOnUse"
+	ObjectBoundingBox GradientUnits = "objectBoundingBox"
+)
+
+// SpreadMethod defines how a gradient is continued beyond
+// its first and last stop.
+type SpreadMethod string
+
+const (
+	SpreadPad     SpreadMethod = "pad"
+	SpreadReflect SpreadMethod = "reflect"
+	SpreadRepeat  SpreadMethod = "repeat"
+)
+
+// Gradient contains properties common to <linearGradient>
+// and <radialGradient> elements.
+type Gradient struct {
+	Units  GradientUnits `xml:"gradientUnits,attr,omitempty"`
+	Spread SpreadMethod  `xml:"spreadMethod,attr,omitempty"`
+	Object
+	Stops []GradientStop
+}
+
+// GradientStop is a <stop> element of a gradient.
+type GradientStop struct {
+	XMLName xml.Name `xml:"stop"`
+	Offset  float64  `xml:"offset,attr"`
+	Color   Paint    `xml:"stop-color,attr,omitempty"`
+	Opacity Length   `xml:"stop-opacity,attr,omitempty"`
+}
+
+// Stop adds a stop with the specified color at offset,
+// which is a value between 0 and 1.
+func (g *Gradient) Stop(offset float64, color Paint) *Gradient {
+	g.Stops = append(g.Stops, GradientStop{Offset: offset, Color: color})
+	return g
+}
+
+// StopOpacity adds a stop with the specified color and opacity at offset.
+func (g *Gradient) StopOpacity(offset float64, color Paint, opacity float64) *Gradient {
+	g.Stops = append(g.Stops, GradientStop{Offset: offset, Color: color, Opacity: Number(opacity)})
+	return g
+}
+
+// SetUnits sets the gradientUnits attribute.
+func (g *Gradient) SetUnits(u GradientUnits) *Gradient {
+	g.Units = u
+	return g
+}
+
+// SetSpread sets the spreadMethod attribute.
+func (g *Gradient) SetSpread(m SpreadMethod) *Gradient {
+	g.Spread = m
+	return g
+}
+
+// URL returns a reference to the gradient, to be used
+// as value of the fill or stroke property.
+func (g *Gradient) URL() Paint {
+	return Paint("url(#" + g.ID + ")")
+}
+
+// LinearGradient is a <linearGradient> element. If not set,
+// the gradient vector extends horizontally across the bounding
+// box of the element painted.
+type LinearGradient struct {
+	XMLName xml.Name `xml:"linearGradient"`
+	X1      Length   `xml:"x1,attr,omitempty"`
+	Y1      Length   `xml:"y1,attr,omitempty"`
+	X2      Length   `xml:"x2,attr,omitempty"`
+	Y2      Length   `xml:"y2,attr,omitempty"`
+	Gradient
+}
+
+// LinearGradient appends a <linearGradient> element with the
+// specified id, usually to a <defs> container.
+func (el *ElemList) LinearGradient(id string) *LinearGradient {
+	g := new(LinearGradient)
+	g.ID = id
+	el.append(g)
+	return g
+}
+
+// Vector sets the start and end point of the gradient vector.
+func (g *LinearGradient) Vector(x1, y1, x2, y2 Length) *LinearGradient {
+	g.X1, g.Y1 = x1, y1
+	g.X2, g.Y2 = x2, y2
+	return g
+}
+
+// RadialGradient is a <radialGradient> element.
+type RadialGradient struct {
+	XMLName xml.Name `xml:"radialGradient"`
+	Cx      Length   `xml:"cx,attr,omitempty"`
+	Cy      Length   `xml:"cy,attr,omitempty"`
+	R       Length   `xml:"r,attr,omitempty"`
+	Fx      Length   `xml:"fx,attr,omitempty"`
+	Fy      Length   `xml:"fy,attr,omitempty"`
+	Gradient
+}
+
+// RadialGradient appends a <radialGradient> element with the
+// specified id, usually to a <defs> container.
+func (el *ElemList) RadialGradient(id string) *RadialGradient {
+	g := new(RadialGradient)
+	g.ID = id
+	el.append(g)
+	return g
+}
+
+// Circle sets the center and radius of the end circle.
+func (g *RadialGradient) Circle(cx, cy, r Length) *RadialGradient {
+	g.Cx, g.Cy, g.R = cx, cy, r
+	return g
+}
+
+// Focus sets the focal point, where the gradient starts.
+func (g *RadialGradient) Focus(fx, fy Length) *RadialGradient {
+	g.Fx, g.Fy = fx, fy
+	return g
+}
+
+// GradientDirection is the direction of a gradient created by RampGradient.
+type GradientDirection int
+
+const (
+	LeftToRight GradientDirection = iota
+	RightToLeft
+	TopToBottom
+	BottomToTop
+)
+
+// rampSteps is the number of gradient intervals per ramp segment,
+// approximating interpolation modes other than linear sRGB.
+const rampSteps = 8
+
+// RampGradient appends a <linearGradient> to el, usually a <defs>
+// container, reproducing the ramp r along the bounding box of the
+// painted element in direction dir, and returns the paint referring
+// to it. The ID is derived from MakeID.
+// The stops of the ramp are copied exactly; since renderers
+// interpolate linearly in sRGB, intermediate stops are
+// added, sampled from the ramp.
+func (d *Document) RampGradient(el *ElemList, r *Ramp, dir GradientDirection) Paint {
+	g := el.LinearGradient(d.autoID("ramp"))
+	zero, one := Number(0), Number(1)
+	switch dir {
+	case LeftToRight:
+		g.Vector(zero, zero, one, zero)
+	case RightToLeft:
+		g.Vector(one, zero, zero, zero)
+	case TopToBottom:
+		g.Vector(zero, zero, zero, one)
+	case BottomToTop:
+		g.Vector(zero, one, zero, zero)
+	}
+	n := len(r.Stops)
+	if n == 0 {
+		return g.URL()
+	}
+	p0, p1 := r.position(0), r.position(n-1)
+	offset := func(t float64) float64 {
+		if p1 <= p0 {
+			return 0
+		}
+		return (t - p0) / (p1 - p0)
+	}
+	g.Stop(0, r.Stops[0])
+	for i := 1; i < n; i++ {
+		t0, t1 := r.position(i-1), r.position(i)
+		for k := 1; k < rampSteps && t1 > t0; k++ {
+			t := lerp(t0, t1, float64(k)/rampSteps)
+			g.Stop(offset(t), r.At(t))
+		}
+		g.Stop(offset(t1), r.Stops[i])
+	}
+	return g.URL()
+}
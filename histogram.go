@@ -0,0 +1,125 @@
+package svg
+
+import (
+	"math"
+)
+
+// Histogram describes a histogram to be drawn using
+// Container.Histogram.
+type Histogram struct {
+	Values []float64
+
+	// BinWidth, if set, specifies the width of the bins,
+	// whose edges are aligned to multiples of BinWidth.
+	// Otherwise the range of the values is divided into NumBins
+	// bins of equal width; if NumBins is zero, its value is
+	// derived from the number of values using Sturges' rule.
+	BinWidth float64
+	NumBins  int
+
+	// NoAxes, if set, suppresses the axes along the bottom
+	// and the left side of the histogram.
+	NoAxes bool
+
+	// AxisStyle is applied to the axes, as Axis.LineStyle.
+	AxisStyle Styling
+}
+
+// HistogramBin is a bin of a histogram, covering values
+// v with X0 <= v < X1; the last bin also includes its upper edge.
+type HistogramBin struct {
+	X0, X1 float64
+	Count  int
+}
+
+// Bins sorts the values of h into bins. NaN and infinite
+// values are ignored.
+func (h *Histogram) Bins() []HistogramBin {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	n := 0
+	for _, v := range h.Values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	var width float64
+	var nbins int
+	if h.BinWidth > 0 {
+		width = h.BinWidth
+		lo = math.Floor(lo/width) * width
+		nbins = int(math.Floor((hi-lo)/width)) + 1
+	} else {
+		nbins = h.NumBins
+		if nbins <= 0 {
+			nbins = int(math.Ceil(math.Log2(float64(n)))) + 1
+		}
+		width = (hi - lo) / float64(nbins)
+		if width == 0 {
+			width = 1
+			nbins = 1
+		}
+	}
+	bins := make([]HistogramBin, nbins)
+	for i := range bins {
+		bins[i].X0 = lo + float64(i)*width
+		bins[i].X1 = lo + float64(i+1)*width
+	}
+	for _, v := range h.Values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		i := int(math.Floor((v - lo) / width))
+		if i >= nbins {
+			i = nbins - 1
+		}
+		bins[i].Count++
+	}
+	return bins
+}
+
+// Histogram appends a group with class "histogram", containing the
+// bars of the histogram h, drawn as rectangles with class
+// "histogram-bar", filling the rectangle at x, y of the specified
+// size. Unless h.NoAxes is set, axes showing the bins' values and
+// their counts are drawn along the bottom and the left side.
+func (c *Container) Histogram(x, y, w, hh float64, h *Histogram) *Container {
+	g := c.Group()
+	g.SetClass("histogram")
+	bins := h.Bins()
+	if len(bins) == 0 {
+		return g
+	}
+	maxCount := 0
+	for _, b := range bins {
+		if b.Count > maxCount {
+			maxCount = b.Count
+		}
+	}
+	// round the top of the count axis up to a tick
+	step := math.Max(niceStep(float64(maxCount)/5), 1)
+	top := math.Max(math.Ceil(float64(maxCount)/step)*step, 1)
+
+	xs := NewLinearScale(bins[0].X0, bins[len(bins)-1].X1, x, x+w)
+	ys := NewLinearScale(0, top, y+hh, y)
+	bars := g.Group()
+	for _, b := range bins {
+		if b.Count == 0 {
+			continue
+		}
+		x0, x1 := xs.Map(b.X0), xs.Map(b.X1)
+		y0 := ys.Map(float64(b.Count))
+		bars.Rect(x0, y0, x1-x0, y+hh-y0).SetClass("histogram-bar")
+	}
+	if !h.NoAxes {
+		g.Axis(&Axis{Scale: xs, Position: AxisBottom, At: y + hh, LineStyle: h.AxisStyle})
+		g.Axis(&Axis{Scale: ys, Position: AxisLeft, At: x, LineStyle: h.AxisStyle,
+			Ticks: niceTicks(0, top, int(top/step))})
+	}
+	return g
+}
@@ -0,0 +1,165 @@
+package svg
+
+// Hit testing considers the geometry of filled areas only; strokes
+// are not taken into account, and the nonzero fill rule is assumed.
+// Points are specified in the coordinate system of the element's
+// parent, i.e. the element's own transformation is applied.
+
+// Contains reports whether the point x, y lies within the rectangle.
+func (r *Rect) Contains(x, y float64) bool {
+	pt, ok := localPoint(&r.Object, x, y)
+	if !ok {
+		return false
+	}
+	if r.Rx != 0 || r.Ry != 0 {
+		return segsContain(rectSegs(r), pt)
+	}
+	return pt[0] >= r.X && pt[0] <= r.X+r.Width && pt[1] >= r.Y && pt[1] <= r.Y+r.Height
+}
+
+// Contains reports whether the point x, y lies within the
+// area enclosed by the points of the polyline or polygon.
+func (line *PolyLine) Contains(x, y float64) bool {
+	pt, ok := localPoint(&line.Object, x, y)
+	if !ok {
+		return false
+	}
+	return winding(line.Points, pt) != 0
+}
+
+// Contains reports whether the point x, y lies within the shape
+// the ShapeObject belongs to, like a circle, an ellipse, or a path,
+// as returned by the respective constructors. For lines, and
+// ShapeObjects not part of an element, false is returned.
+func (s *ShapeObject) Contains(x, y float64) bool {
+	if s.owner == nil {
+		return false
+	}
+	return s.owner.Contains(x, y)
+}
+
+func (c *circle) Contains(x, y float64) bool {
+	pt, ok := localPoint(&c.Object, x, y)
+	if !ok {
+		return false
+	}
+	dx, dy := pt[0]-c.X, pt[1]-c.Y
+	return dx*dx+dy*dy <= c.R*c.R
+}
+
+func (e *ellipse) Contains(x, y float64) bool {
+	pt, ok := localPoint(&e.Object, x, y)
+	if !ok || e.Rx == 0 || e.Ry == 0 {
+		return false
+	}
+	dx, dy := (pt[0]-e.X)/e.Rx, (pt[1]-e.Y)/e.Ry
+	return dx*dx+dy*dy <= 1
+}
+
+func (p *path) Contains(x, y float64) bool {
+	pt, ok := localPoint(&p.Object, x, y)
+	if !ok {
+		return false
+	}
+	segs, err := parsePath(p.D)
+	if err != nil {
+		return false
+	}
+	return segsContain(segs, pt)
+}
+
+// hitTester is implemented by shapes supporting hit testing.
+type hitTester interface {
+	Contains(x, y float64) bool
+}
+
+// bindShape makes the ShapeObject of x, if x is a shape
+// implementing its own Contains method, refer to x, so that
+// ShapeObject.Contains may dispatch to it, and returns x.
+func bindShape(x interface{}) interface{} {
+	switch x.(type) {
+	case *Rect, *PolyLine, *polygon, *circle, *ellipse, *path:
+		x.(shape).shape().owner = x.(hitTester)
+	}
+	return x
+}
+
+// ElementAt returns the Object of the topmost shape within the
+// container, or within its descendants, containing the point x, y,
+// or nil, if there is no such shape. The point is specified in the
+// coordinate system of the container's parent.
+// Elements left out using When or WhenFunc, and definitions,
+// are not taken into account.
+// This may be used by applications keeping the Document as a
+// model, e.g. to resolve clicks on a rendered image.
+func (c *Container) ElementAt(x, y float64) *Object {
+	pt, ok := localPoint(&c.Object, x, y)
+	if !ok {
+		return nil
+	}
+	for i := len(c.ElemList) - 1; i >= 0; i-- {
+		e := c.ElemList[i]
+		if !included(e) {
+			continue
+		}
+		switch v := e.(type) {
+		case *Defs, *Symbol, *Pattern, *Marker, *Filter:
+		case container:
+			if hit := v.container().ElementAt(pt[0], pt[1]); hit != nil {
+				return hit
+			}
+		case hitTester:
+			if v.Contains(pt[0], pt[1]) {
+				return e.(element).object()
+			}
+		}
+	}
+	return nil
+}
+
+// localPoint transforms x, y into the local coordinate
+// system of the object.
+func localPoint(o *Object, x, y float64) ([2]float64, bool) {
+	pt := [2]float64{x, y}
+	if len(o.TransformList) == 0 {
+		return pt, true
+	}
+	m, ok := o.TransformList.matrix()
+	if !ok {
+		return pt, false
+	}
+	inv, ok := m.invert()
+	if !ok {
+		return pt, false
+	}
+	return inv.apply(pt), true
+}
+
+// segsContain reports whether pt lies within the area
+// enclosed by the path segments.
+func segsContain(segs []pathSeg, pt [2]float64) bool {
+	w := 0
+	for _, s := range flattenPath(segs, 0.01) {
+		w += winding(s.pts, pt)
+	}
+	return w != 0
+}
+
+// winding returns the winding number of the closed polygon
+// pts around pt.
+func winding(pts [][2]float64, pt [2]float64) int {
+	w := 0
+	n := len(pts)
+	for i := 0; i < n; i++ {
+		a, b := pts[i], pts[(i+1)%n]
+		side := (b[0]-a[0])*(pt[1]-a[1]) - (pt[0]-a[0])*(b[1]-a[1])
+		if a[1] <= pt[1] {
+			if b[1] > pt[1] && side > 0 {
+				w++
+			}
+		} else if b[1] <= pt[1] && side < 0 {
+			w--
+		}
+	}
+	return w
+}
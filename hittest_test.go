@@ -0,0 +1,47 @@
+package svg
+
+import "testing"
+
+func TestContains(t *testing.T) {
+	d := NewDocument(nil)
+	c := d.Circle(10, 10, 5)
+	e := d.Ellipse(0, 0, 4, 2)
+	e.Translate(50, 50)
+	p := d.Path("M0,0 L10,0 L10,10 Z")
+	p.Translate(100, 0)
+	l := d.Line(0, 0, 10, 10)
+	r := d.Rect(0, 0, 2, 2)
+
+	for _, tc := range []struct {
+		name string
+		s    interface{ Contains(x, y float64) bool }
+		x, y float64
+		want bool
+	}{
+		{"circle", c, 10, 10, true},
+		{"circle", c, 14, 10, true},
+		{"circle", c, 14, 14, false},
+		{"ellipse", e, 53, 50, true},
+		{"ellipse", e, 50, 53, false},
+		{"ellipse", e, 0, 0, false},
+		{"path", p, 109, 1, true},
+		{"path", p, 101, 9, false},
+		{"line", l, 5, 5, false},
+		{"rect", &r.ShapeObject, 1, 1, true},
+		{"rect", &r.ShapeObject, 3, 1, false},
+	} {
+		if got := tc.s.Contains(tc.x, tc.y); got != tc.want {
+			t.Errorf("%s: Contains(%v, %v) = %v, want %v", tc.name, tc.x, tc.y, got, tc.want)
+		}
+	}
+
+	if o := d.ElementAt(53, 50); o != &e.Object {
+		t.Errorf("ElementAt: got %v, want the ellipse", o)
+	}
+	if o := d.ElementAt(1, 1); o != &r.Object {
+		t.Errorf("ElementAt: got %v, want the rect", o)
+	}
+	if o := d.ElementAt(200, 200); o != nil {
+		t.Errorf("ElementAt: got %v, want nil", o)
+	}
+}
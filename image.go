@@ -0,0 +1,53 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"image"
+	"image/png"
+	"net/http"
+)
+
+// Image is an <image> element, embedding a raster image.
+type Image struct {
+	XMLName xml.Name `xml:"image"`
+	X       float64  `xml:"x,attr,omitempty"`
+	Y       float64  `xml:"y,attr,omitempty"`
+	Width   Length   `xml:"width,attr,omitempty"`
+	Height  Length   `xml:"height,attr,omitempty"`
+	Href    string   `xml:"href,attr,omitempty"`
+
+	// PreserveAspectRatio controls how the image is fitted into
+	// the rectangle, like "xMidYMid meet", the default, or "none".
+	PreserveAspectRatio string `xml:"preserveAspectRatio,attr,omitempty"`
+
+	Object
+}
+
+// Image appends an <image> element showing img, encoded as PNG
+// into a data URI, within the rectangle at x, y of size w×h.
+func (el *ElemList) Image(x, y, w, h float64, img image.Image) (*Image, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return el.ImageData(x, y, w, h, buf.Bytes()), nil
+}
+
+// ImageData appends an <image> element showing the encoded image
+// data, like the contents of a PNG or JPEG file, as a data URI,
+// within the rectangle at x, y of size w×h. The media type
+// is detected from the data.
+func (el *ElemList) ImageData(x, y, w, h float64, data []byte) *Image {
+	im := &Image{X: x, Y: y, Width: Number(w), Height: Number(h)}
+	im.Href = "data:" + http.DetectContentType(data) + ";base64," + base64.StdEncoding.EncodeToString(data)
+	el.append(im)
+	return im
+}
+
+// SetPreserveAspectRatio sets the preserveAspectRatio attribute.
+func (im *Image) SetPreserveAspectRatio(par string) *Image {
+	im.PreserveAspectRatio = par
+	return im
+}
@@ -0,0 +1,149 @@
+package svg
+
+import (
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MapArea describes a clickable area of an HTML image map,
+// corresponding to an element of a document rendered as
+// a raster image. The struct may also be encoded as JSON
+// to serve as a hit map.
+type MapArea struct {
+	ID     string `json:"id"`
+	Shape  string `json:"shape"` // "rect", "circle", or "poly"
+	Coords []int  `json:"coords"`
+	Href   string `json:"href,omitempty"`
+	Alt    string `json:"alt,omitempty"`
+}
+
+// MapAreas returns the outlines of the shapes having one of the
+// specified IDs, in pixel coordinates of a raster image of the
+// given size, rendered from the document. The viewBox, if set,
+// is mapped onto the image as defined by the default
+// preserveAspectRatio value, i.e. it is scaled uniformly and centered.
+// As the first matching area of an image map takes precedence,
+// areas are returned in reverse document order, i.e. topmost
+// elements first. Href and Alt fields are to be filled in by the
+// caller.
+func (d *Document) MapAreas(width, height float64, ids ...string) []MapArea {
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	m := identity
+	if vb := d.ViewBox; len(vb) == 4 && vb[2] > 0 && vb[3] > 0 {
+		s := math.Min(width/float64(vb[2]), height/float64(vb[3]))
+		tx := (width-float64(vb[2])*s)/2 - float64(vb[0])*s
+		ty := (height-float64(vb[3])*s)/2 - float64(vb[1])*s
+		m = affine{s, 0, 0, s, tx, ty}
+	}
+	if own, ok := d.TransformList.matrix(); ok {
+		m = m.mul(own)
+	}
+	var areas []MapArea
+	collectAreas(d.ElemList, m, want, &areas)
+	for i, j := 0, len(areas)-1; i < j; i, j = i+1, j-1 {
+		areas[i], areas[j] = areas[j], areas[i]
+	}
+	return areas
+}
+
+func collectAreas(el ElemList, parent affine, want map[string]bool, areas *[]MapArea) {
+	for _, x := range el {
+		e, ok := x.(element)
+		if !ok || !included(x) {
+			continue
+		}
+		switch x.(type) {
+		case *Defs, *Symbol, *Pattern, *Marker, *Filter:
+			continue
+		}
+		obj := e.object()
+		own, ok := obj.TransformList.matrix()
+		if !ok {
+			continue
+		}
+		m := parent.mul(own)
+		if c, ok := x.(container); ok {
+			collectAreas(c.container().ElemList, m, want, areas)
+			continue
+		}
+		if !want[obj.ID] {
+			continue
+		}
+		if a, ok := mapArea(x, m); ok {
+			a.ID = obj.ID
+			*areas = append(*areas, a)
+		}
+	}
+}
+
+func mapArea(x interface{}, m affine) (a MapArea, ok bool) {
+	switch v := x.(type) {
+	case *Rect:
+		if m.isAxisAligned() && v.Rx == 0 && v.Ry == 0 {
+			p1 := m.apply([2]float64{v.X, v.Y})
+			p2 := m.apply([2]float64{v.X + v.Width, v.Y + v.Height})
+			a.Shape = "rect"
+			a.Coords = roundCoords(
+				math.Min(p1[0], p2[0]), math.Min(p1[1], p2[1]),
+				math.Max(p1[0], p2[0]), math.Max(p1[1], p2[1]))
+			return a, true
+		}
+	case *circle:
+		if m.isSimilarity() {
+			c := m.apply([2]float64{v.X, v.Y})
+			r := v.R * math.Sqrt(math.Abs(m[0]*m[3]-m[1]*m[2]))
+			a.Shape = "circle"
+			a.Coords = roundCoords(c[0], c[1], r)
+			return a, true
+		}
+	}
+	segs, ok := shapeSegs(x)
+	if !ok {
+		return a, false
+	}
+	transformPath(segs, m)
+	sub := flattenPath(segs, 0.5)
+	if len(sub) == 0 || len(sub[0].pts) < 3 {
+		return a, false
+	}
+	a.Shape = "poly"
+	for _, pt := range sub[0].pts {
+		a.Coords = append(a.Coords, roundCoords(pt[0], pt[1])...)
+	}
+	return a, true
+}
+
+func roundCoords(f ...float64) []int {
+	c := make([]int, len(f))
+	for i, v := range f {
+		c[i] = int(math.Round(v))
+	}
+	return c
+}
+
+// WriteImageMap writes an HTML <map> element with the specified
+// name to w, containing an <area> element for each of the areas.
+func WriteImageMap(w io.Writer, name string, areas []MapArea) error {
+	var b strings.Builder
+	b.WriteString(`<map name="` + html.EscapeString(name) + `">` + "\n")
+	for _, a := range areas {
+		coords := make([]string, len(a.Coords))
+		for i, c := range a.Coords {
+			coords[i] = strconv.Itoa(c)
+		}
+		b.WriteString(`<area shape="` + a.Shape + `" coords="` + strings.Join(coords, ",") + `"`)
+		if a.Href != "" {
+			b.WriteString(` href="` + html.EscapeString(a.Href) + `"`)
+		}
+		b.WriteString(` alt="` + html.EscapeString(a.Alt) + `">` + "\n")
+	}
+	b.WriteString("</map>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,97 @@
+package svg
+
+import (
+	"strconv"
+	"strings"
+)
+
+type layer struct {
+	name string
+	c    *Container
+}
+
+type namedLayer struct {
+	name string
+	g    *Container
+}
+
+// Layer returns the top-level group registered under name, like
+// "background", "data", or "annotations", which is appended to the
+// document on first use. This way independent parts of a program
+// may contribute to the same layer without passing containers
+// around. Layers are drawn in the order they have been created;
+// use SetZ to order them otherwise.
+func (d *Document) Layer(name string) *Container {
+	for _, l := range d.namedLayers {
+		if l.name == name {
+			return l.g
+		}
+	}
+	g := d.Group()
+	d.namedLayers = append(d.namedLayers, namedLayer{name: name, g: g})
+	return g
+}
+
+// ToggleLayer registers container c as a layer with the given name,
+// that may be shown or hidden interactively using the controls
+// generated by LayerControls. If c has no ID yet, a unique one,
+// like "layer-1", will be created.
+func (d *Document) ToggleLayer(c *Container, name string) *Container {
+	if c.ID == "" {
+		c.ID = d.autoID("layer")
+	}
+	d.layers = append(d.layers, layer{name: name, c: c})
+	return c
+}
+
+// LayerControls appends a group containing a legend of checkboxes
+// at position x, y, one for each layer registered so far using
+// ToggleLayer. Clicking a checkbox toggles the display of the
+// corresponding layer. The necessary script code is embedded
+// into onclick attributes, so that the resulting document is
+// self-contained.
+func (d *Document) LayerControls(x, y int) *Container {
+	const lineHeight = 18
+
+	box := d.MakeStyle("layer-box", "fill:white;stroke:black")
+	mark := d.MakeStyle("layer-mark", "fill:none;stroke:black;stroke-width:2")
+	label := d.MakeStyle("layer-label", "font-size:12px")
+	item := d.MakeStyle("layer-toggle", "cursor:pointer")
+
+	g := d.Group()
+	g.TranslateInt(x, y)
+	for i, l := range d.layers {
+		it := g.Group()
+		it.TranslateInt(0, i*lineHeight)
+		it.WithStyle(item)
+		it.Attr("onclick", "var l=document.getElementById("+jsString(l.c.ID)+");"+
+			"var h=l.style.display=='none';"+
+			"l.style.display=h?'':'none';"+
+			"this.querySelector('path').style.visibility=h?'':'hidden'")
+		it.RectInt(0, 0, 12, 12).WithStyle(box)
+		it.Path("M2.5,6 L5,9 L10,3").WithStyle(mark)
+		it.TextInt(18, 10, l.name).WithStyle(label)
+	}
+	return g
+}
+
+// jsString returns s as a single-quoted JavaScript string literal.
+func jsString(s string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '\'':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < ' ' || r == 0x7F || r == 0x2028 || r == 0x2029:
+			b.WriteString(`\u`)
+			h := strconv.FormatInt(int64(r), 16)
+			b.WriteString(strings.Repeat("0", 4-len(h)) + h)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestJSString(t *testing.T) {
+	for s, want := range map[string]string{
+		"layer1":    `'layer1'`,
+		`a'b`:       `'a\'b'`,
+		`a\b`:       `'a\\b'`,
+		"a\nb":      `'a\u000ab'`,
+		"x\u2028y":  `'x\u2028y'`,
+		`');alert(`: `'\');alert('`,
+	} {
+		if got := jsString(s); got != want {
+			t.Errorf("jsString(%q) = %s, want %s", s, got, want)
+		}
+	}
+}
+
+func TestLayerControlsEscapeID(t *testing.T) {
+	d := NewDocument(nil)
+	g := d.Group()
+	g.SetID(`x');alert('1`)
+	d.ToggleLayer(g, "l")
+	d.LayerControls(0, 0)
+	var buf bytes.Buffer
+	if err := d.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dec := xml.NewDecoder(&buf)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			t.Fatal("onclick attribute not found")
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			for _, a := range se.Attr {
+				if a.Name.Local != "onclick" {
+					continue
+				}
+				want := `getElementById('x\');alert(\'1')`
+				if !strings.Contains(a.Value, want) {
+					t.Errorf("onclick = %s, want it to contain %s", a.Value, want)
+				}
+				return
+			}
+		}
+	}
+}
+
+func TestToggleLayerIDs(t *testing.T) {
+	d := NewDocument(nil)
+	a := d.ToggleLayer(d.Group(), "a")
+	b := d.ToggleLayer(d.Group(), "b")
+	c := d.Group()
+	c.SetID("own")
+	d.ToggleLayer(c, "c")
+	if a.ID == "" || a.ID == b.ID || !strings.HasPrefix(a.ID, "layer-") {
+		t.Errorf("got IDs %q and %q, want distinct generated IDs", a.ID, b.ID)
+	}
+	if c.ID != "own" {
+		t.Errorf("got ID %q, want own", c.ID)
+	}
+}
@@ -0,0 +1,204 @@
+package svg
+
+import (
+	"encoding/xml"
+)
+
+// PxUnits returns a Length that will be marshaled with a "px" suffix.
+func PxUnits(f float64) Length {
+	return unitLength{f, "px"}
+}
+
+// PtUnits returns a Length that will be marshaled with a "pt" suffix.
+func PtUnits(f float64) Length {
+	return unitLength{f, "pt"}
+}
+
+// PcUnits returns a Length that will be marshaled with a "pc" suffix.
+func PcUnits(f float64) Length {
+	return unitLength{f, "pc"}
+}
+
+// MmUnits returns a Length that will be marshaled with a "mm" suffix.
+func MmUnits(f float64) Length {
+	return unitLength{f, "mm"}
+}
+
+// CmUnits returns a Length that will be marshaled with a "cm" suffix.
+func CmUnits(f float64) Length {
+	return unitLength{f, "cm"}
+}
+
+// InUnits returns a Length that will be marshaled with an "in" suffix.
+func InUnits(f float64) Length {
+	return unitLength{f, "in"}
+}
+
+// VwUnits returns a Length that will be marshaled with a "vw" suffix,
+// i.e. in percent of the width of the initial containing block,
+// like the browser's viewport, if the document is embedded into
+// an HTML page.
+func VwUnits(f float64) Length {
+	return unitLength{f, "vw"}
+}
+
+// VhUnits returns a Length that will be marshaled with a "vh" suffix,
+// i.e. in percent of the height of the initial containing block.
+func VhUnits(f float64) Length {
+	return unitLength{f, "vh"}
+}
+
+// VminUnits returns a Length that will be marshaled with a "vmin"
+// suffix, i.e. in percent of the smaller of vw and vh.
+func VminUnits(f float64) Length {
+	return unitLength{f, "vmin"}
+}
+
+// VmaxUnits returns a Length that will be marshaled with a "vmax"
+// suffix, i.e. in percent of the larger of vw and vh.
+func VmaxUnits(f float64) Length {
+	return unitLength{f, "vmax"}
+}
+
+// RemUnits returns a Length that will be marshaled with a "rem"
+// suffix, i.e. relative to the font size of the root element, which
+// is that of the host page, if the document is embedded into HTML.
+func RemUnits(f float64) Length {
+	return unitLength{f, "rem"}
+}
+
+// ChUnits returns a Length that will be marshaled with a "ch" suffix,
+// i.e. relative to the advance width of the "0" glyph of the font.
+func ChUnits(f float64) Length {
+	return unitLength{f, "ch"}
+}
+
+// unitLength is a length in one of the CSS units
+// not covered by the other Length types.
+type unitLength struct {
+	v    float64
+	unit string
+}
+
+func (l unitLength) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return marshalLengthAttr(name, l.v, l.unit)
+}
+
+// pxPerUnit contains the sizes of the absolute units in CSS pixels,
+// which correspond to user units; "" stands for a Number.
+var pxPerUnit = map[string]float64{
+	"":   1,
+	"px": 1,
+	"pt": 96.0 / 72,
+	"pc": 16,
+	"in": 96,
+	"cm": 96 / 2.54,
+	"mm": 96 / 25.4,
+}
+
+// lengthValue returns the value and the unit of l,
+// or false, if l is not of a type defined by this package.
+func lengthValue(l Length) (v float64, unit string, ok bool) {
+	switch l := l.(type) {
+	case number:
+		return float64(l), "", true
+	case emUnits:
+		return float64(l), "em", true
+	case exUnits:
+		return float64(l), "ex", true
+	case percentage:
+		return float64(l), "%", true
+	case unitLength:
+		return l.v, l.unit, true
+	}
+	return 0, "", false
+}
+
+// makeLength returns a Length of value v in the specified unit.
+func makeLength(v float64, unit string) Length {
+	switch unit {
+	case "":
+		return number(v)
+	case "em":
+		return emUnits(v)
+	case "ex":
+		return exUnits(v)
+	case "%":
+		return percentage(v)
+	}
+	return unitLength{v, unit}
+}
+
+// compatible returns the values of a and b, converted to the unit
+// of a, if their units are compatible, i.e. equal, or both absolute.
+func compatible(a, b Length) (va, vb float64, unit string, ok bool) {
+	va, unit, ok = lengthValue(a)
+	if !ok {
+		return
+	}
+	vb, ub, ok := lengthValue(b)
+	if !ok || ub == unit {
+		return
+	}
+	sa, okA := pxPerUnit[unit]
+	sb, okB := pxPerUnit[ub]
+	if !okA || !okB {
+		return va, vb, unit, false
+	}
+	return va, vb * sb / sa, unit, true
+}
+
+// AddLengths returns the sum of a and b, in the unit of a, if their
+// units are compatible: equal, or both absolute, like mm and pt.
+// Numbers, i.e. user units, are treated as px. Differences can
+// be computed by adding a length scaled by -1, see ScaleLength.
+// Lengths of incompatible units, like a percentage and px, cannot
+// be added, since their sum depends on the context of the element.
+func AddLengths(a, b Length) (Length, bool) {
+	va, vb, unit, ok := compatible(a, b)
+	if !ok {
+		return nil, false
+	}
+	return makeLength(va+vb, unit), true
+}
+
+// ScaleLength returns l multiplied by f, in the unit of l.
+func ScaleLength(l Length, f float64) (Length, bool) {
+	v, unit, ok := lengthValue(l)
+	if !ok {
+		return nil, false
+	}
+	return makeLength(v*f, unit), true
+}
+
+// CompareLengths returns -1, 0, or +1, depending on whether a is
+// less than, equal to, or greater than b. False is returned
+// if their units are not compatible, see AddLengths.
+func CompareLengths(a, b Length) (int, bool) {
+	va, vb, _, ok := compatible(a, b)
+	switch {
+	case !ok:
+		return 0, false
+	case va < vb:
+		return -1, true
+	case va > vb:
+		return 1, true
+	}
+	return 0, true
+}
+
+// ConvertLength returns an absolute length l, or a Number, in
+// device pixels for a resolution of dpi dots per inch. A dpi of 96
+// results in CSS pixels, i.e. user units. False is returned for
+// relative lengths, like percentages, that depend on context.
+func ConvertLength(l Length, dpi float64) (float64, bool) {
+	v, unit, ok := lengthValue(l)
+	if !ok {
+		return 0, false
+	}
+	s, ok := pxPerUnit[unit]
+	if !ok {
+		return 0, false
+	}
+	return v * s * dpi / 96, true
+}
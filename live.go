@@ -0,0 +1,183 @@
+package svg
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LiveChart serves a document via HTTP, that keeps itself up to
+// date: the served document contains a small script subscribing to
+// a stream of server-sent events, through which the patch
+// operations computed by Diff are pushed whenever Update is called.
+// The document must be displayed standalone, e.g. in a browser tab,
+// or using an <object> or <iframe> element, since scripts of <img>
+// elements are not run.
+type LiveChart struct {
+	mu      sync.Mutex
+	doc     *Document
+	version int
+	clients map[chan []byte]bool
+}
+
+// NewLiveChart returns a LiveChart serving d.
+// Documents passed to NewLiveChart, or Update,
+// must not be modified afterwards.
+func NewLiveChart(d *Document) *LiveChart {
+	return &LiveChart{doc: d, clients: make(map[chan []byte]bool)}
+}
+
+// Update replaces the document, and sends the changes
+// to all connected clients.
+func (lc *LiveChart) Update(d *Document) error {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	ops, err := Diff(lc.doc, d)
+	if err != nil {
+		return err
+	}
+	lc.doc = d
+	lc.version++
+	msg, err := lc.message(ops)
+	if err != nil {
+		return err
+	}
+	for ch := range lc.clients {
+		select {
+		case ch <- msg:
+		default:
+			// The client is too slow; dropping it makes
+			// its browser reconnect, and reload the document.
+			delete(lc.clients, ch)
+			close(ch)
+		}
+	}
+	return nil
+}
+
+// message returns an event containing ops, and the current version.
+func (lc *LiveChart) message(ops []PatchOp) ([]byte, error) {
+	if ops == nil {
+		ops = []PatchOp{}
+	}
+	data, err := json.Marshal(ops)
+	if err != nil {
+		return nil, err
+	}
+	return []byte("id: " + strconv.Itoa(lc.version) + "\ndata: " + string(data) + "\n\n"), nil
+}
+
+// ServeHTTP serves the document, or, if the query contains
+// an "events" parameter, the event stream.
+func (lc *LiveChart) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["events"]; ok {
+		lc.serveEvents(w, r)
+		return
+	}
+	lc.mu.Lock()
+	d := *lc.doc
+	d.ElemList = append(ElemList(nil), lc.doc.ElemList...)
+	d.append(&script{Content: strings.Replace(liveScript, "VERSION", strconv.Itoa(lc.version), 1)})
+	buf, err := xml.Marshal(&d)
+	lc.mu.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(buf)
+}
+
+func (lc *LiveChart) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+
+	ch := make(chan []byte, 16)
+	lc.mu.Lock()
+	last := r.Header.Get("Last-Event-ID")
+	if last == "" {
+		last = r.URL.Query().Get("v")
+	}
+	if last != strconv.Itoa(lc.version) {
+		// The client missed updates; make it reload the document.
+		msg, err := lc.message([]PatchOp{{Op: "replace"}})
+		if err == nil {
+			ch <- msg
+		}
+	}
+	lc.clients[ch] = true
+	lc.mu.Unlock()
+	defer func() {
+		lc.mu.Lock()
+		if lc.clients[ch] {
+			delete(lc.clients, ch)
+		}
+		lc.mu.Unlock()
+	}()
+
+	flusher.Flush()
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if _, err := w.Write(msg); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+type script struct {
+	XMLName xml.Name `xml:"script"`
+	Content string   `xml:",cdata"`
+}
+
+// liveScript applies patch operations received from the event stream.
+const liveScript = `
+(function() {
+var svg = document.documentElement;
+function elem(id) { return id ? document.getElementById(id) : svg; }
+function parse(m) {
+	var d = new DOMParser().parseFromString('<svg xmlns="http://www.w3.org/2000/svg">' + m + '</svg>', 'image/svg+xml');
+	return document.importNode(d.documentElement.firstElementChild, true);
+}
+var es = new EventSource(location.pathname + '?events&v=VERSION');
+es.onmessage = function(ev) {
+	JSON.parse(ev.data).forEach(function(op) {
+		var e = elem(op.id);
+		switch (op.op) {
+		case 'remove':
+			if (e) e.remove();
+			break;
+		case 'insert':
+			var p = elem(op.parent);
+			p.insertBefore(parse(op.markup), p.children[op.index || 0] || null);
+			break;
+		case 'replace':
+			if (!op.id) { es.close(); location.reload(); return; }
+			if (e) e.replaceWith(parse(op.markup));
+			break;
+		case 'attrs':
+			for (var k in op.set || {}) e.setAttribute(k, op.set[k]);
+			(op.remove || []).forEach(function(k) { e.removeAttribute(k); });
+			break;
+		}
+	});
+};
+})();
+`
@@ -0,0 +1,70 @@
+package svg
+
+import (
+	"sort"
+	"strconv"
+)
+
+// LODGroup is a group containing alternative representations
+// of the same content for different levels of detail. Only one
+// of the representations is displayed at a time, depending on
+// the width of the viewport the document is rendered into,
+// which is controlled by CSS media queries within the embedded
+// stylesheet.
+type LODGroup struct {
+	Container *Container
+	name      string
+	levels    []lodLevel
+}
+
+type lodLevel struct {
+	minWidth float64
+	c        *Container
+}
+
+// LevelOfDetail appends a group to el, that may be populated with
+// alternative representations of content using the Level method.
+// The name is used to derive class names of the representations,
+// and should be unique within the document.
+func (d *Document) LevelOfDetail(el *ElemList, name string) *LODGroup {
+	l := &LODGroup{Container: el.Group(), name: name}
+	d.lods = append(d.lods, l)
+	return l
+}
+
+// Level adds a representation to the group that is displayed if
+// the width of the viewport, in CSS pixels, is at least minWidth,
+// and less than the minWidth of the level next in size.
+func (l *LODGroup) Level(minWidth float64) *Container {
+	c := l.Container.Group()
+	c.SetClass(l.name + "-lod" + strconv.Itoa(len(l.levels)))
+	l.levels = append(l.levels, lodLevel{minWidth: minWidth, c: c})
+	return c
+}
+
+// lodStyle returns the stylesheet rules controlling the display
+// of the levels of detail registered within the document.
+func (d *Document) lodStyle() string {
+	scope := ""
+	if d.conf != nil && d.conf.Scoped && d.ID != "" {
+		scope = "#" + d.ID + " "
+	}
+	s := ""
+	for _, l := range d.lods {
+		levels := make([]lodLevel, len(l.levels))
+		copy(levels, l.levels)
+		sort.SliceStable(levels, func(i, j int) bool {
+			return levels[i].minWidth < levels[j].minWidth
+		})
+		for i, lv := range levels {
+			rule := " {" + scope + "." + lv.c.Class + " {display:none}}"
+			if i != 0 {
+				s += " @media not all and (min-width: " + formatFloat(lv.minWidth) + "px)" + rule
+			}
+			if i < len(levels)-1 {
+				s += " @media (min-width: " + formatFloat(levels[i+1].minWidth) + "px)" + rule
+			}
+		}
+	}
+	return s
+}
@@ -0,0 +1,73 @@
+package svg
+
+import (
+	"encoding/xml"
+)
+
+// MarkerUnits defines the coordinate system of a marker's
+// width, height, and contents.
+type MarkerUnits string
+
+const (
+	// MarkerStrokeWidth scales the marker by the
+	// stroke width of the referencing shape.
+	MarkerStrokeWidth    MarkerUnits = "strokeWidth"
+	MarkerUserSpaceOnUse MarkerUnits = "userSpaceOnUse"
+)
+
+const (
+	// OrientAuto rotates markers in the direction of the path.
+	OrientAuto = "auto"
+
+	// OrientAutoStartReverse is like OrientAuto, but rotates
+	// the start marker by 180 degrees, which allows using
+	// the same arrowhead at both ends of a line.
+	OrientAutoStartReverse = "auto-start-reverse"
+)
+
+// Marker is a <marker> element, a container for graphics,
+// like arrowheads, drawn at the vertices of shapes referencing
+// it using SetMarkerStart, SetMarkerMid, or SetMarkerEnd.
+type Marker struct {
+	XMLName xml.Name `xml:"marker"`
+
+	// Width and Height default to 3, if nil.
+	Width   Length `xml:"markerWidth,attr,omitempty"`
+	Height  Length `xml:"markerHeight,attr,omitempty"`
+	ViewBox Ints   `xml:"viewBox,attr,omitempty"`
+
+	// RefX and RefY specify the point of the marker
+	// that is placed onto the vertex.
+	RefX float64 `xml:"refX,attr,omitempty"`
+	RefY float64 `xml:"refY,attr,omitempty"`
+
+	// Orient is either an angle in degrees,
+	// OrientAuto, or OrientAutoStartReverse.
+	Orient string      `xml:"orient,attr,omitempty"`
+	Units  MarkerUnits `xml:"markerUnits,attr,omitempty"`
+
+	Container
+}
+
+// Marker appends a <marker> element with the specified id,
+// size and reference point to el, which is usually a <defs>
+// container. Child elements are added to the returned
+// marker's Container.
+func (el *ElemList) Marker(id string, w, h, refX, refY float64) *Marker {
+	m := &Marker{Width: Number(w), Height: Number(h), RefX: refX, RefY: refY}
+	m.ID = id
+	el.append(m)
+	return m
+}
+
+// SetOrient sets the orient attribute.
+func (m *Marker) SetOrient(orient string) *Marker {
+	m.Orient = orient
+	return m
+}
+
+// SetUnits sets the markerUnits attribute.
+func (m *Marker) SetUnits(u MarkerUnits) *Marker {
+	m.Units = u
+	return m
+}
@@ -0,0 +1,158 @@
+package svg
+
+import (
+	"encoding/xml"
+	"errors"
+	"time"
+)
+
+const (
+	rdfNameSpace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	dcNameSpace  = "http://purl.org/dc/elements/1.1/"
+	ccNameSpace  = "http://creativecommons.org/ns#"
+)
+
+// Metadata is a <metadata> element, carrying information about
+// the document, like its creator and license, which is encoded
+// as an RDF description using the Dublin Core vocabulary,
+// in the same form as used by Inkscape. Empty fields
+// are left out.
+type Metadata struct {
+	ID string
+
+	Title       string
+	Description string
+	Creator     string
+	Date        string // like "2006-01-02"
+
+	// License is the URL of the license, like
+	// "http://creativecommons.org/licenses/by/4.0/".
+	License string
+}
+
+// Metadata appends a <metadata> element. It is usually
+// placed at the start of a document.
+func (el *ElemList) Metadata() *Metadata {
+	m := new(Metadata)
+	el.append(m)
+	return m
+}
+
+// SetTitle sets the title of the work.
+func (m *Metadata) SetTitle(title string) *Metadata {
+	m.Title = title
+	return m
+}
+
+// SetDescription sets a description of the work.
+func (m *Metadata) SetDescription(desc string) *Metadata {
+	m.Description = desc
+	return m
+}
+
+// SetCreator sets the name of the creator of the work.
+func (m *Metadata) SetCreator(name string) *Metadata {
+	m.Creator = name
+	return m
+}
+
+// SetDate sets the date of the work, formatted as YYYY-MM-DD.
+func (m *Metadata) SetDate(t time.Time) *Metadata {
+	m.Date = t.Format("2006-01-02")
+	return m
+}
+
+// SetLicense sets the URL of the license of the work.
+func (m *Metadata) SetLicense(url string) *Metadata {
+	m.License = url
+	return m
+}
+
+func (m *Metadata) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Local: "metadata"}}
+	if m.ID != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "id"}, Value: m.ID})
+	}
+	rdf := xml.StartElement{Name: xml.Name{Local: "rdf:RDF"}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: "xmlns:rdf"}, Value: rdfNameSpace},
+		{Name: xml.Name{Local: "xmlns:dc"}, Value: dcNameSpace},
+		{Name: xml.Name{Local: "xmlns:cc"}, Value: ccNameSpace},
+	}}
+	work := xml.StartElement{Name: xml.Name{Local: "cc:Work"}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: "rdf:about"}, Value: ""},
+	}}
+	tokens := []xml.Token{start, rdf, work}
+	prop := func(name, value string) {
+		if value != "" {
+			el := xml.StartElement{Name: xml.Name{Local: name}}
+			tokens = append(tokens, el, xml.CharData(value), el.End())
+		}
+	}
+	prop("dc:title", m.Title)
+	prop("dc:description", m.Description)
+	if m.Creator != "" {
+		creator := xml.StartElement{Name: xml.Name{Local: "dc:creator"}}
+		agent := xml.StartElement{Name: xml.Name{Local: "cc:Agent"}}
+		tokens = append(tokens, creator, agent)
+		prop("dc:title", m.Creator)
+		tokens = append(tokens, agent.End(), creator.End())
+	}
+	prop("dc:date", m.Date)
+	if m.License != "" {
+		lic := xml.StartElement{Name: xml.Name{Local: "cc:license"}, Attr: []xml.Attr{
+			{Name: xml.Name{Local: "rdf:resource"}, Value: m.License},
+		}}
+		tokens = append(tokens, lic, lic.End())
+	}
+	tokens = append(tokens, work.End(), rdf.End(), start.End())
+	for _, t := range tokens {
+		if err := e.EncodeToken(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildAttrs returns the non-empty fields of m as build log attributes.
+func (m *Metadata) buildAttrs() []BuildAttr {
+	var attrs []BuildAttr
+	for _, a := range m.fields() {
+		if *a.value != "" {
+			attrs = append(attrs, BuildAttr{a.name, *a.value})
+		}
+	}
+	return attrs
+}
+
+// replayMetadata creates a Metadata from build log attributes.
+func replayMetadata(attrs []BuildAttr) (*Metadata, error) {
+	m := new(Metadata)
+	fields := m.fields()
+L:
+	for _, a := range attrs {
+		for _, f := range fields {
+			if f.name == a.Name {
+				*f.value = a.Value
+				continue L
+			}
+		}
+		return nil, errors.New("svg: build log: unknown metadata attribute " + a.Name)
+	}
+	return m, nil
+}
+
+type metadataField struct {
+	name  string
+	value *string
+}
+
+func (m *Metadata) fields() []metadataField {
+	return []metadataField{
+		{"id", &m.ID},
+		{"title", &m.Title},
+		{"description", &m.Description},
+		{"creator", &m.Creator},
+		{"date", &m.Date},
+		{"license", &m.License},
+	}
+}
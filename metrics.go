@@ -0,0 +1,52 @@
+package svg
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// TextMeasurer estimates the width of text rendered
+// using the specified font size.
+type TextMeasurer interface {
+	TextWidth(s string, fontSize float64) float64
+}
+
+// AvgCharWidth is a simple TextMeasurer, assuming that each
+// character has the same width, as a fraction of the font size.
+type AvgCharWidth float64
+
+func (w AvgCharWidth) TextWidth(s string, fontSize float64) float64 {
+	return float64(utf8.RuneCountInString(s)) * float64(w) * fontSize
+}
+
+// DefaultMeasurer is used by helpers needing text metrics,
+// if no other TextMeasurer has been specified. It is a rough
+// approximation for common sans-serif fonts.
+var DefaultMeasurer TextMeasurer = AvgCharWidth(0.55)
+
+// WrapText splits s into lines not exceeding maxWidth, if possible,
+// breaking at white space. Words that are wider than maxWidth are
+// placed on lines of their own. If m is nil, DefaultMeasurer is used.
+func WrapText(s string, maxWidth, fontSize float64, m TextMeasurer) []string {
+	if m == nil {
+		m = DefaultMeasurer
+	}
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			if line == "" {
+				line = word
+				continue
+			}
+			if m.TextWidth(line+" "+word, fontSize) > maxWidth {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,62 @@
+package svg
+
+import (
+	"sort"
+	"strings"
+)
+
+// ModuleMatrix appends a path rendering a two-dimensional matrix of
+// modules, like the output of barcode or QR code encoders, with the
+// top left corner at x, y. Each true element of m is drawn as a
+// filled square of the size module. To keep the output small,
+// adjacent modules within a row are merged into runs, and identical
+// runs within consecutive rows are merged into rectangles.
+func (el *ElemList) ModuleMatrix(x, y, module float64, m [][]bool) *ShapeObject {
+	type run struct {
+		col, n int
+	}
+	// open maps runs of the previous row onto the
+	// row index where the rectangle started
+	open := make(map[run]int)
+	var b strings.Builder
+	flush := func(r run, row0, row1 int) {
+		b.WriteString("M" + formatFloat(x+float64(r.col)*module) + "," + formatFloat(y+float64(row0)*module))
+		b.WriteString("h" + formatFloat(float64(r.n)*module))
+		b.WriteString("v" + formatFloat(float64(row1-row0)*module))
+		b.WriteString("h" + formatFloat(-float64(r.n)*module) + "z")
+	}
+	for row := 0; row <= len(m); row++ {
+		next := make(map[run]int)
+		if row < len(m) {
+			line := m[row]
+			for col := 0; col < len(line); {
+				if !line[col] {
+					col++
+					continue
+				}
+				r := run{col: col}
+				for col < len(line) && line[col] {
+					col++
+					r.n++
+				}
+				start, ok := open[r]
+				if !ok {
+					start = row
+				}
+				next[r] = start
+				delete(open, r)
+			}
+		}
+		done := make([]run, 0, len(open))
+		for r := range open {
+			done = append(done, r)
+		}
+		// sort to get deterministic output
+		sort.Slice(done, func(i, j int) bool { return done[i].col < done[j].col })
+		for _, r := range done {
+			flush(r, open[r], row)
+		}
+		open = next
+	}
+	return el.Path(b.String())
+}
@@ -0,0 +1,177 @@
+package svg
+
+// MorphPaths converts the path data from and to into equivalent
+// path data having the same structure, i.e. the same number of
+// subpaths, each consisting of the same number of segments of the
+// same type, so that they may be used as from and to values of an
+// <animate> element, or CSS path() morphs, which interpolate the
+// numbers of two paths pairwise.
+//
+// All segments are converted to cubic Bézier curves; paths with
+// fewer segments are adjusted by splitting their longest curves.
+// Missing subpaths are added as degenerate subpaths, collapsed into
+// the end point of the last existing one. Subpaths are closed only
+// if they are closed in both paths.
+func MorphPaths(from, to string) (string, string, error) {
+	sa, err := morphSubpaths(from)
+	if err != nil {
+		return "", "", err
+	}
+	sb, err := morphSubpaths(to)
+	if err != nil {
+		return "", "", err
+	}
+	for len(sa) < len(sb) {
+		sa = append(sa, degenerateSubpath(sa, len(sb[len(sa)].curves)))
+	}
+	for len(sb) < len(sa) {
+		sb = append(sb, degenerateSubpath(sb, len(sa[len(sb)].curves)))
+	}
+	var segsA, segsB []pathSeg
+	for i := range sa {
+		a, b := &sa[i], &sb[i]
+		for len(a.curves) < len(b.curves) {
+			a.splitLongest()
+		}
+		for len(b.curves) < len(a.curves) {
+			b.splitLongest()
+		}
+		closed := a.closed && b.closed
+		segsA = a.appendSegs(segsA, closed)
+		segsB = b.appendSegs(segsB, closed)
+	}
+	return formatPath(segsA), formatPath(segsB), nil
+}
+
+// morphSubpath is a subpath consisting of cubic curves only.
+type morphSubpath struct {
+	start  [2]float64
+	curves [][3][2]float64
+	closed bool
+}
+
+// morphSubpaths parses path data into subpaths of cubic curves.
+func morphSubpaths(d string) ([]morphSubpath, error) {
+	segs, err := parsePath(d)
+	if err != nil {
+		return nil, err
+	}
+	var list []morphSubpath
+	var cur *morphSubpath
+	var pos [2]float64
+	for _, s := range segs {
+		if s.cmd == 'M' {
+			// consecutive moves don't add empty subpaths
+			if cur == nil || len(cur.curves) != 0 {
+				list = append(list, morphSubpath{})
+				cur = &list[len(list)-1]
+			}
+			cur.start = s.pts[0]
+			pos = s.pts[0]
+			continue
+		}
+		if cur == nil {
+			list = append(list, morphSubpath{start: pos})
+			cur = &list[len(list)-1]
+		}
+		switch s.cmd {
+		case 'L':
+			cur.curves = append(cur.curves, lineCurve(pos, s.pts[0]))
+		case 'Q':
+			c1 := [2]float64{pos[0] + 2.0/3*(s.pts[0][0]-pos[0]), pos[1] + 2.0/3*(s.pts[0][1]-pos[1])}
+			c2 := [2]float64{s.pts[1][0] + 2.0/3*(s.pts[0][0]-s.pts[1][0]), s.pts[1][1] + 2.0/3*(s.pts[0][1]-s.pts[1][1])}
+			cur.curves = append(cur.curves, [3][2]float64{c1, c2, s.pts[1]})
+		case 'C':
+			cur.curves = append(cur.curves, s.pts)
+		case 'Z':
+			if pos != cur.start {
+				cur.curves = append(cur.curves, lineCurve(pos, cur.start))
+			}
+			cur.closed = true
+			pos = cur.start
+			// a segment following Z starts a new subpath
+			cur = nil
+			continue
+		}
+		pos = s.end(cur.start)
+	}
+	return list, nil
+}
+
+// lineCurve returns the control and end points of a cubic
+// curve describing a straight line from p0 to p1.
+func lineCurve(p0, p1 [2]float64) [3][2]float64 {
+	return [3][2]float64{
+		{p0[0] + (p1[0]-p0[0])/3, p0[1] + (p1[1]-p0[1])/3},
+		{p0[0] + (p1[0]-p0[0])*2/3, p0[1] + (p1[1]-p0[1])*2/3},
+		p1,
+	}
+}
+
+// degenerateSubpath returns a subpath of n curves
+// collapsed into the end point of the last subpath of list.
+func degenerateSubpath(list []morphSubpath, n int) morphSubpath {
+	var pt [2]float64
+	if len(list) != 0 {
+		last := &list[len(list)-1]
+		pt = last.end()
+		if last.closed {
+			pt = last.start
+		}
+	}
+	s := morphSubpath{start: pt}
+	for i := 0; i < n; i++ {
+		s.curves = append(s.curves, [3][2]float64{pt, pt, pt})
+	}
+	return s
+}
+
+func (s *morphSubpath) end() [2]float64 {
+	if n := len(s.curves); n != 0 {
+		return s.curves[n-1][2]
+	}
+	return s.start
+}
+
+// splitLongest splits the curve with the longest control polygon
+// in halves. A subpath without curves gets a zero-length one.
+func (s *morphSubpath) splitLongest() {
+	if len(s.curves) == 0 {
+		s.curves = append(s.curves, [3][2]float64{s.start, s.start, s.start})
+		return
+	}
+	best, bestLen := 0, -1.0
+	p0 := s.start
+	var start0 [2]float64
+	for i, c := range s.curves {
+		l := dist(c[0][0]-p0[0], c[0][1]-p0[1]) +
+			dist(c[1][0]-c[0][0], c[1][1]-c[0][1]) +
+			dist(c[2][0]-c[1][0], c[2][1]-c[1][1])
+		if l > bestLen {
+			best, bestLen, start0 = i, l, p0
+		}
+		p0 = c[2]
+	}
+	c := s.curves[best]
+	mid := func(a, b [2]float64) [2]float64 {
+		return [2]float64{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}
+	}
+	p01, p12, p23 := mid(start0, c[0]), mid(c[0], c[1]), mid(c[1], c[2])
+	p012, p123 := mid(p01, p12), mid(p12, p23)
+	m := mid(p012, p123)
+	first := [3][2]float64{p01, p012, m}
+	second := [3][2]float64{p123, p23, c[2]}
+	s.curves = append(s.curves[:best+1], s.curves[best:]...)
+	s.curves[best], s.curves[best+1] = first, second
+}
+
+func (s *morphSubpath) appendSegs(segs []pathSeg, closed bool) []pathSeg {
+	segs = append(segs, pathSeg{cmd: 'M', pts: [3][2]float64{s.start}})
+	for _, c := range s.curves {
+		segs = append(segs, pathSeg{cmd: 'C', pts: c})
+	}
+	if closed {
+		segs = append(segs, pathSeg{cmd: 'Z'})
+	}
+	return segs
+}
@@ -0,0 +1,111 @@
+package svg
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// MultiDocument builds a set of named artboards, i.e. separate
+// SVG documents, like the icons of an icon set, that share
+// definitions and styles during construction.
+type MultiDocument struct {
+	// Defs contains the shared definitions.
+	Defs *Container
+
+	shared *Document
+	boards []*artboard
+}
+
+type artboard struct {
+	name string
+	doc  *Document
+}
+
+// NewMultiDocument creates an empty MultiDocument. The
+// configuration is used for all artboards.
+func NewMultiDocument(c *Conf) *MultiDocument {
+	m := &MultiDocument{shared: NewDocument(c)}
+	m.Defs = m.shared.Defs()
+	return m
+}
+
+// MakeStyle creates a style shared by all artboards,
+// like Document.MakeStyle.
+func (m *MultiDocument) MakeStyle(name, style string) Styling {
+	return m.shared.MakeStyle(name, style)
+}
+
+// Artboard adds a new artboard, and returns its document,
+// which may be populated as usual.
+func (m *MultiDocument) Artboard(name string) *Document {
+	d := NewDocument(m.shared.conf)
+	m.boards = append(m.boards, &artboard{name: name, doc: d})
+	return d
+}
+
+// Document returns the complete document of the named artboard,
+// or nil, if it does not exist. The shared stylesheet is
+// prepended to the artboard's own one, and the shared definitions
+// referenced from the artboard, directly or indirectly,
+// are placed into a <defs> element at the start of the document.
+// The artboard itself is not modified.
+func (m *MultiDocument) Document(name string) *Document {
+	for _, b := range m.boards {
+		if b.name == name {
+			return m.assemble(b.doc)
+		}
+	}
+	return nil
+}
+
+func (m *MultiDocument) assemble(a *Document) *Document {
+	p := &pruner{
+		defs: make(map[string]interface{}),
+		live: make(map[string]bool),
+	}
+	var shared ElemList
+	for _, x := range m.Defs.ElemList {
+		if e, ok := x.(element); ok && e.object().ID != "" {
+			p.defs[e.object().ID] = x
+			shared = append(shared, x)
+		}
+	}
+	style := strings.TrimSpace(m.shared.Style + " " + a.Style)
+	scanRefs(style, p.ref)
+	collectRefs(reflect.ValueOf(&a.Object).Elem(), p.ref)
+	p.visit(a.ElemList, false)
+	p.drain()
+
+	defs := new(Defs)
+	for _, x := range shared {
+		if p.live[x.(element).object().ID] {
+			defs.append(x)
+		}
+	}
+	d := *a
+	d.Style = style
+	if len(defs.ElemList) != 0 {
+		d.ElemList = append(ElemList{defs}, a.ElemList...)
+	}
+	return &d
+}
+
+// Encode writes the document of each artboard into a file within
+// directory dir, named after the artboard, with suffix ".svg".
+func (m *MultiDocument) Encode(dir string) error {
+	for _, b := range m.boards {
+		var buf bytes.Buffer
+		err := m.assemble(b.doc).Encode(&buf, Indent("", "\t"), SelfClose())
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, b.name+".svg"), buf.Bytes(), 0666)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package svg
+
+// DefineTransform defines a named transformation, like a standard
+// placement or orientation, that objects may refer to using
+// Object.UseTransform. Changing a definition affects all objects
+// referring to it. Definitions may refer to other definitions.
+func (d *Document) DefineTransform(name string, tl TransformList) {
+	if d.transforms == nil {
+		d.transforms = make(map[string]TransformList)
+	}
+	d.transforms[name] = tl
+}
+
+// UseTransform appends a reference to the transformation named
+// name, defined using Document.DefineTransform, to the object's
+// transformations. The reference is replaced by the definition when
+// the document is encoded; until then, functions interpreting
+// transformations, like those computing bounding boxes, treat it like
+// an unknown transformation. References to undefined names are
+// left out.
+func (o *Object) UseTransform(name string) *Object {
+	o.TransformList = append(o.TransformList, Transform{Args: []TransformArg{transformRef(name)}})
+	return o
+}
+
+// transformRef is the argument of a Transform
+// referring to a named transformation.
+type transformRef string
+
+func (r transformRef) String() string { return string(r) }
+
+func (t Transform) ref() (transformRef, bool) {
+	if t.Name != "" || len(t.Args) != 1 {
+		return "", false
+	}
+	r, ok := t.Args[0].(transformRef)
+	return r, ok
+}
+
+// hasRefs reports whether tl refers to named transformations.
+func (tl TransformList) hasRefs() bool {
+	for _, t := range tl {
+		if _, ok := t.ref(); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// expandTransforms returns tl, with references to named
+// transformations replaced by their definitions from defs.
+func expandTransforms(tl TransformList, defs map[string]TransformList) TransformList {
+	if !tl.hasRefs() {
+		return tl
+	}
+	return appendExpanded(nil, tl, defs, 0)
+}
+
+func appendExpanded(dst, tl TransformList, defs map[string]TransformList, depth int) TransformList {
+	for _, t := range tl {
+		r, ok := t.ref()
+		if !ok {
+			dst = append(dst, t)
+			continue
+		}
+		if def, ok := defs[string(r)]; ok && depth < maxUseDepth {
+			dst = appendExpanded(dst, def, defs, depth+1)
+		}
+	}
+	return dst
+}
+
+// withNamedTransforms returns a copy of element x, having references
+// to named transformations expanded, or x itself, if it has none.
+func (p *preparer) withNamedTransforms(x interface{}) interface{} {
+	e, ok := x.(element)
+	if !ok || !e.object().TransformList.hasRefs() {
+		return x
+	}
+	x = shallowCopy(x)
+	obj := x.(element).object()
+	obj.TransformList = expandTransforms(obj.TransformList, p.transforms)
+	return x
+}
@@ -0,0 +1,207 @@
+package svg
+
+import (
+	"math"
+	"reflect"
+	"strings"
+)
+
+// numberMapper creates copies of elements having their numbers
+// rounded to a number of decimals, see Conf.Precision, and non-finite
+// numbers clamped, see Conf.ClampNonFinite.
+type numberMapper struct {
+	scale float64 // 10^decimals, or zero
+	clamp bool
+}
+
+// maxClamped is the largest float32,
+// the precision many renderers use.
+const maxClamped = math.MaxFloat32
+
+func (r *numberMapper) mapNumber(f float64) float64 {
+	if r.clamp {
+		switch {
+		case math.IsNaN(f):
+			f = 0
+		case f > maxClamped:
+			f = maxClamped
+		case f < -maxClamped:
+			f = -maxClamped
+		}
+	}
+	if r.scale != 0 {
+		f = math.Round(f*r.scale) / r.scale
+	}
+	if f == 0 {
+		// avoid "-0"
+		return 0
+	}
+	return f
+}
+
+// elem returns a copy of x, with coordinates, lengths, and
+// arguments of transformations mapped. Children of containers
+// are not modified.
+func (r *numberMapper) elem(x interface{}) interface{} {
+	if _, ok := x.(element); !ok {
+		return x
+	}
+	x = shallowCopy(x)
+	if p, ok := x.(*path); ok {
+		p.D = r.pathData(p.D)
+	}
+	r.value(reflect.ValueOf(x).Elem())
+	return x
+}
+
+// value maps the numbers within v. Slices are replaced
+// by copies, as they may be shared with the original element.
+func (r *numberMapper) value(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if v.CanSet() {
+			v.SetFloat(r.mapNumber(v.Float()))
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			r.value(v.Index(i))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if f := t.Field(i); f.PkgPath != "" && !f.Anonymous {
+				continue
+			}
+			r.value(v.Field(i))
+		}
+	case reflect.Slice:
+		if v.Len() == 0 || !v.CanSet() || v.Type() == elemListType {
+			return
+		}
+		switch v.Type().Elem().Kind() {
+		case reflect.Float32, reflect.Float64, reflect.Array, reflect.Struct, reflect.Interface:
+		default:
+			return
+		}
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(cp, v)
+		for i := 0; i < cp.Len(); i++ {
+			r.value(cp.Index(i))
+		}
+		v.Set(cp)
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		switch e := v.Interface().(type) {
+		case floatArg:
+			v.Set(reflect.ValueOf(floatArg(r.mapNumber(float64(e)))))
+		case *tspan:
+			ts := *e
+			r.value(reflect.ValueOf(&ts).Elem())
+			v.Set(reflect.ValueOf(&ts))
+		case Length:
+			if val, unit, ok := lengthValue(e); ok {
+				v.Set(reflect.ValueOf(makeLength(r.mapNumber(val), unit)))
+			}
+		}
+	}
+}
+
+// pathData returns d with its non-integer numbers, and
+// values formatted from non-finite numbers, like "NaN", mapped.
+// Integers, and arc flags, which may be written without
+// separators, are kept as they are.
+func (r *numberMapper) pathData(d string) string {
+	var b strings.Builder
+	var cmd byte
+	arg := 0
+	for i := 0; i < len(d); {
+		c := d[i]
+		if (cmd == 'A' || cmd == 'a') && (arg%7 == 3 || arg%7 == 4) && (c == '0' || c == '1') {
+			b.WriteByte(c)
+			i++
+			arg++
+			continue
+		}
+		n := numberLen(d[i:])
+		if n == 0 {
+			n = nonFiniteLen(d[i:])
+		}
+		if n == 0 {
+			if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' {
+				cmd = c
+				arg = 0
+			}
+			b.WriteByte(c)
+			i++
+			continue
+		}
+		num := d[i : i+n]
+		i += n
+		arg++
+		if !strings.ContainsAny(num, ".eEIN") {
+			b.WriteString(num)
+			continue
+		}
+		f, err := parseFloats(num)
+		if err != nil || len(f) != 1 {
+			b.WriteString(num)
+			continue
+		}
+		s := formatFloat(r.mapNumber(f[0]))
+		// keep numbers apart that were separated
+		// by a sign or a decimal point only
+		if out := b.String(); out != "" && s[0] != '-' {
+			if c := out[len(out)-1]; c >= '0' && c <= '9' || c == '.' {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(s)
+	}
+	return b.String()
+}
+
+// nonFiniteLen returns the length of a non-finite number,
+// as formatted by strconv, at the start of s, or zero.
+func nonFiniteLen(s string) int {
+	for _, v := range []string{"NaN", "+Inf", "-Inf", "Inf"} {
+		if strings.HasPrefix(s, v) {
+			return len(v)
+		}
+	}
+	return 0
+}
+
+// numberLen returns the length of the number at the start of s,
+// or zero.
+func numberLen(s string) int {
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	digits := 0
+	for ; i < len(s) && s[i] >= '0' && s[i] <= '9'; i++ {
+		digits++
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		for ; i < len(s) && s[i] >= '0' && s[i] <= '9'; i++ {
+			digits++
+		}
+	}
+	if digits == 0 {
+		return 0
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			for i = j; i < len(s) && s[i] >= '0' && s[i] <= '9'; i++ {
+			}
+		}
+	}
+	return i
+}
@@ -0,0 +1,209 @@
+package svg
+
+import (
+	"math"
+	"reflect"
+	"strings"
+)
+
+// NumberError describes a number within an element that cannot
+// be encoded safely, i.e. NaN, or an infinity.
+//
+// Numbers within attributes are formatted independently of the
+// locale, using a point as decimal separator, and without "+" signs
+// in exponents, like in "1e06". Encoding a document fails with a
+// NumberError if it contains an invalid number, so that no "NaN" or
+// "Inf" values reach renderers. Constructors and setters taking
+// numbers record a NumberError in the created object, which is
+// returned by Object.Err.
+type NumberError struct {
+	Elem  string // element name, like "rect"
+	ID    string
+	Attr  string
+	Value float64
+}
+
+func (e *NumberError) Error() string {
+	s := "svg: "
+	if e.Elem != "" {
+		s += e.Elem
+		if e.ID != "" {
+			s += " #" + e.ID
+		}
+		s += ": "
+	}
+	return s + "invalid number in " + e.Attr + ": " + formatFloat(e.Value)
+}
+
+// CheckNumbers returns a NumberError for each number within the
+// document, including definitions, that cannot be encoded safely.
+// The same check is performed, stopping at the first error, when
+// the document is encoded.
+func (d *Document) CheckNumbers() []error {
+	var errs []error
+	checkNumbers(d.ElemList, &errs, -1)
+	return errs
+}
+
+// firstNumberError returns the first error found
+// by checkNumbers within el, or nil.
+func firstNumberError(el ElemList) error {
+	var errs []error
+	checkNumbers(el, &errs, 1)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs[0]
+}
+
+// Err returns the first error recorded while setting attributes of
+// the object using constructors or setters, like a NumberError if a
+// number passed was NaN or infinite, or nil.
+func (o *Object) Err() error {
+	if o.err != nil {
+		return o.err
+	}
+	return o.Presentation.err
+}
+
+// checkNumber records a NumberError in the object, unless an
+// error has been recorded already, if f cannot be encoded safely.
+func (o *Object) checkNumber(elem, attr string, f float64) {
+	if o.err == nil && !validNumber(f) {
+		o.err = &NumberError{Elem: elem, ID: o.ID, Attr: attr, Value: f}
+	}
+}
+
+func (p *Presentation) checkNumber(attr string, f float64) {
+	if p.err == nil && !validNumber(f) {
+		p.err = &NumberError{Attr: attr, Value: f}
+	}
+}
+
+// recordNumberError records the first invalid number within
+// the attributes of element x, if any, in its Object.
+func recordNumberError(x interface{}) {
+	e, ok := x.(element)
+	if !ok {
+		return
+	}
+	obj := e.object()
+	if obj.err != nil {
+		return
+	}
+	var errs []error
+	nc := &numberChecker{elem: x, id: obj.ID, errs: &errs}
+	if p, ok := x.(*path); ok {
+		nc.pathData(p.D)
+	}
+	nc.value(reflect.ValueOf(x).Elem(), "")
+	if len(errs) != 0 {
+		obj.err = errs[0]
+	}
+}
+
+func validNumber(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// checkNumbers adds errors for the invalid numbers within the
+// elements of el to errs, until errs contains max errors,
+// if max is positive.
+func checkNumbers(el ElemList, errs *[]error, max int) {
+	for _, x := range el {
+		if max > 0 && len(*errs) >= max {
+			return
+		}
+		e, ok := x.(element)
+		if !ok {
+			continue
+		}
+		nc := &numberChecker{elem: x, id: e.object().ID, errs: errs}
+		if p, ok := x.(*path); ok {
+			nc.pathData(p.D)
+		}
+		nc.value(reflect.ValueOf(x).Elem(), "")
+		if c, ok := x.(container); ok {
+			checkNumbers(c.container().ElemList, errs, max)
+		}
+	}
+}
+
+type numberChecker struct {
+	elem interface{}
+	id   string
+	errs *[]error
+}
+
+func (nc *numberChecker) check(attr string, f float64) {
+	if !validNumber(f) {
+		*nc.errs = append(*nc.errs, &NumberError{Elem: elemName(nc.elem), ID: nc.id, Attr: attr, Value: f})
+	}
+}
+
+// value checks the numbers within v, which belong
+// to the attribute attr, if it is not empty.
+func (nc *numberChecker) value(v reflect.Value, attr string) {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if attr != "" {
+			nc.check(attr, v.Float())
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			nc.value(v.Index(i), attr)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" && !f.Anonymous {
+				continue
+			}
+			tag := strings.Split(f.Tag.Get("xml"), ",")
+			switch {
+			case f.Anonymous && tag[0] == "":
+				nc.value(v.Field(i), attr)
+			case len(tag) > 1 && tag[1] == "attr" && tag[0] != "":
+				nc.value(v.Field(i), tag[0])
+			case attr != "":
+				// fields of attribute values, like Transform.Args
+				nc.value(v.Field(i), attr)
+			case v.Field(i).Type() == textDataType:
+				nc.value(v.Field(i), "")
+			}
+		}
+	case reflect.Slice:
+		if v.Type() == elemListType {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			nc.value(v.Index(i), attr)
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		switch e := v.Interface().(type) {
+		case floatArg:
+			nc.check(attr, float64(e))
+		case *tspan:
+			nc.value(reflect.ValueOf(e).Elem(), "")
+		case Length:
+			if f, _, ok := lengthValue(e); ok {
+				nc.check(attr, f)
+			}
+		}
+	}
+}
+
+// pathData checks path data for numbers formatted from
+// infinite or NaN values.
+func (nc *numberChecker) pathData(d string) {
+	switch {
+	case strings.Contains(d, "NaN"):
+		nc.check("d", math.NaN())
+	case strings.Contains(d, "Inf"):
+		nc.check("d", math.Inf(1))
+	}
+}
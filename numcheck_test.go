@@ -0,0 +1,76 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNumberFormat(t *testing.T) {
+	d := NewDocument(nil)
+	r := d.Rect(0, 0, 1e6, 2.5e-7)
+	r.AriaLabel = "growth: 1e+3 per day"
+	r.Attr("data-formula", "x,2e+5")
+	r.PathLength = 5e6
+	d.Text(0, 0, "a").AddSpan("b").X = 3e6
+
+	var buf bytes.Buffer
+	if err := d.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	m, err := xml.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, out := range []string{buf.String(), string(m)} {
+		for _, want := range []string{
+			`width="1e06"`,
+			`height="2.5e-07"`,
+			`aria-label="growth: 1e+3 per day"`,
+			`data-formula="x,2e+5"`,
+			`pathLength="5e06"`,
+			`<tspan x="3e06">`,
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s: missing %s", out, want)
+			}
+		}
+	}
+}
+
+func TestNonFiniteNumbers(t *testing.T) {
+	d := NewDocument(nil)
+	c := d.Circle(math.NaN(), 0, 1)
+	err, ok := c.Err().(*NumberError)
+	if !ok || err.Elem != "circle" || err.Attr != "cx" {
+		t.Errorf("circle: got error %v", c.Err())
+	}
+
+	r := d.Rect(0, 0, 1, 1)
+	if r.Err() != nil {
+		t.Fatal(r.Err())
+	}
+	r.SetRx(math.Inf(1)).SetRy(math.NaN())
+	if err, ok := r.Err().(*NumberError); !ok || err.Attr != "rx" {
+		t.Errorf("rect: got error %v", r.Err())
+	}
+
+	g := d.Group()
+	g.SetStrokeWidth(math.Inf(-1))
+	if err, ok := g.Err().(*NumberError); !ok || err.Attr != "stroke-width" {
+		t.Errorf("group: got error %v", g.Err())
+	}
+
+	if err := d.Encode(new(bytes.Buffer)); err == nil {
+		t.Error("Encode: missing error")
+	}
+	if n := len(d.CheckNumbers()); n != 4 {
+		t.Errorf("CheckNumbers: got %d errors, want 4", n)
+	}
+	d.ElemList = append(d.ElemList, &Rect{Width: math.NaN()})
+	if _, err := xml.Marshal(d); err == nil {
+		t.Error("xml.Marshal: missing error")
+	}
+}
@@ -0,0 +1,176 @@
+package svg
+
+import (
+	"encoding/xml"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// numberFormat describes the struct type used to encode
+// elements of a type with numeric attributes.
+type numberFormat struct {
+	typ    reflect.Type // nil, if there are no numeric attributes
+	index  [][]int      // of the fields of typ within the element type
+	floats []int        // fields of typ containing numeric attributes
+}
+
+// numberFormats caches a *numberFormat per element type.
+var numberFormats sync.Map
+
+var (
+	float64Type = reflect.TypeOf(float64(0))
+	numberType  = reflect.TypeOf(number(0))
+)
+
+// formatNumbers replaces the elements of el, a list of the prepared
+// document, and of its containers, by copies created by
+// withNumberFormat, as needed.
+func formatNumbers(el ElemList) {
+	for i, x := range el {
+		if c, ok := x.(container); ok {
+			formatNumbers(c.container().ElemList)
+		}
+		el[i] = withNumberFormat(x)
+	}
+}
+
+// withNumberFormat returns a copy of element x, if it has numeric
+// attributes of type float64 that encoding/xml would format using
+// an exponent with a "+" sign, like "1e+06", i.e. values of at
+// least 1e6; otherwise x is returned. The copy is a value of a
+// struct type having the same fields, flattened, except that
+// numeric attributes are of type number, which is marshaled
+// using formatFloat.
+func withNumberFormat(x interface{}) interface{} {
+	if _, ok := x.(xml.Marshaler); ok {
+		return x
+	}
+	v := reflect.ValueOf(x)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return x
+	}
+	v = v.Elem()
+	nf := numberFormatOf(v.Type())
+	if nf.typ == nil {
+		return x
+	}
+	exp := false
+	for _, i := range nf.floats {
+		if math.Abs(v.FieldByIndex(nf.index[i]).Float()) >= 1e6 {
+			exp = true
+			break
+		}
+	}
+	if !exp {
+		return x
+	}
+	cp := reflect.New(nf.typ).Elem()
+	for i, idx := range nf.index {
+		f := cp.Field(i)
+		f.Set(v.FieldByIndex(idx).Convert(f.Type()))
+	}
+	return cp.Addr().Interface()
+}
+
+// numberFormatOf returns the numberFormat of struct type t.
+func numberFormatOf(t reflect.Type) *numberFormat {
+	if nf, ok := numberFormats.Load(t); ok {
+		return nf.(*numberFormat)
+	}
+	nf := new(numberFormat)
+	var fields []formatField
+	var xmlName *formatField
+	fields = formatFields(t, nil, fields, &xmlName)
+	if xmlName != nil {
+		fields = append([]formatField{*xmlName}, fields...)
+	}
+	sf := make([]reflect.StructField, len(fields))
+	for i, f := range fields {
+		sf[i] = reflect.StructField{Name: f.Name, Type: f.Type, Tag: f.Tag}
+		if f.Name != "XMLName" {
+			sf[i].Name = "F" + strconv.Itoa(i)
+		}
+		nf.index = append(nf.index, f.Index)
+		if f.Type == numberType {
+			nf.floats = append(nf.floats, i)
+		}
+	}
+	if len(nf.floats) != 0 {
+		nf.typ = reflect.StructOf(sf)
+	}
+	numberFormats.Store(t, nf)
+	return nf
+}
+
+// formatField is a field of the struct type created by
+// numberFormatOf. Its Index refers to the original field.
+type formatField struct {
+	reflect.StructField
+	key   string // mode and name of the field, to detect conflicts
+	depth int
+}
+
+// formatFields appends the fields of struct type t, as they are
+// encoded by encoding/xml, to fields, flattening embedded structs.
+// If two fields share a name, the one of the outer struct is kept.
+// The XMLName field is stored in *xmlName, the outermost one
+// taking precedence.
+func formatFields(t reflect.Type, index []int, fields []formatField, xmlName **formatField) []formatField {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("xml")
+		if f.PkgPath != "" && !f.Anonymous || tag == "-" {
+			continue
+		}
+		idx := append(index[:len(index):len(index)], i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			fields = formatFields(f.Type, idx, fields, xmlName)
+			continue
+		}
+		name, flags := tag, ""
+		if i := strings.IndexByte(tag, ','); i != -1 {
+			name, flags = tag[:i], tag[i:]
+		}
+		mode := strings.Replace(flags, ",omitempty", "", 1)
+		if name == "" && (mode == "" || mode == ",attr") {
+			name = f.Name
+		}
+		ff := formatField{key: mode + " " + name, depth: len(idx)}
+		ff.Index = idx
+		ff.Type = f.Type
+		ff.Tag = reflect.StructTag(`xml:"` + name + flags + `"`)
+		ff.Name = f.Name
+		if f.Name == "XMLName" {
+			ff.Tag = f.Tag
+			if *xmlName == nil || len(idx) == 1 {
+				*xmlName = &ff
+			}
+			continue
+		}
+		if mode == ",attr" && f.Type == float64Type {
+			ff.Type = numberType
+		}
+		fields = addFormatField(fields, ff)
+	}
+	return fields
+}
+
+// addFormatField appends f to fields, unless a field of the same
+// name belongs to an outer struct. Fields of the same name
+// belonging to inner structs are removed.
+func addFormatField(fields []formatField, f formatField) []formatField {
+	list := fields[:0]
+	for _, old := range fields {
+		if old.key == f.key {
+			if old.depth <= f.depth {
+				return fields
+			}
+			continue
+		}
+		list = append(list, old)
+	}
+	return append(list, f)
+}
@@ -0,0 +1,277 @@
+package svg
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FlattenOpacity replaces semi-transparent colors by solid colors,
+// pre-blended against the background color bg, for consumers that
+// mishandle transparency, like some office suites and plotters.
+// If bg cannot be parsed, white is assumed.
+//
+// Declaration blocks of the embedded stylesheet and style attributes,
+// and the typed presentation attributes of objects, see Presentation,
+// are processed independently: The alpha channel of rgba() and
+// #rrggbbaa colors, fill-opacity and stroke-opacity values, and the
+// opacity property are folded into the fill and stroke colors of the
+// same block. Declarations are only changed or removed if the colors
+// they apply to are specified within the same block, and can be
+// parsed; the opacity property is folded only if both fill and stroke
+// are specified. Gradient stops are treated accordingly.
+// Since the colors of overlapping elements are not blended with each
+// other, the result is exact only for elements drawn directly onto
+// the background.
+func (d *Document) FlattenOpacity(bg Paint) {
+	f := &opacityFlattener{bg: rgb{1, 1, 1}}
+	if c, ok := parseColor(bg); ok {
+		f.bg = c
+	}
+	d.Style = f.stylesheet(d.Style)
+	f.list(d.ElemList)
+}
+
+type opacityFlattener struct {
+	bg rgb
+}
+
+func (f *opacityFlattener) list(el ElemList) {
+	for _, x := range el {
+		f.elem(x)
+	}
+}
+
+func (f *opacityFlattener) elem(x interface{}) {
+	if e, ok := x.(element); ok {
+		obj := e.object()
+		obj.Style = f.decls(obj.Style)
+		f.presentation(&obj.Presentation)
+	}
+	switch v := x.(type) {
+	case container:
+		f.list(v.container().ElemList)
+	case *text:
+		f.textData(v.Data)
+	case *LinearGradient:
+		f.stops(v.Stops)
+	case *RadialGradient:
+		f.stops(v.Stops)
+	}
+}
+
+func (f *opacityFlattener) textData(data TextData) {
+	for _, x := range data {
+		if ts, ok := x.(*tspan); ok {
+			ts.Style = f.decls(ts.Style)
+			f.presentation(&ts.Presentation)
+			f.textData(ts.Data)
+		}
+	}
+}
+
+func (f *opacityFlattener) stops(stops []GradientStop) {
+	for i := range stops {
+		s := &stops[i]
+		c, ok := parseColor(s.Color)
+		if !ok {
+			continue
+		}
+		a := parseAlpha(s.Color)
+		if s.Opacity != nil {
+			o, ok := s.Opacity.(number)
+			if !ok {
+				continue
+			}
+			a *= float64(o)
+		}
+		if a < 1 {
+			s.Color = f.blend(c, a)
+		}
+		s.Opacity = nil
+	}
+}
+
+// presentation processes the typed presentation
+// attributes of an object like a declaration block.
+func (f *opacityFlattener) presentation(p *Presentation) {
+	list := p.decls()
+	if list == nil {
+		return
+	}
+	style := make([]string, len(list))
+	for i, d := range list {
+		style[i] = d.prop + ":" + d.value
+	}
+	p.setDecls(appendDecls(nil, f.decls(strings.Join(style, ";"))))
+}
+
+// stylesheet processes the declaration blocks of a stylesheet.
+func (f *opacityFlattener) stylesheet(css string) string {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(css, '{')
+		if i == -1 {
+			break
+		}
+		end := strings.IndexByte(css[i:], '}')
+		if end == -1 {
+			break
+		}
+		end += i
+		b.WriteString(css[:i+1])
+		b.WriteString(f.decls(css[i+1 : end]))
+		css = css[end:]
+	}
+	b.WriteString(css)
+	return b.String()
+}
+
+type declaration struct {
+	prop, value string
+}
+
+// decls processes a semicolon separated list of declarations.
+func (f *opacityFlattener) decls(style string) string {
+	if style == "" {
+		return style
+	}
+	var list []declaration
+	for _, s := range strings.Split(style, ";") {
+		i := strings.IndexByte(s, ':')
+		if i == -1 {
+			continue
+		}
+		list = append(list, declaration{strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])})
+	}
+	opacity := 1.0
+	opacityIdx := -1
+	for i, decl := range list {
+		if decl.prop == "opacity" {
+			if v, err := strconv.ParseFloat(decl.value, 64); err == nil {
+				opacity, opacityIdx = v, i
+			}
+		}
+	}
+	type paint struct {
+		color, opacity int // indices into list, or -1
+	}
+	var paints [2]paint
+	foldOpacity := opacityIdx != -1
+	for k, prop := range []string{"fill", "stroke"} {
+		p := paint{-1, -1}
+		for i, decl := range list {
+			switch decl.prop {
+			case prop:
+				p.color = i
+			case prop + "-opacity":
+				p.opacity = i
+			}
+		}
+		if p.color == -1 {
+			foldOpacity = false
+		} else if v := Paint(list[p.color].value); v != None {
+			if _, ok := parseColor(v); !ok {
+				p.color = -1
+				foldOpacity = false
+			}
+		}
+		if p.opacity != -1 {
+			if _, err := strconv.ParseFloat(list[p.opacity].value, 64); err != nil {
+				p.color = -1
+				foldOpacity = false
+			}
+		}
+		paints[k] = p
+	}
+	remove := make(map[int]bool)
+	for _, p := range paints {
+		if p.color == -1 || Paint(list[p.color].value) == None {
+			continue
+		}
+		v := Paint(list[p.color].value)
+		c, _ := parseColor(v)
+		a := parseAlpha(v)
+		if p.opacity != -1 {
+			o, _ := strconv.ParseFloat(list[p.opacity].value, 64)
+			a *= o
+			remove[p.opacity] = true
+		}
+		if foldOpacity {
+			a *= opacity
+		}
+		if a < 1 {
+			list[p.color].value = string(f.blend(c, a))
+		}
+	}
+	if foldOpacity {
+		remove[opacityIdx] = true
+	}
+	var b strings.Builder
+	for i, decl := range list {
+		if remove[i] {
+			continue
+		}
+		if b.Len() != 0 {
+			b.WriteByte(';')
+		}
+		b.WriteString(decl.prop + ":" + decl.value)
+	}
+	return b.String()
+}
+
+// blend returns the solid color resulting from drawing
+// c with alpha a onto the background.
+func (f *opacityFlattener) blend(c rgb, a float64) Paint {
+	if a < 0 {
+		a = 0
+	}
+	return rgb{
+		lerp(f.bg.r, c.r, a),
+		lerp(f.bg.g, c.g, a),
+		lerp(f.bg.b, c.b, a),
+	}.paint()
+}
+
+// parseAlpha returns the alpha channel of an rgba() color, or
+// a color in #rgba or #rrggbbaa notation; otherwise it returns 1.
+func parseAlpha(p Paint) float64 {
+	s := strings.ToLower(strings.TrimSpace(string(p.Fallback())))
+	if strings.HasPrefix(s, "#") {
+		h := s[1:]
+		switch len(h) {
+		case 4:
+			h = h[3:] + h[3:]
+		case 8:
+			h = h[6:]
+		default:
+			return 1
+		}
+		v, err := strconv.ParseUint(h, 16, 8)
+		if err != nil {
+			return 1
+		}
+		return float64(v) / 255
+	}
+	for _, fn := range []string{"rgb(", "rgba("} {
+		if !strings.HasPrefix(s, fn) || !strings.HasSuffix(s, ")") {
+			continue
+		}
+		args := strings.FieldsFunc(s[len(fn):len(s)-1], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '/'
+		})
+		if len(args) < 4 {
+			return 1
+		}
+		a := args[3]
+		scale := 1.0
+		if strings.HasSuffix(a, "%") {
+			a, scale = a[:len(a)-1], 100
+		}
+		v, err := strconv.ParseFloat(a, 64)
+		if err != nil {
+			return 1
+		}
+		return v / scale
+	}
+	return 1
+}
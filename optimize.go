@@ -0,0 +1,776 @@
+package svg
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+)
+
+// An OptimizePass modifies a document to reduce the size of its
+// encoding, without changing the way it is rendered.
+type OptimizePass func(d *Document)
+
+// Optimize applies the passes to the document, in order. If no
+// passes are specified, StripEmptyGroups, DropDefaults,
+// MergeTransforms, and DedupDefs are applied.
+func Optimize(d *Document, passes ...OptimizePass) {
+	if len(passes) == 0 {
+		passes = []OptimizePass{StripEmptyGroups, DropDefaults, MergeTransforms, DedupDefs}
+	}
+	for _, pass := range passes {
+		pass(d)
+	}
+}
+
+// StripEmptyGroups removes groups and <defs> elements without
+// children, unless they have an ID, a title, or a description.
+// Groups that become empty this way are removed too.
+func StripEmptyGroups(d *Document) {
+	d.ElemList = stripEmpty(d.ElemList)
+}
+
+func stripEmpty(el ElemList) ElemList {
+	list := el[:0]
+	for _, x := range el {
+		if c, ok := x.(container); ok {
+			c := c.container()
+			c.ElemList = stripEmpty(c.ElemList)
+			switch x.(type) {
+			case *Group, *Defs:
+				if len(c.ElemList) == 0 && c.ID == "" && c.Title == "" && c.Desc == "" {
+					continue
+				}
+			}
+		}
+		list = append(list, x)
+	}
+	for i := len(list); i < len(el); i++ {
+		el[i] = nil
+	}
+	return list
+}
+
+// DropDefaults removes attributes, and declarations of style
+// attributes, that are set to their default values, like
+// gradientUnits="objectBoundingBox", or "opacity:1".
+// Declarations of inherited properties, like "stroke-width:1", are
+// removed only if no ancestor of the element specifies the property,
+// or is styled by the embedded stylesheet, and the element is not
+// referenced by a <use> element, which it would inherit from.
+// Declarations are not removed from elements styled by the embedded
+// stylesheet, and not at all, if the stylesheet contains selectors
+// other than classes.
+func DropDefaults(d *Document) {
+	dd := &defaultsDropper{
+		styled: true,
+		used:   make(map[string]bool),
+	}
+	if s := (&cssScanner{defined: make(map[string]bool)}); classSelectorsOnly(d.Style) {
+		s.stylesheet(d.Style)
+		dd.classes = s.defined
+		dd.styled = false
+	}
+	collectUseTargets(d.ElemList, dd.used)
+	dd.list(d.ElemList, nil, false)
+}
+
+// initialValues contains the initial values of CSS properties
+// that may be removed from declarations by DropDefaults; those
+// of inherited properties are marked.
+var initialValues = map[string]struct {
+	value     string
+	inherited bool
+}{
+	"opacity":           {"1", false},
+	"display":           {"inline", false},
+	"stop-opacity":      {"1", false},
+	"flood-opacity":     {"1", false},
+	"filter":            {"none", false},
+	"clip-path":         {"none", false},
+	"mask":              {"none", false},
+	"text-decoration":   {"none", false},
+	"fill-opacity":      {"1", true},
+	"stroke-opacity":    {"1", true},
+	"stroke":            {"none", true},
+	"stroke-width":      {"1", true},
+	"stroke-linecap":    {"butt", true},
+	"stroke-linejoin":   {"miter", true},
+	"stroke-miterlimit": {"4", true},
+	"stroke-dasharray":  {"none", true},
+	"stroke-dashoffset": {"0", true},
+	"fill-rule":         {"nonzero", true},
+	"clip-rule":         {"nonzero", true},
+	"visibility":        {"visible", true},
+	"text-anchor":       {"start", true},
+	"font-style":        {"normal", true},
+	"font-weight":       {"normal", true},
+	"font-variant":      {"normal", true},
+	"letter-spacing":    {"normal", true},
+	"word-spacing":      {"normal", true},
+}
+
+type defaultsDropper struct {
+	// styled is set if the stylesheet cannot be analyzed,
+	// in which case declarations are left alone.
+	styled  bool
+	classes map[string]bool
+	used    map[string]bool
+}
+
+// list processes the elements of el. Declared contains the inherited
+// properties specified by ancestors; if unknown is set, the values
+// inherited are not known.
+func (dd *defaultsDropper) list(el ElemList, declared map[string]bool, unknown bool) {
+	for _, x := range el {
+		e, ok := x.(element)
+		if !ok {
+			continue
+		}
+		dropDefaultAttrs(x)
+		obj := e.object()
+		if obj.ID != "" && dd.used[obj.ID] {
+			unknown = true
+		}
+		own := dd.styling(obj, declared, unknown)
+		var data TextData
+		switch v := x.(type) {
+		case container:
+			dd.list(v.container().ElemList, own, unknown || dd.classStyled(&obj.Styling))
+		case *text:
+			data = v.Data
+		}
+		dd.textData(data, own, unknown || dd.classStyled(&obj.Styling))
+	}
+}
+
+func (dd *defaultsDropper) textData(data TextData, declared map[string]bool, unknown bool) {
+	for _, x := range data {
+		if ts, ok := x.(*tspan); ok {
+			own := dd.styling(&ts.Object, declared, unknown)
+			dd.textData(ts.Data, own, unknown || dd.classStyled(&ts.Styling))
+		}
+	}
+}
+
+// classStyled reports whether st refers to classes
+// of the embedded stylesheet.
+func (dd *defaultsDropper) classStyled(st *Styling) bool {
+	for _, class := range strings.Fields(st.Class) {
+		if dd.classes[class] {
+			return true
+		}
+	}
+	return false
+}
+
+// styling removes declarations and presentation attributes of obj
+// that are set to their initial values, and returns the set of
+// inherited properties declared by obj and its ancestors.
+func (dd *defaultsDropper) styling(obj *Object, declared map[string]bool, unknown bool) map[string]bool {
+	// properties specified more than once, using fallbacks, or both
+	// as attribute and within the style attribute, are left alone
+	count := make(map[string]int)
+	decls := appendDecls(nil, obj.Style)
+	for _, decl := range decls {
+		count[decl.prop]++
+	}
+	for _, a := range obj.ExtraAttr {
+		if ea, ok := a.(*extraAttr); ok {
+			count[ea.name]++
+		}
+	}
+	typed := obj.Presentation.decls()
+	for _, decl := range typed {
+		count[decl.prop]++
+	}
+	droppable := func(prop, value string) bool {
+		iv, ok := initialValues[prop]
+		if !ok || value != iv.value || count[prop] != 1 || dd.styled || dd.classStyled(&obj.Styling) {
+			return false
+		}
+		return !iv.inherited || !unknown && !declared[prop]
+	}
+	var own []string
+	if obj.Style != "" {
+		var keep []string
+		for _, decl := range decls {
+			if droppable(decl.prop, decl.value) {
+				continue
+			}
+			keep = append(keep, decl.prop+":"+decl.value)
+			own = append(own, decl.prop)
+		}
+		obj.Style = strings.Join(keep, ";")
+	}
+	if len(obj.ExtraAttr) != 0 {
+		list := obj.ExtraAttr[:0]
+		for _, a := range obj.ExtraAttr {
+			if ea, ok := a.(*extraAttr); ok {
+				if droppable(ea.name, ea.value) {
+					continue
+				}
+				own = append(own, ea.name)
+			}
+			list = append(list, a)
+		}
+		for i := len(list); i < len(obj.ExtraAttr); i++ {
+			obj.ExtraAttr[i] = nil
+		}
+		obj.ExtraAttr = list
+		if len(list) == 0 {
+			obj.ExtraAttr = nil
+		}
+	}
+	if len(typed) != 0 {
+		keep := typed[:0]
+		for _, decl := range typed {
+			if droppable(decl.prop, decl.value) {
+				continue
+			}
+			keep = append(keep, decl)
+			own = append(own, decl.prop)
+		}
+		obj.Presentation.setDecls(keep)
+	}
+	if len(own) == 0 {
+		return declared
+	}
+	m := make(map[string]bool, len(declared)+len(own))
+	for prop := range declared {
+		m[prop] = true
+	}
+	for _, prop := range own {
+		m[prop] = true
+	}
+	return m
+}
+
+// dropDefaultAttrs clears typed attributes of x
+// that are set to their default values.
+func dropDefaultAttrs(x interface{}) {
+	clearUnits := func(u *GradientUnits, def GradientUnits) {
+		if *u == def {
+			*u = ""
+		}
+	}
+	clearLength := func(l *Length, def Length) {
+		if *l != nil && sameLength(*l, def) {
+			*l = nil
+		}
+	}
+	clearPAR := func(par *string) {
+		if *par == "xMidYMid meet" || *par == "xMidYMid" {
+			*par = ""
+		}
+	}
+	switch v := x.(type) {
+	case *LinearGradient:
+		clearUnits(&v.Units, ObjectBoundingBox)
+		if v.Spread == SpreadPad {
+			v.Spread = ""
+		}
+	case *RadialGradient:
+		clearUnits(&v.Units, ObjectBoundingBox)
+		if v.Spread == SpreadPad {
+			v.Spread = ""
+		}
+	case *Pattern:
+		clearUnits(&v.Units, ObjectBoundingBox)
+		clearUnits(&v.ContentUnits, UserSpaceOnUse)
+	case *Marker:
+		if v.Units == MarkerStrokeWidth {
+			v.Units = ""
+		}
+		clearLength(&v.Width, Number(3))
+		clearLength(&v.Height, Number(3))
+	case *Filter:
+		clearUnits(&v.Units, ObjectBoundingBox)
+		clearUnits(&v.PrimitiveUnits, UserSpaceOnUse)
+		clearLength(&v.X, Percentage(-10))
+		clearLength(&v.Y, Percentage(-10))
+		clearLength(&v.Width, Percentage(120))
+		clearLength(&v.Height, Percentage(120))
+	case *text:
+		if v.LengthAdjust == Spacing {
+			v.LengthAdjust = ""
+		}
+	case *Symbol:
+		clearPAR(&v.PreserveAspectRatio)
+	case *Image:
+		clearPAR(&v.PreserveAspectRatio)
+	}
+}
+
+// sameLength reports whether a and b have the same value and unit.
+func sameLength(a, b Length) bool {
+	va, ua, okA := lengthValue(a)
+	vb, ub, okB := lengthValue(b)
+	return okA && okB && va == vb && ua == ub
+}
+
+// classSelectorsOnly reports whether the selectors of the
+// rules of css, including those within @media rules, consist
+// of class selectors only, optionally preceded by an ID
+// selector, as created by MakeStyle and LevelOfDetail.
+func classSelectorsOnly(css string) bool {
+	for {
+		i := strings.IndexAny(css, "{}")
+		if i == -1 {
+			return true
+		}
+		if css[i] == '}' {
+			css = css[i+1:]
+			continue
+		}
+		prelude := strings.TrimSpace(css[:i])
+		css = css[i+1:]
+		if strings.HasPrefix(prelude, "@media") {
+			continue
+		}
+		for _, sel := range strings.Split(prelude, ",") {
+			f := strings.Fields(sel)
+			if len(f) == 2 && strings.HasPrefix(f[0], "#") {
+				f = f[1:]
+			}
+			if len(f) != 1 || !isClassSelector(f[0]) {
+				return false
+			}
+		}
+		end := strings.IndexByte(css, '}')
+		if end == -1 {
+			return true
+		}
+		css = css[end+1:]
+	}
+}
+
+func isClassSelector(s string) bool {
+	if len(s) < 2 || s[0] != '.' {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if !isNameByte(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// collectUseTargets adds the IDs referenced by <use> elements to ids.
+func collectUseTargets(el ElemList, ids map[string]bool) {
+	for _, x := range el {
+		switch v := x.(type) {
+		case *use:
+			if strings.HasPrefix(v.Href, "#") {
+				ids[v.Href[1:]] = true
+			}
+		case container:
+			collectUseTargets(v.container().ElemList, ids)
+		}
+	}
+}
+
+// MergeTransforms combines consecutive transformations of the same
+// kind, like two translations, within transform attributes, and
+// removes transformations that have no effect, like scale(1).
+func MergeTransforms(d *Document) {
+	d.TransformList = mergeTransforms(d.TransformList)
+	mergeListTransforms(d.ElemList)
+}
+
+func mergeListTransforms(el ElemList) {
+	for _, x := range el {
+		if e, ok := x.(element); ok {
+			obj := e.object()
+			obj.TransformList = mergeTransforms(obj.TransformList)
+		}
+		if c, ok := x.(container); ok {
+			mergeListTransforms(c.container().ElemList)
+		}
+	}
+}
+
+func mergeTransforms(tl TransformList) TransformList {
+	if len(tl) == 0 {
+		return tl
+	}
+	var list TransformList
+	for _, t := range tl {
+		a, ok := t.floatArgs()
+		if !ok {
+			list = append(list, t)
+			continue
+		}
+		if n := len(list); n != 0 {
+			if prev, ok := list[n-1].floatArgs(); ok {
+				if m, ok := mergeTransform(list[n-1].Name, prev, t.Name, a); ok {
+					list[n-1] = m
+					if isIdentity(m) {
+						list = list[:n-1]
+					}
+					continue
+				}
+			}
+		}
+		if !isIdentity(t) {
+			list = append(list, t)
+		}
+	}
+	return list
+}
+
+// floatArgs returns the arguments of t as numbers.
+func (t Transform) floatArgs() ([]float64, bool) {
+	a, err := parseFloats(TransformList{t}.argString())
+	return a, err == nil
+}
+
+func (tl TransformList) argString() string {
+	s := make([]string, 0, 6)
+	for _, t := range tl {
+		for _, arg := range t.Args {
+			s = append(s, arg.String())
+		}
+	}
+	return strings.Join(s, " ")
+}
+
+// mergeTransform returns a transformation equivalent to
+// the transformation named n1 with arguments a1, followed
+// by the transformation named n2 with arguments a2.
+func mergeTransform(n1 string, a1 []float64, n2 string, a2 []float64) (Transform, bool) {
+	if n1 != n2 {
+		return Transform{}, false
+	}
+	args := func(f ...float64) []TransformArg {
+		list := make([]TransformArg, len(f))
+		for i := range f {
+			list[i] = floatArg(f[i])
+		}
+		return list
+	}
+	pair := func(a []float64, def float64) (float64, float64) {
+		if len(a) == 1 {
+			return a[0], def
+		}
+		return a[0], a[1]
+	}
+	switch n1 {
+	case "translate":
+		x1, y1 := pair(a1, 0)
+		x2, y2 := pair(a2, 0)
+		return Transform{Name: n1, Args: args(x1+x2, y1+y2)}, true
+	case "scale":
+		if len(a1) == 1 && len(a2) == 1 {
+			return Transform{Name: n1, Args: args(a1[0] * a2[0])}, true
+		}
+		x1, y1 := pair(a1, a1[0])
+		x2, y2 := pair(a2, a2[0])
+		return Transform{Name: n1, Args: args(x1*x2, y1*y2)}, true
+	case "rotate":
+		if len(a1) == 1 && len(a2) == 1 {
+			return Transform{Name: n1, Args: args(a1[0] + a2[0])}, true
+		}
+		if len(a1) == 3 && len(a2) == 3 && a1[1] == a2[1] && a1[2] == a2[2] {
+			return Transform{Name: n1, Args: args(a1[0]+a2[0], a1[1], a1[2])}, true
+		}
+	}
+	return Transform{}, false
+}
+
+// isIdentity reports whether t has no effect.
+func isIdentity(t Transform) bool {
+	a, ok := t.floatArgs()
+	if !ok || len(a) == 0 {
+		return false
+	}
+	switch t.Name {
+	case "translate", "rotate", "skewX", "skewY":
+		return a[0] == 0 && (len(a) == 1 || t.Name == "rotate" || a[1] == 0)
+	case "scale":
+		return a[0] == 1 && (len(a) == 1 || a[1] == 1)
+	case "matrix":
+		return len(a) == 6 && affine{a[0], a[1], a[2], a[3], a[4], a[5]} == identity
+	}
+	return false
+}
+
+// DedupDefs removes definitions that are identical to a definition
+// appearing earlier in the document, apart from their IDs, and
+// redirects references to them. Definitions are those considered
+// by PruneDefs. Definitions containing elements with IDs are
+// not removed.
+func DedupDefs(d *Document) {
+	for {
+		dd := &deduper{
+			first:   make(map[string]string),
+			replace: make(map[string]string),
+		}
+		d.ElemList = dd.list(d.ElemList, false)
+		if len(dd.replace) == 0 {
+			return
+		}
+		replaceRefs(reflect.ValueOf(d).Elem(), dd.replace)
+		for class, style := range d.styles.classMap {
+			d.styles.classMap[class] = replaceIDRefs(style, dd.replace)
+		}
+	}
+}
+
+type deduper struct {
+	// first maps the markup of definitions to their IDs
+	first   map[string]string
+	replace map[string]string
+}
+
+func (dd *deduper) list(el ElemList, inDefs bool) ElemList {
+	p := new(pruner)
+	list := el[:0]
+	for _, x := range el {
+		if id := p.defID(x, inDefs); id != "" {
+			if key, ok := defKey(x); ok {
+				if first, ok := dd.first[key]; ok {
+					dd.replace[id] = first
+					continue
+				}
+				dd.first[key] = id
+			}
+		}
+		if c, ok := x.(container); ok {
+			_, isDefs := x.(*Defs)
+			c := c.container()
+			c.ElemList = dd.list(c.ElemList, isDefs)
+		}
+		list = append(list, x)
+	}
+	for i := len(list); i < len(el); i++ {
+		el[i] = nil
+	}
+	return list
+}
+
+// defKey returns the markup of definition x, without its ID,
+// or false, if one of its descendants has an ID.
+func defKey(x interface{}) (string, bool) {
+	if c, ok := x.(container); ok {
+		ids := make(map[string]interface{})
+		collectIDs(c.container().ElemList, ids)
+		if len(ids) != 0 {
+			return "", false
+		}
+	}
+	cp := shallowCopy(x)
+	cp.(element).object().ID = ""
+	buf, err := xml.Marshal(cp)
+	if err != nil {
+		return "", false
+	}
+	return string(buf), true
+}
+
+// replaceRefs replaces references within the string fields of v,
+// and of the elements it contains, according to m, which maps IDs
+// to the IDs replacing them.
+func replaceRefs(v reflect.Value, m map[string]string) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			replaceRefs(v.Elem(), m)
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(replaceIDRefs(v.String(), m))
+		}
+	case reflect.Struct:
+		if !v.CanAddr() {
+			return
+		}
+		if ea, ok := v.Addr().Interface().(*extraAttr); ok {
+			ea.value = replaceIDRefs(ea.value, m)
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			replaceRefs(v.Field(i), m)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			replaceRefs(v.Index(i), m)
+		}
+	}
+}
+
+// replaceIDRefs replaces references of the form "#id", and
+// url(#id) expressions, within s according to m.
+func replaceIDRefs(s string, m map[string]string) string {
+	if strings.HasPrefix(s, "#") {
+		if id, ok := m[s[1:]]; ok {
+			return "#" + id
+		}
+		return s
+	}
+	if !strings.Contains(s, "url(") {
+		return s
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "url(")
+		if i == -1 {
+			break
+		}
+		end := strings.IndexByte(s[i:], ')')
+		if end == -1 {
+			break
+		}
+		end += i
+		b.WriteString(s[:i])
+		ref := strings.Trim(strings.TrimSpace(s[i+4:end]), `"'`)
+		if id, ok := m[strings.TrimPrefix(ref, "#")]; ok && strings.HasPrefix(ref, "#") {
+			b.WriteString("url(#" + id + ")")
+		} else {
+			b.WriteString(s[i : end+1])
+		}
+		s = s[end+1:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// ExtractSymbols replaces groups that occur repeatedly within
+// the document, differing only by their transformations, by <use>
+// elements referring to a <symbol> containing the group's children,
+// like those created by loops drawing the same shape at different
+// positions. The symbols are placed into a <defs> element at the
+// start of the document. Groups with IDs, or descendants having
+// IDs, are not replaced. As the structure of the document changes,
+// the pass is not applied if the stylesheet contains selectors
+// other than classes. It is not part of Optimize's default passes.
+func ExtractSymbols(d *Document) {
+	if !classSelectorsOnly(d.Style) {
+		return
+	}
+	ids := make(map[string]interface{})
+	collectIDs(d.ElemList, ids)
+	se := &symbolExtractor{doc: d, ids: ids, symbols: make(map[string]string)}
+	for {
+		se.count = make(map[string]int)
+		// symbols created so far may contain further
+		// repeated groups
+		se.countGroups(se.defs)
+		se.countGroups(d.ElemList)
+		n := len(se.defs)
+		se.replace(se.defs[:n])
+		se.replace(d.ElemList)
+		if len(se.defs) == n {
+			break
+		}
+	}
+	if len(se.defs) != 0 {
+		defs := new(Defs)
+		defs.ElemList = se.defs
+		d.ElemList = append(ElemList{defs}, d.ElemList...)
+	}
+}
+
+type symbolExtractor struct {
+	doc     *Document
+	ids     map[string]interface{}
+	count   map[string]int    // occurrences of group keys
+	symbols map[string]string // maps group keys to symbol IDs
+	defs    ElemList
+}
+
+// groupKey returns the markup of group g without its
+// transformations, or false, if g may not be replaced.
+func (se *symbolExtractor) groupKey(g *Group) (string, bool) {
+	if len(g.ElemList) == 0 || g.ID != "" || g.omit || g.expand {
+		return "", false
+	}
+	ids := make(map[string]interface{})
+	collectIDs(g.ElemList, ids)
+	if len(ids) != 0 {
+		return "", false
+	}
+	cp := *g
+	cp.TransformList = nil
+	cp.CSSTransform = nil
+	buf, err := xml.Marshal(&cp)
+	if err != nil {
+		return "", false
+	}
+	return string(buf), true
+}
+
+// countGroups counts the occurrences of replaceable groups
+// within el. The descendants of a group are only counted
+// at its first occurrence.
+func (se *symbolExtractor) countGroups(el ElemList) {
+	for _, x := range el {
+		if g, ok := x.(*Group); ok {
+			if key, ok := se.groupKey(g); ok {
+				se.count[key]++
+				if se.count[key] > 1 {
+					continue
+				}
+			}
+		}
+		if _, ok := x.(*clipPath); ok {
+			// <use> elements within clip paths may
+			// only refer to shapes or text
+			continue
+		}
+		if c, ok := x.(container); ok {
+			se.countGroups(c.container().ElemList)
+		}
+	}
+}
+
+// replace replaces groups within el that occur repeatedly
+// by <use> elements.
+func (se *symbolExtractor) replace(el ElemList) {
+	for i, x := range el {
+		if g, ok := x.(*Group); ok {
+			if key, ok := se.groupKey(g); ok && se.count[key] > 1 {
+				el[i] = se.use(g, key)
+				continue
+			}
+		}
+		if _, ok := x.(*clipPath); ok {
+			continue
+		}
+		if c, ok := x.(container); ok {
+			se.replace(c.container().ElemList)
+		}
+	}
+}
+
+// use returns a <use> element referring to the symbol
+// created from the group with the specified key,
+// which is created if it does not exist yet.
+func (se *symbolExtractor) use(g *Group, key string) *use {
+	id, ok := se.symbols[key]
+	if !ok {
+		for {
+			id = se.doc.autoID("symbol")
+			if _, dup := se.ids[id]; !dup {
+				break
+			}
+		}
+		s := new(Symbol)
+		s.Container = g.Container
+		s.ID = id
+		s.TransformList = nil
+		s.CSSTransform = nil
+		// symbols clip their content to the viewport by default
+		s.Attr("overflow", "visible")
+		se.ids[id] = s
+		se.symbols[key] = id
+		se.defs = append(se.defs, s)
+	}
+	u := &use{Href: "#" + id}
+	u.TransformList = g.TransformList
+	u.CSSTransform = g.CSSTransform
+	return u
+}
@@ -0,0 +1,78 @@
+package svg
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Outline writes an indented tree of the document's elements to w,
+// one line per element, showing the element name, and, if present,
+// its ID (prefixed by '#') and class (prefixed by '.'). Elements that
+// will be left out when encoding are marked as such.
+// Outline is meant as a debugging aid.
+func (d *Document) Outline(w io.Writer) error {
+	ow := &outlineWriter{w: w}
+	ow.line(0, "svg", &d.Object, true)
+	ow.list(1, d.ElemList)
+	return ow.err
+}
+
+type outlineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ow *outlineWriter) list(depth int, el ElemList) {
+	for _, x := range el {
+		var o *Object
+		if e, ok := x.(element); ok {
+			o = e.object()
+		}
+		ow.line(depth, elemName(x), o, included(x))
+		if c, ok := x.(container); ok {
+			ow.list(depth+1, c.container().ElemList)
+		}
+	}
+}
+
+func (ow *outlineWriter) line(depth int, name string, o *Object, included bool) {
+	if ow.err != nil {
+		return
+	}
+	s := strings.Repeat("  ", depth) + name
+	if o != nil {
+		if o.ID != "" {
+			s += " #" + o.ID
+		}
+		if o.Class != "" {
+			s += " ." + strings.Join(strings.Fields(o.Class), ".")
+		}
+	}
+	if !included {
+		s += " (omitted)"
+	}
+	_, ow.err = fmt.Fprintln(ow.w, s)
+}
+
+// elemName returns the XML element name of x, as
+// specified by the tag of its XMLName field.
+func elemName(x interface{}) string {
+	t := reflect.TypeOf(x)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		if f, ok := t.FieldByName("XMLName"); ok {
+			name := f.Tag.Get("xml")
+			if i := strings.IndexByte(name, ','); i != -1 {
+				name = name[:i]
+			}
+			if name != "" {
+				return name
+			}
+		}
+	}
+	return t.Name()
+}
@@ -0,0 +1,78 @@
+package svg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Paint is a value of the fill or stroke property, like a
+// color in CSS syntax, "none", or a reference to a paint server.
+// Besides the sRGB color used by browsers, it may carry hints
+// for print production, see CMYK, ICCColor, and Spot.
+type Paint string
+
+const (
+	None         Paint = "none"
+	CurrentColor Paint = "currentColor"
+)
+
+// RGB returns the color with the specified components
+// in hexadecimal notation.
+func RGB(r, g, b uint8) Paint {
+	return Paint(fmt.Sprintf("#%02x%02x%02x", r, g, b))
+}
+
+// CMYK returns a device-cmyk() color, as defined by CSS Color
+// Level 4, with components between 0 and 1, and p as sRGB fallback.
+// Since browsers do not support device-cmyk(), the value should be
+// used within a style using Decl, which adds a declaration of the
+// fallback color.
+func (p Paint) CMYK(c, m, y, k float64) Paint {
+	return Paint("device-cmyk(" + formatFloat(c) + " " + formatFloat(m) + " " +
+		formatFloat(y) + " " + formatFloat(k) + ", " + string(p.Fallback()) + ")")
+}
+
+// ICCColor appends an SVG 1.1 icc-color() specification to p,
+// referring to the color profile with the specified name,
+// which must be declared by the consuming application,
+// or within the document.
+func (p Paint) ICCColor(profile string, components ...float64) Paint {
+	s := string(p.Fallback()) + " icc-color(" + profile
+	for _, c := range components {
+		s += ", " + formatFloat(c)
+	}
+	return Paint(s + ")")
+}
+
+// Spot appends an icc-named-color() specification to p, naming
+// a spot color within a named color profile, like a Pantone ink.
+func (p Paint) Spot(profile, name string) Paint {
+	return Paint(string(p.Fallback()) + " icc-named-color(" + profile + ", " + strconv.Quote(name) + ")")
+}
+
+// Fallback returns the sRGB color of p, without print hints.
+func (p Paint) Fallback() Paint {
+	s := string(p)
+	if strings.HasPrefix(s, "device-cmyk(") && strings.HasSuffix(s, ")") {
+		if i := strings.LastIndexByte(s, ','); i != -1 {
+			return Paint(strings.TrimSpace(s[i+1 : len(s)-1]))
+		}
+		return ""
+	}
+	if i := strings.Index(s, " icc-"); i != -1 {
+		return Paint(s[:i])
+	}
+	return p
+}
+
+// Decl returns a CSS declaration of property, like "fill", set
+// to p. If p contains print hints, a declaration using the
+// fallback color is prepended, so that renderers ignoring the
+// hints use the fallback color.
+func (p Paint) Decl(property string) string {
+	if f := p.Fallback(); f != p && f != "" {
+		return property + ":" + string(f) + ";" + property + ":" + string(p)
+	}
+	return property + ":" + string(p)
+}
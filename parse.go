@@ -0,0 +1,247 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// Parse reads an SVG document from r, and creates a Document using
+// configuration c, that may be modified and encoded again. Supported
+// are the elements this package creates; other elements result in
+// an error. Attributes that have no corresponding field are kept as
+// extra attributes, see Object.Attr. The content of <style> elements
+// becomes the embedded stylesheet, Document.Style; the classes it
+// defines are not available to MakeStyle.
+//
+// Parse converts the document into a list of operations, which is
+// then replayed, see Snapshot and Replay.
+func Parse(r io.Reader, c *Conf) (*Document, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	log, err := parseLog(data)
+	if err != nil {
+		return nil, err
+	}
+	return Replay(c, log)
+}
+
+type logParser struct {
+	data  []byte
+	dec   *xml.Decoder
+	ops   []BuildOp
+	style []string
+}
+
+// parseLog returns operations reproducing the document data.
+func parseLog(data []byte) ([]BuildOp, error) {
+	p := &logParser{data: data, dec: xml.NewDecoder(bytes.NewReader(data))}
+	for {
+		tok, err := p.dec.RawToken()
+		if err == io.EOF {
+			return nil, errors.New("svg: parse: no svg element")
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if rawName(start.Name).Local != "svg" {
+			return nil, errors.New("svg: parse: root element is not svg")
+		}
+		p.ops = append(p.ops, BuildOp{Op: "doc", Attrs: parseAttrs(start.Attr)})
+		if err := p.children(0, "svg"); err != nil {
+			return nil, err
+		}
+		if len(p.style) != 0 {
+			p.ops = append(p.ops, BuildOp{Op: "stylesheet", Value: strings.Join(p.style, " ")})
+		}
+		return p.ops, nil
+	}
+}
+
+// children adds operations for the content of the element
+// with the specified name, logged at index parent.
+func (p *logParser) children(parent int, name string) error {
+	isText := name == "text" || name == "tspan"
+	for {
+		tok, err := p.dec.RawToken()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return nil
+		case xml.CharData:
+			if isText {
+				p.ops = append(p.ops, BuildOp{Op: "text", Parent: parent, Value: string(t)})
+			}
+		case xml.StartElement:
+			if err := p.elem(parent, t); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (p *logParser) elem(parent int, start xml.StartElement) error {
+	name := rawName(start.Name).Local
+	switch name {
+	case "title", "desc":
+		s, err := p.text()
+		if err != nil {
+			return err
+		}
+		p.ops = append(p.ops, BuildOp{Op: name, Parent: parent, Value: s})
+		return nil
+	case "style":
+		s, err := p.text()
+		if err != nil {
+			return err
+		}
+		p.style = append(p.style, strings.TrimSpace(s))
+		return nil
+	case "metadata":
+		attrs, err := p.metadata(start)
+		if err != nil {
+			return err
+		}
+		p.ops = append(p.ops, BuildOp{Op: "elem", Parent: parent, Name: name, Attrs: attrs})
+		return nil
+	}
+	if _, ok := buildElems[name]; !ok {
+		return errors.New("svg: parse: unsupported element " + name)
+	}
+	i := len(p.ops)
+	p.ops = append(p.ops, BuildOp{Op: "elem", Parent: parent, Name: name, Attrs: parseAttrs(start.Attr)})
+	if name == "foreignObject" {
+		content, err := p.inner()
+		if err != nil {
+			return err
+		}
+		if content != "" {
+			p.ops = append(p.ops, BuildOp{Op: "text", Parent: i, Value: content})
+		}
+		return nil
+	}
+	return p.children(i, name)
+}
+
+// text returns the character data of the current element.
+func (p *logParser) text() (string, error) {
+	var b strings.Builder
+	for {
+		tok, err := p.dec.RawToken()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			b.Write(t)
+		case xml.StartElement:
+			return "", errors.New("svg: parse: unexpected element within " + rawName(t.Name).Local)
+		case xml.EndElement:
+			return b.String(), nil
+		}
+	}
+}
+
+// inner returns the markup of the content of the current element.
+func (p *logParser) inner() (string, error) {
+	begin := p.dec.InputOffset()
+	end := begin
+	for depth := 0; ; {
+		tok, err := p.dec.RawToken()
+		if err != nil {
+			return "", err
+		}
+		switch tok.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				return string(p.data[begin:end]), nil
+			}
+			depth--
+		}
+		end = p.dec.InputOffset()
+	}
+}
+
+// metadata returns the fields of a <metadata> element, as
+// created by Metadata, as attributes of a BuildOp.
+func (p *logParser) metadata(start xml.StartElement) ([]BuildAttr, error) {
+	var attrs []BuildAttr
+	for _, a := range start.Attr {
+		if rawName(a.Name).Local == "id" {
+			attrs = append(attrs, BuildAttr{"id", a.Value})
+		}
+	}
+	var path []string
+	for {
+		tok, err := p.dec.RawToken()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := rawName(t.Name).Local
+			path = append(path, name)
+			if name == "cc:license" {
+				for _, a := range t.Attr {
+					if rawName(a.Name).Local == "rdf:resource" {
+						attrs = append(attrs, BuildAttr{"license", a.Value})
+					}
+				}
+			}
+		case xml.EndElement:
+			if len(path) == 0 {
+				return attrs, nil
+			}
+			path = path[:len(path)-1]
+		case xml.CharData:
+			field := ""
+			switch strings.Join(path, " ") {
+			case "rdf:RDF cc:Work dc:title":
+				field = "title"
+			case "rdf:RDF cc:Work dc:description":
+				field = "description"
+			case "rdf:RDF cc:Work dc:date":
+				field = "date"
+			case "rdf:RDF cc:Work dc:creator cc:Agent dc:title":
+				field = "creator"
+			}
+			if field != "" {
+				attrs = append(attrs, BuildAttr{field, string(t)})
+			}
+		}
+	}
+}
+
+// parseAttrs converts attributes into BuildOp attributes,
+// leaving out namespace declarations. Xlink:href is treated
+// like href.
+func parseAttrs(list []xml.Attr) []BuildAttr {
+	var attrs []BuildAttr
+	for _, a := range list {
+		name := rawName(a.Name).Local
+		switch {
+		case name == "xmlns" || a.Name.Space == "xmlns":
+			continue
+		case name == "xlink:href":
+			name = "href"
+		}
+		attrs = append(attrs, BuildAttr{name, a.Value})
+	}
+	return attrs
+}
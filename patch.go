@@ -0,0 +1,288 @@
+package svg
+
+import (
+	"encoding/xml"
+	"sort"
+	"strings"
+)
+
+// PatchOp is an operation updating the DOM of a document,
+// as displayed in a browser, to a newer version. It may be
+// encoded as JSON. Elements are addressed by their IDs; an
+// empty ID, or Parent, refers to the root <svg> element.
+//
+// Operations are:
+//
+//	"remove":  remove the element ID
+//	"insert":  insert Markup as child of Parent, at the position
+//	           Index (if missing: 0) within its element children
+//	"replace": replace the element ID by Markup; if ID is empty,
+//	           the whole document is replaced
+//	"attrs":   set the attributes in Set, and remove those in Remove,
+//	           of the element ID
+type PatchOp struct {
+	Op     string            `json:"op"`
+	ID     string            `json:"id,omitempty"`
+	Parent string            `json:"parent,omitempty"`
+	Index  int               `json:"index,omitempty"`
+	Set    map[string]string `json:"set,omitempty"`
+	Remove []string          `json:"remove,omitempty"`
+	Markup string            `json:"markup,omitempty"`
+}
+
+// Diff returns the operations needed to update the DOM of
+// document a, as encoded, to the state of document b.
+// Elements are matched by their IDs, so elements expected
+// to change between versions should have IDs assigned.
+// Changes to elements without ID are expressed by replacing
+// their closest ancestor having an ID, or, if there is none,
+// the whole document. The operations are to be applied in order.
+func Diff(a, b *Document) ([]PatchOp, error) {
+	df := new(differ)
+	pa, pb := a.prepared(), b.prepared()
+	sa, err := docShell(pa)
+	if err != nil {
+		return nil, err
+	}
+	sb, err := docShell(pb)
+	if err != nil {
+		return nil, err
+	}
+	off := leadingChildren(&pb.Object)
+	if pb.Style != "" {
+		off++
+	}
+	ok, err := df.node("", sa, sb, pa.ElemList, pb.ElemList, off)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		buf, err := xml.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+		df.ops = []PatchOp{{Op: "replace", Markup: string(buf)}}
+	}
+	return df.ops, nil
+}
+
+type differ struct {
+	ops []PatchOp
+}
+
+// docShell returns the markup of d, without its child elements.
+func docShell(d *Document) (string, error) {
+	shell := *d
+	shell.ElemList = nil
+	buf, err := xml.Marshal((*plainDocument)(&shell))
+	return string(buf), err
+}
+
+// node compares two versions of the element with the given ID,
+// given as their markup excluding the child elements, and their
+// lists of children; off is the number of child elements of the
+// new version preceding those in cb. If the differences cannot be expressed
+// by operations addressing the element, or its descendants,
+// false is returned, and no operations are added.
+func (df *differ) node(id, sa, sb string, ca, cb ElemList, off int) (bool, error) {
+	mark := len(df.ops)
+	if sa != sb {
+		attrsA, restA, err := splitStartTag(sa)
+		if err != nil {
+			return false, err
+		}
+		attrsB, restB, err := splitStartTag(sb)
+		if err != nil {
+			return false, err
+		}
+		if restA != restB {
+			return false, nil
+		}
+		op := PatchOp{Op: "attrs", ID: id, Set: make(map[string]string)}
+		for name, v := range attrsB {
+			if va, ok := attrsA[name]; !ok || va != v {
+				op.Set[name] = v
+			}
+		}
+		for name := range attrsA {
+			if _, ok := attrsB[name]; !ok {
+				op.Remove = append(op.Remove, name)
+			}
+		}
+		sort.Strings(op.Remove)
+		df.ops = append(df.ops, op)
+	}
+	ok, err := df.children(id, ca, cb, off)
+	if !ok || err != nil {
+		df.ops = df.ops[:mark]
+	}
+	return ok, err
+}
+
+// children compares the child elements of the element parent.
+func (df *differ) children(parent string, ca, cb ElemList, off int) (bool, error) {
+	ka, err := childKeys(ca)
+	if err != nil {
+		return false, err
+	}
+	kb, err := childKeys(cb)
+	if err != nil {
+		return false, err
+	}
+	inA := make(map[string]int, len(ka))
+	for i, k := range ka {
+		inA[k] = i
+	}
+	inB := make(map[string]int, len(kb))
+	for i, k := range kb {
+		inB[k] = i
+	}
+
+	// Apart from removed and inserted elements with ID,
+	// the lists must be equal.
+	var keptA, keptB []string
+	for _, k := range ka {
+		if _, ok := inB[k]; ok || !isIDKey(k) {
+			keptA = append(keptA, k)
+		}
+	}
+	for _, k := range kb {
+		if _, ok := inA[k]; ok || !isIDKey(k) {
+			keptB = append(keptB, k)
+		}
+	}
+	if strings.Join(keptA, "\x00") != strings.Join(keptB, "\x00") {
+		return false, nil
+	}
+
+	for _, k := range ka {
+		if _, ok := inB[k]; !ok && isIDKey(k) {
+			df.ops = append(df.ops, PatchOp{Op: "remove", ID: k[1:]})
+		}
+	}
+	for i, k := range kb {
+		if !isIDKey(k) {
+			continue
+		}
+		j, ok := inA[k]
+		if ok {
+			if err := df.elem(k[1:], ca[j], cb[i]); err != nil {
+				return false, err
+			}
+			continue
+		}
+		buf, err := xml.Marshal(cb[i])
+		if err != nil {
+			return false, err
+		}
+		df.ops = append(df.ops, PatchOp{Op: "insert", Parent: parent, Index: off + i, Markup: string(buf)})
+	}
+	return true, nil
+}
+
+// elem compares two versions of the element with the given ID.
+func (df *differ) elem(id string, x, y interface{}) error {
+	mx, err := xml.Marshal(x)
+	if err != nil {
+		return err
+	}
+	my, err := xml.Marshal(y)
+	if err != nil {
+		return err
+	}
+	if string(mx) == string(my) {
+		return nil
+	}
+	sx, sy := string(mx), string(my)
+	var cx, cy ElemList
+	off := 0
+	if c, ok := x.(container); ok {
+		if sx, cx, err = containerShell(c); err != nil {
+			return err
+		}
+	}
+	if c, ok := y.(container); ok {
+		if sy, cy, err = containerShell(c); err != nil {
+			return err
+		}
+		off = leadingChildren(&c.container().Object)
+	}
+	ok, err := df.node(id, sx, sy, cx, cy, off)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		df.ops = append(df.ops, PatchOp{Op: "replace", ID: id, Markup: string(my)})
+	}
+	return nil
+}
+
+// containerShell returns the markup of c without its child
+// elements, and the children.
+func containerShell(c container) (string, ElemList, error) {
+	shell := shallowCopy(c)
+	shell.(container).container().ElemList = nil
+	buf, err := xml.Marshal(shell)
+	return string(buf), c.container().ElemList, err
+}
+
+// leadingChildren returns the number of child elements
+// encoded from fields of o, preceding the elements of a container.
+func leadingChildren(o *Object) int {
+	n := 0
+	if o.Title != "" {
+		n++
+	}
+	if o.Desc != "" {
+		n++
+	}
+	return n
+}
+
+// childKeys returns, for each element of el, a key consisting
+// of '#' followed by its ID, if it has one, or else of '<'
+// followed by its markup.
+func childKeys(el ElemList) ([]string, error) {
+	keys := make([]string, len(el))
+	for i, x := range el {
+		if e, ok := x.(element); ok && e.object().ID != "" {
+			keys[i] = "#" + e.object().ID
+			continue
+		}
+		buf, err := xml.Marshal(x)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = "<" + string(buf)
+	}
+	return keys, nil
+}
+
+func isIDKey(k string) bool {
+	return strings.HasPrefix(k, "#")
+}
+
+// splitStartTag returns the attributes of the start tag of the
+// markup s, and the remaining markup, prefixed by the element's name.
+func splitStartTag(s string) (map[string]string, string, error) {
+	dec := xml.NewDecoder(strings.NewReader(s))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, "", err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		attrs := make(map[string]string, len(start.Attr))
+		for _, a := range start.Attr {
+			name := a.Name.Local
+			if a.Name.Space != "" {
+				name = a.Name.Space + ":" + name
+			}
+			attrs[name] = a.Value
+		}
+		return attrs, start.Name.Local + s[dec.InputOffset():], nil
+	}
+}
@@ -0,0 +1,104 @@
+package svg
+
+import (
+	"strings"
+)
+
+// PathBuilder creates path data from float coordinates,
+// to be used with ElemList.Path. The zero value is an empty path.
+//
+//	var p PathBuilder
+//	p.MoveTo(0, 0).LineTo(10, 0).CurveTo(15, 0, 20, 5, 20, 10).Close()
+//	el.Path(p.String())
+type PathBuilder struct {
+	b strings.Builder
+}
+
+func (p *PathBuilder) cmd(c string, pts ...[2]float64) *PathBuilder {
+	if p.b.Len() != 0 {
+		p.b.WriteByte(' ')
+	}
+	p.b.WriteString(c)
+	for i, pt := range pts {
+		if i != 0 {
+			p.b.WriteByte(' ')
+		}
+		p.b.WriteString(formatPoint(pt))
+	}
+	return p
+}
+
+// MoveTo starts a new subpath at x, y.
+func (p *PathBuilder) MoveTo(x, y float64) *PathBuilder {
+	return p.cmd("M", [2]float64{x, y})
+}
+
+// LineTo draws a straight line to x, y.
+func (p *PathBuilder) LineTo(x, y float64) *PathBuilder {
+	return p.cmd("L", [2]float64{x, y})
+}
+
+// HLineTo draws a horizontal line to x.
+func (p *PathBuilder) HLineTo(x float64) *PathBuilder {
+	p.cmd("H")
+	p.b.WriteString(formatFloat(x))
+	return p
+}
+
+// VLineTo draws a vertical line to y.
+func (p *PathBuilder) VLineTo(y float64) *PathBuilder {
+	p.cmd("V")
+	p.b.WriteString(formatFloat(y))
+	return p
+}
+
+// CurveTo draws a cubic Bézier curve to x, y,
+// with control points x1, y1, and x2, y2.
+func (p *PathBuilder) CurveTo(x1, y1, x2, y2, x, y float64) *PathBuilder {
+	return p.cmd("C", [2]float64{x1, y1}, [2]float64{x2, y2}, [2]float64{x, y})
+}
+
+// QuadTo draws a quadratic Bézier curve to x, y,
+// with control point x1, y1.
+func (p *PathBuilder) QuadTo(x1, y1, x, y float64) *PathBuilder {
+	return p.cmd("Q", [2]float64{x1, y1}, [2]float64{x, y})
+}
+
+// ArcTo draws an elliptical arc to x, y, with the parameters
+// of the SVG arc command.
+func (p *PathBuilder) ArcTo(rx, ry, rotation float64, largeArc, sweep bool, x, y float64) *PathBuilder {
+	flag := func(b bool) string {
+		if b {
+			return "1"
+		}
+		return "0"
+	}
+	p.cmd("A", [2]float64{rx, ry})
+	p.b.WriteString(" " + formatFloat(rotation) + " " + flag(largeArc) + "," + flag(sweep) + " " + formatPoint([2]float64{x, y}))
+	return p
+}
+
+// Arc draws a circular arc around cx, cy with radius r, from
+// angle a0 to a1, specified in degrees clockwise from the positive
+// x axis. If the path is empty, a subpath is started at the arc's
+// start point; otherwise, a line is drawn to it first.
+func (p *PathBuilder) Arc(cx, cy, r, a0, a1 float64) *PathBuilder {
+	x, y := polarPoint(cx, cy, r, a0)
+	if p.b.Len() == 0 {
+		p.MoveTo(x, y)
+	} else {
+		p.LineTo(x, y)
+	}
+	writeArc(&p.b, cx, cy, r, a0, a1)
+	return p
+}
+
+// Close closes the current subpath.
+func (p *PathBuilder) Close() *PathBuilder {
+	return p.cmd("Z")
+}
+
+// String returns the path data.
+func (p *PathBuilder) String() string {
+	return p.b.String()
+}
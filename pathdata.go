@@ -0,0 +1,461 @@
+package svg
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// pathSeg is a segment of normalized path data. All coordinates
+// are absolute. Commands are restricted to M, L, C, Q and Z;
+// horizontal and vertical lines are converted to L,
+// shorthand curves to their full forms, and elliptical
+// arcs to cubic Bézier curves.
+type pathSeg struct {
+	cmd byte
+	pts [3][2]float64
+}
+
+// npts returns the number of points used by the segment's command.
+func (s *pathSeg) npts() int {
+	switch s.cmd {
+	case 'M', 'L':
+		return 1
+	case 'Q':
+		return 2
+	case 'C':
+		return 3
+	}
+	return 0
+}
+
+// end returns the end point of the segment; for Z the
+// start point of the subpath, which must be supplied, is returned.
+func (s *pathSeg) end(subpathStart [2]float64) [2]float64 {
+	if n := s.npts(); n != 0 {
+		return s.pts[n-1]
+	}
+	return subpathStart
+}
+
+var errPathSyntax = errors.New("svg: invalid path data")
+
+// parsePath parses SVG path data into normalized segments.
+func parsePath(d string) ([]pathSeg, error) {
+	p := &pathParser{s: d}
+	var segs []pathSeg
+	var cur, start, ctrl [2]float64
+	var cmd, prev byte
+	for {
+		p.skipSpace()
+		if p.i == len(p.s) {
+			break
+		}
+		c := p.s[p.i]
+		switch {
+		case strings.IndexByte("MmLlHhVvCcSsQqTtAaZz", c) != -1:
+			cmd = c
+			p.i++
+		case cmd == 0 || cmd == 'Z' || cmd == 'z':
+			return nil, errPathSyntax
+		}
+		rel := cmd >= 'a'
+		var base [2]float64
+		if rel {
+			base = cur
+		}
+		var seg pathSeg
+		var err error
+		switch cmd {
+		case 'M', 'm':
+			seg.cmd = 'M'
+			seg.pts[0], err = p.point(base)
+			start = seg.pts[0]
+			// subsequent pairs are implicit lineto commands
+			if rel {
+				cmd = 'l'
+			} else {
+				cmd = 'L'
+			}
+		case 'L', 'l':
+			seg.cmd = 'L'
+			seg.pts[0], err = p.point(base)
+		case 'H', 'h':
+			seg.cmd = 'L'
+			seg.pts[0] = cur
+			seg.pts[0][0], err = p.number()
+			seg.pts[0][0] += base[0]
+		case 'V', 'v':
+			seg.cmd = 'L'
+			seg.pts[0] = cur
+			seg.pts[0][1], err = p.number()
+			seg.pts[0][1] += base[1]
+		case 'C', 'c':
+			seg.cmd = 'C'
+			err = p.points(seg.pts[:3], base)
+		case 'S', 's':
+			seg.cmd = 'C'
+			seg.pts[0] = cur
+			if prev == 'C' {
+				seg.pts[0] = reflectPoint(ctrl, cur)
+			}
+			err = p.points(seg.pts[1:3], base)
+		case 'Q', 'q':
+			seg.cmd = 'Q'
+			err = p.points(seg.pts[:2], base)
+		case 'T', 't':
+			seg.cmd = 'Q'
+			seg.pts[0] = cur
+			if prev == 'Q' {
+				seg.pts[0] = reflectPoint(ctrl, cur)
+			}
+			seg.pts[1], err = p.point(base)
+		case 'A', 'a':
+			var arc [5]float64
+			var large, sweep bool
+			var to [2]float64
+			for i := 0; i < 3 && err == nil; i++ {
+				arc[i], err = p.number()
+			}
+			if err == nil {
+				large, err = p.flag()
+			}
+			if err == nil {
+				sweep, err = p.flag()
+			}
+			if err == nil {
+				to, err = p.point(base)
+			}
+			if err != nil {
+				return nil, err
+			}
+			segs = append(segs, arcToCubic(cur, arc[0], arc[1], arc[2], large, sweep, to)...)
+			// a following S or T command has no control
+			// point to reflect, even though the arc has
+			// been converted to cubic curves
+			cur = to
+			prev = 'A'
+			ctrl = cur
+			continue
+		case 'Z', 'z':
+			seg.cmd = 'Z'
+		}
+		if err != nil {
+			return nil, err
+		}
+		segs = append(segs, seg)
+		switch seg.cmd {
+		case 'C':
+			ctrl = seg.pts[1]
+		case 'Q':
+			ctrl = seg.pts[0]
+		}
+		cur = seg.end(start)
+		prev = seg.cmd
+	}
+	return segs, nil
+}
+
+func reflectPoint(p, center [2]float64) [2]float64 {
+	return [2]float64{2*center[0] - p[0], 2*center[1] - p[1]}
+}
+
+type pathParser struct {
+	s string
+	i int
+}
+
+func (p *pathParser) skipSpace() {
+	for p.i < len(p.s) && isPathSpace(p.s[p.i]) {
+		p.i++
+	}
+}
+
+func isPathSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// skipSep skips white space, including an optional comma.
+func (p *pathParser) skipSep() {
+	p.skipSpace()
+	if p.i < len(p.s) && p.s[p.i] == ',' {
+		p.i++
+		p.skipSpace()
+	}
+}
+
+func (p *pathParser) number() (float64, error) {
+	p.skipSep()
+	s := p.s
+	i := p.i
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	ndigits := 0
+	for ; i < len(s) && isDigit(s[i]); i++ {
+		ndigits++
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		for ; i < len(s) && isDigit(s[i]); i++ {
+			ndigits++
+		}
+	}
+	if ndigits == 0 {
+		return 0, errPathSyntax
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && isDigit(s[j]) {
+			for i = j; i < len(s) && isDigit(s[i]); i++ {
+			}
+		}
+	}
+	f, err := strconv.ParseFloat(s[p.i:i], 64)
+	if err != nil {
+		return 0, errPathSyntax
+	}
+	p.i = i
+	return f, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (p *pathParser) flag() (bool, error) {
+	p.skipSep()
+	if p.i < len(p.s) {
+		switch p.s[p.i] {
+		case '0':
+			p.i++
+			return false, nil
+		case '1':
+			p.i++
+			return true, nil
+		}
+	}
+	return false, errPathSyntax
+}
+
+func (p *pathParser) point(base [2]float64) (pt [2]float64, err error) {
+	pt[0], err = p.number()
+	if err != nil {
+		return
+	}
+	pt[1], err = p.number()
+	pt[0] += base[0]
+	pt[1] += base[1]
+	return
+}
+
+func (p *pathParser) points(pts [][2]float64, base [2]float64) (err error) {
+	for i := range pts {
+		pts[i], err = p.point(base)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// arcToCubic converts an elliptical arc, specified using SVG's
+// endpoint parameterization, into a sequence of cubic Bézier
+// segments, each spanning at most 90 degrees.
+func arcToCubic(from [2]float64, rx, ry, phiDeg float64, large, sweep bool, to [2]float64) []pathSeg {
+	if from == to {
+		return nil
+	}
+	rx = math.Abs(rx)
+	ry = math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		return []pathSeg{{cmd: 'L', pts: [3][2]float64{to}}}
+	}
+	phi := phiDeg * math.Pi / 180
+	sin, cos := math.Sincos(phi)
+
+	// step 1: compute (x1', y1')
+	dx := (from[0] - to[0]) / 2
+	dy := (from[1] - to[1]) / 2
+	x1 := cos*dx + sin*dy
+	y1 := -sin*dx + cos*dy
+
+	// correct out-of-range radii
+	if l := x1*x1/(rx*rx) + y1*y1/(ry*ry); l > 1 {
+		l = math.Sqrt(l)
+		rx *= l
+		ry *= l
+	}
+
+	// step 2: compute (cx', cy')
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	k := 0.0
+	if num > 0 && den != 0 {
+		k = math.Sqrt(num / den)
+	}
+	if large == sweep {
+		k = -k
+	}
+	cx1 := k * rx * y1 / ry
+	cy1 := -k * ry * x1 / rx
+
+	// step 3: compute (cx, cy)
+	cx := cos*cx1 - sin*cy1 + (from[0]+to[0])/2
+	cy := sin*cx1 + cos*cy1 + (from[1]+to[1])/2
+
+	// step 4: compute start angle and sweep
+	theta1 := math.Atan2((y1-cy1)/ry, (x1-cx1)/rx)
+	theta2 := math.Atan2((-y1-cy1)/ry, (-x1-cx1)/rx)
+	dtheta := theta2 - theta1
+	if sweep && dtheta < 0 {
+		dtheta += 2 * math.Pi
+	} else if !sweep && dtheta > 0 {
+		dtheta -= 2 * math.Pi
+	}
+
+	n := int(math.Ceil(math.Abs(dtheta)/(math.Pi/2) - 1e-9))
+	if n < 1 {
+		n = 1
+	}
+	delta := dtheta / float64(n)
+	t := 4.0 / 3 * math.Tan(delta/4)
+	ellipsePoint := func(theta float64) (pt, deriv [2]float64) {
+		s, c := math.Sincos(theta)
+		pt = [2]float64{cx + rx*c*cos - ry*s*sin, cy + rx*c*sin + ry*s*cos}
+		deriv = [2]float64{-rx*s*cos - ry*c*sin, -rx*s*sin + ry*c*cos}
+		return
+	}
+	segs := make([]pathSeg, n)
+	theta := theta1
+	p0, d0 := ellipsePoint(theta)
+	for i := range segs {
+		theta += delta
+		p1, d1 := ellipsePoint(theta)
+		if i == n-1 {
+			p1 = to
+		}
+		segs[i] = pathSeg{cmd: 'C', pts: [3][2]float64{
+			{p0[0] + t*d0[0], p0[1] + t*d0[1]},
+			{p1[0] - t*d1[0], p1[1] - t*d1[1]},
+			p1,
+		}}
+		p0, d0 = p1, d1
+	}
+	return segs
+}
+
+func transformPath(segs []pathSeg, m affine) {
+	for i := range segs {
+		s := &segs[i]
+		for j := 0; j < s.npts(); j++ {
+			s.pts[j] = m.apply(s.pts[j])
+		}
+	}
+}
+
+// subpath is a flattened subpath, consisting of straight
+// lines between points only.
+type subpath struct {
+	pts    [][2]float64
+	closed bool
+}
+
+// flattenPath approximates the curves within segs by straight lines
+// deviating at most by tol from the original curve.
+func flattenPath(segs []pathSeg, tol float64) []subpath {
+	var list []subpath
+	var cur *subpath
+	var pos, start [2]float64
+	for i := range segs {
+		s := &segs[i]
+		if s.cmd == 'M' || cur == nil {
+			list = append(list, subpath{})
+			cur = &list[len(list)-1]
+			if s.cmd != 'M' {
+				cur.pts = append(cur.pts, pos)
+			}
+		}
+		switch s.cmd {
+		case 'M':
+			start = s.pts[0]
+			cur.pts = append(cur.pts, start)
+		case 'L':
+			cur.pts = append(cur.pts, s.pts[0])
+		case 'Q':
+			p0, p1, p2 := pos, s.pts[0], s.pts[1]
+			n := curveSteps(dist(p0[0]-2*p1[0]+p2[0], p0[1]-2*p1[1]+p2[1])/4, tol)
+			for k := 1; k <= n; k++ {
+				t := float64(k) / float64(n)
+				u := 1 - t
+				cur.pts = append(cur.pts, [2]float64{
+					u*u*p0[0] + 2*u*t*p1[0] + t*t*p2[0],
+					u*u*p0[1] + 2*u*t*p1[1] + t*t*p2[1],
+				})
+			}
+		case 'C':
+			p0, p1, p2, p3 := pos, s.pts[0], s.pts[1], s.pts[2]
+			dd := math.Max(
+				dist(p0[0]-2*p1[0]+p2[0], p0[1]-2*p1[1]+p2[1]),
+				dist(p1[0]-2*p2[0]+p3[0], p1[1]-2*p2[1]+p3[1]))
+			n := curveSteps(dd*3/4, tol)
+			for k := 1; k <= n; k++ {
+				t := float64(k) / float64(n)
+				u := 1 - t
+				a, b, c, d := u*u*u, 3*u*u*t, 3*u*t*t, t*t*t
+				cur.pts = append(cur.pts, [2]float64{
+					a*p0[0] + b*p1[0] + c*p2[0] + d*p3[0],
+					a*p0[1] + b*p1[1] + c*p2[1] + d*p3[1],
+				})
+			}
+		case 'Z':
+			cur.closed = true
+			cur = nil
+		}
+		pos = s.end(start)
+	}
+	return list
+}
+
+// curveSteps returns the number of straight lines needed to
+// approximate a curve, the second differences of which are bounded
+// by dd, so that the deviation does not exceed tol.
+func curveSteps(dd, tol float64) int {
+	if tol <= 0 {
+		tol = 0.1
+	}
+	n := int(math.Ceil(math.Sqrt(dd / tol)))
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+func dist(dx, dy float64) float64 {
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
+// formatPath converts normalized segments into path data.
+func formatPath(segs []pathSeg) string {
+	var b strings.Builder
+	for i := range segs {
+		s := &segs[i]
+		if i != 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteByte(s.cmd)
+		for j := 0; j < s.npts(); j++ {
+			if j != 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(formatFloat(s.pts[j][0]))
+			b.WriteByte(',')
+			b.WriteString(formatFloat(s.pts[j][1]))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package svg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	for _, tc := range []struct {
+		d    string
+		want string
+	}{
+		{"M10,20 L30,40", "M10,20 L30,40"},
+		{"m10,20 l5,5 h10 v-5 z", "M10,20 L15,25 L25,25 L25,20 Z"},
+		{"M0,0 H10 V10 H0 Z", "M0,0 L10,0 L10,10 L0,10 Z"},
+		{"M.5.5L-1e1-2", "M0.5,0.5 L-10,-2"},
+
+		// implicit repetitions
+		{"M0,0 10,0 10,10", "M0,0 L10,0 L10,10"},
+		{"m1,1 10,0 0,10", "M1,1 L11,1 L11,11"},
+		{"M0,0 C1,1 2,2 3,3 4,4 5,5 6,6", "M0,0 C1,1 2,2 3,3 C4,4 5,5 6,6"},
+		{"M0,0 h10 10", "M0,0 L10,0 L20,0"},
+
+		// shorthand curves
+		{"M0,0 C0,10 10,10 10,0 S20,-10 20,0", "M0,0 C0,10 10,10 10,0 C10,-10 20,-10 20,0"},
+		{"M0,0 c0,10 10,10 10,0 s10,-10 10,0", "M0,0 C0,10 10,10 10,0 C10,-10 20,-10 20,0"},
+		{"M0,0 L10,0 S20,10 30,0", "M0,0 L10,0 C10,0 20,10 30,0"},
+		{"M0,0 Q5,10 10,0 T20,0 T30,0", "M0,0 Q5,10 10,0 Q15,-10 20,0 Q25,10 30,0"},
+		{"M0,0 q5,10 10,0 t10,0", "M0,0 Q5,10 10,0 Q15,-10 20,0"},
+		{"M0,0 C0,10 10,10 10,0 T20,0", "M0,0 C0,10 10,10 10,0 Q10,0 20,0"},
+	} {
+		segs, err := parsePath(tc.d)
+		if err != nil {
+			t.Errorf("%q: %v", tc.d, err)
+			continue
+		}
+		if got := formatPath(segs); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.d, got, tc.want)
+		}
+	}
+}
+
+func TestParsePathArc(t *testing.T) {
+	for _, tc := range []struct {
+		d     string
+		ncurv int
+		tail  string
+	}{
+		{"M0,0 A10,10 0 0 1 20,0", 2, "20,0"},
+		{"M0,0 A10,10 0 0 1 20,0 S 30,10 40,0", 3, "C20,0 30,10 40,0"},
+		{"M0,0 a10,10 0 0 1 20,0 s10,10 20,0", 3, "C20,0 30,10 40,0"},
+		{"M0,0 A10,10 0 0 1 20,0 T 40,0", 2, "Q20,0 40,0"},
+		{"M0,0 A10,10 0 0 1 20,0 10,10 0 0 1 0,0", 4, "0,0"},
+		{"M0,0 A0,10 0 0 1 20,0", 0, "L20,0"},
+	} {
+		segs, err := parsePath(tc.d)
+		if err != nil {
+			t.Errorf("%q: %v", tc.d, err)
+			continue
+		}
+		n := 0
+		for _, s := range segs {
+			if s.cmd == 'C' {
+				n++
+			}
+		}
+		got := formatPath(segs)
+		if n != tc.ncurv || !strings.HasSuffix(got, tc.tail) {
+			t.Errorf("%q: got %q, want %d cubic curves, ending with %q", tc.d, got, tc.ncurv, tc.tail)
+		}
+	}
+}
+
+func TestParsePathErrors(t *testing.T) {
+	for _, d := range []string{
+		"10,10",
+		"M10",
+		"M0,0 L10,x",
+		"M0,0 Z 10,10",
+		"M0,0 A10,10 0 2 1 20,0",
+	} {
+		if _, err := parsePath(d); err == nil {
+			t.Errorf("%q: missing error", d)
+		}
+	}
+}
+
+func TestBakeTransforms(t *testing.T) {
+	d := NewDocument(nil)
+	g := d.Group()
+	g.Translate(10, 20)
+	g.Scale(2)
+	g.Line(0, 0, 1, 1)
+	g.Rect(1, 1, 2, 3).SetRx(1)
+	g.Circle(0, 0, 1)
+	g.Path("M0,0 L1,0 A1,1 0 0 1 1,2")
+	r := g.Group()
+	r.RotateOrig(90)
+	r.Rect(0, 0, 1, 2)
+	g.Text(1, 1, "a")
+	tg := d.Group()
+	tg.Translate(10, 20)
+	tg.Text(1, 1, "b")
+	d.BakeTransforms()
+
+	got := encodeString(t, d)
+	for _, want := range []string{
+		`<g><line x1="10" y1="20" x2="12" y2="22"></line>`,
+		`<rect x="12" y="22" width="4" height="6" rx="2" ry="2"></rect>`,
+		`<circle cx="10" cy="20" r="2"></circle>`,
+		`<path d="M10,20 L12,20 C`,
+		`<g><polygon points="10,20 10,22 6,22 6,20"></polygon></g>`,
+		`<text x="1" y="1" transform="matrix(2,0,0,2,10,20)">a</text></g>`,
+		`<g><text x="11" y="21">b</text></g>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got %s, want it to contain %s", got, want)
+		}
+	}
+}
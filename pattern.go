@@ -0,0 +1,54 @@
+package svg
+
+import (
+	"encoding/xml"
+)
+
+// Pattern is a <pattern> element, a container defining a tile
+// that is repeated to fill or stroke other elements.
+type Pattern struct {
+	XMLName xml.Name `xml:"pattern"`
+
+	X float64 `xml:"x,attr,omitempty"`
+	Y float64 `xml:"y,attr,omitempty"`
+
+	Width   Length `xml:"width,attr,omitempty"`
+	Height  Length `xml:"height,attr,omitempty"`
+	ViewBox Ints   `xml:"viewBox,attr,omitempty"`
+
+	// Units defines the coordinate system of x, y, width, and height,
+	// ContentUnits that of the pattern's child elements. The
+	// values are the same as those of a gradient's units.
+	Units        GradientUnits `xml:"patternUnits,attr,omitempty"`
+	ContentUnits GradientUnits `xml:"patternContentUnits,attr,omitempty"`
+
+	Container
+}
+
+// Pattern appends a <pattern> element with the specified id, and a
+// tile at x, y of size w×h, to el, which is usually a <defs> container.
+// Child elements are added to the returned pattern's Container.
+func (el *ElemList) Pattern(id string, x, y, w, h float64) *Pattern {
+	p := &Pattern{X: x, Y: y, Width: Number(w), Height: Number(h)}
+	p.ID = id
+	el.append(p)
+	return p
+}
+
+// SetUnits sets the patternUnits attribute.
+func (p *Pattern) SetUnits(u GradientUnits) *Pattern {
+	p.Units = u
+	return p
+}
+
+// SetContentUnits sets the patternContentUnits attribute.
+func (p *Pattern) SetContentUnits(u GradientUnits) *Pattern {
+	p.ContentUnits = u
+	return p
+}
+
+// URL returns a reference to the pattern, to be used
+// as value of the fill or stroke property.
+func (p *Pattern) URL() Paint {
+	return Paint("url(#" + p.ID + ")")
+}
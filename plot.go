@@ -0,0 +1,61 @@
+package svg
+
+// Plot describes a rectangular plot area, together with the
+// scales mapping data values onto it: an x scale, a primary
+// y scale, with its axis at the left side, and an optional
+// secondary y scale, with its axis at the right side.
+// Each data series is mapped using either Mapper or
+// SecondaryMapper, depending on the y axis it refers to.
+type Plot struct {
+	Area BBox
+
+	X, Y Scale
+
+	// Y2, if set, is the secondary y scale.
+	Y2 Scale
+
+	// XAxis, YAxis, and Y2Axis contain options, like tick styling,
+	// used when drawing the axes with Container.PlotAxes.
+	// Their Scale, Position, and At fields are set automatically.
+	XAxis, YAxis, Y2Axis Axis
+}
+
+// Mapper returns a CoordMapper for series referring to
+// the primary y axis.
+func (p *Plot) Mapper() *CoordMapper {
+	return &CoordMapper{X: p.X, Y: p.Y}
+}
+
+// SecondaryMapper returns a CoordMapper for series referring to
+// the secondary y axis. If there is no secondary scale,
+// the primary scale is used.
+func (p *Plot) SecondaryMapper() *CoordMapper {
+	if p.Y2 == nil {
+		return p.Mapper()
+	}
+	return &CoordMapper{X: p.X, Y: p.Y2}
+}
+
+// PlotAxes appends a group with class "plot-axes", containing
+// the x axis along the bottom of the plot area, the primary y axis
+// along the left side, and, if p.Y2 is set, the secondary y axis
+// along the right side, which additionally has class "axis-secondary".
+func (c *Container) PlotAxes(p *Plot) *Container {
+	g := c.Group()
+	g.SetClass("plot-axes")
+
+	a := p.XAxis
+	a.Scale, a.Position, a.At = p.X, AxisBottom, p.Area.Y+p.Area.Height
+	g.Axis(&a)
+
+	a = p.YAxis
+	a.Scale, a.Position, a.At = p.Y, AxisLeft, p.Area.X
+	g.Axis(&a)
+
+	if p.Y2 != nil {
+		a = p.Y2Axis
+		a.Scale, a.Position, a.At = p.Y2, AxisRight, p.Area.X+p.Area.Width
+		g.Axis(&a).SetClass("axis axis-secondary")
+	}
+	return g
+}
@@ -0,0 +1,96 @@
+package svg
+
+import (
+	"math"
+	"strings"
+)
+
+// PolarMapper maps polar coordinates onto SVG user coordinates.
+// Radii are mapped using the R scale, angles using the Theta scale,
+// which must yield degrees, measured clockwise from the positive
+// x axis, like in SVG's rotate() transformation.
+type PolarMapper struct {
+	CX, CY float64
+	R      Scale
+	Theta  Scale
+}
+
+// NewPolarMapper returns a PolarMapper centered at cx, cy, mapping
+// radii between 0 and 1 onto 0 to radius, and angles between 0 and
+// 1 onto a0 to a1 degrees.
+func NewPolarMapper(cx, cy, radius, a0, a1 float64) *PolarMapper {
+	return &PolarMapper{
+		CX:    cx,
+		CY:    cy,
+		R:     NewLinearScale(0, 1, 0, radius),
+		Theta: NewLinearScale(0, 1, a0, a1),
+	}
+}
+
+// MapPolar returns the user coordinates of the point at radius r and
+// angle theta.
+func (m *PolarMapper) MapPolar(r, theta float64) (x, y float64) {
+	return polarPoint(m.CX, m.CY, m.R.Map(r), m.Theta.Map(theta))
+}
+
+// Arc appends an arc of radius r between the angles theta0 and theta1.
+func (m *PolarMapper) Arc(el *ElemList, r, theta0, theta1 float64) *ShapeObject {
+	return el.Arc(m.CX, m.CY, m.R.Map(r), m.Theta.Map(theta0), m.Theta.Map(theta1))
+}
+
+// Sector appends an annular sector between the radii r0 and r1,
+// and the angles theta0 and theta1.
+func (m *PolarMapper) Sector(el *ElemList, r0, r1, theta0, theta1 float64) *ShapeObject {
+	return el.Sector(m.CX, m.CY, m.R.Map(r0), m.R.Map(r1), m.Theta.Map(theta0), m.Theta.Map(theta1))
+}
+
+// Arc appends a path describing a circular arc around cx, cy with
+// radius r, from angle a0 to a1, specified in degrees
+// clockwise from the positive x axis.
+func (el *ElemList) Arc(cx, cy, r, a0, a1 float64) *ShapeObject {
+	var p PathBuilder
+	return el.Path(p.Arc(cx, cy, r, a0, a1).String())
+}
+
+// Sector appends a closed path describing an annular sector around
+// cx, cy between the radii r0 and r1, and the angles a0 and a1,
+// specified in degrees clockwise from the positive x axis.
+// If r0 is zero, a pie slice results.
+func (el *ElemList) Sector(cx, cy, r0, r1, a0, a1 float64) *ShapeObject {
+	var p PathBuilder
+	p.Arc(cx, cy, r1, a0, a1)
+	if r0 != 0 {
+		p.Arc(cx, cy, r0, a1, a0)
+	} else {
+		p.LineTo(cx, cy)
+	}
+	return el.Path(p.Close().String())
+}
+
+// writeArc writes SVG arc commands from angle a0 to a1, assuming
+// the current point is already at a0.
+func writeArc(b *strings.Builder, cx, cy, r, a0, a1 float64) {
+	// A single arc command cannot describe a full circle,
+	// so it is split into arcs of less than 360° each.
+	n := int(math.Abs(a1-a0)/360) + 1
+	rs := formatFloat(r)
+	sweep := "1"
+	if a1 < a0 {
+		sweep = "0"
+	}
+	for i := 1; i <= n; i++ {
+		from := a0 + float64(i-1)*(a1-a0)/float64(n)
+		to := a0 + float64(i)*(a1-a0)/float64(n)
+		large := "0"
+		if math.Abs(to-from) > 180 {
+			large = "1"
+		}
+		x, y := polarPoint(cx, cy, r, to)
+		b.WriteString(" A" + rs + "," + rs + " 0 " + large + "," + sweep + " " + formatFloat(x) + "," + formatFloat(y))
+	}
+}
+
+func polarPoint(cx, cy, r, degrees float64) (x, y float64) {
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+	return cx + r*cos, cy + r*sin
+}
@@ -0,0 +1,26 @@
+package svg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArcSpans(t *testing.T) {
+	for _, tc := range []struct {
+		a0, a1 float64
+		narcs  int
+	}{
+		{0, 90, 1},
+		{0, 270, 1},
+		{0, 360, 2},
+		{0, 720, 3},
+		{90, -990, 4},
+	} {
+		var b strings.Builder
+		writeArc(&b, 0, 0, 10, tc.a0, tc.a1)
+		d := b.String()
+		if n := strings.Count(d, "A"); n != tc.narcs {
+			t.Errorf("%v to %v: got %d arcs, want %d: %s", tc.a0, tc.a1, n, tc.narcs, d)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package svg
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+)
+
+// presentationAttrs contains the CSS properties that
+// may be specified as presentation attributes.
+var presentationAttrs = map[string]bool{
+	"alignment-baseline":           true,
+	"baseline-shift":               true,
+	"clip":                         true,
+	"clip-path":                    true,
+	"clip-rule":                    true,
+	"color":                        true,
+	"color-interpolation":          true,
+	"color-interpolation-filters":  true,
+	"color-profile":                true,
+	"color-rendering":              true,
+	"cursor":                       true,
+	"direction":                    true,
+	"display":                      true,
+	"dominant-baseline":            true,
+	"enable-background":            true,
+	"fill":                         true,
+	"fill-opacity":                 true,
+	"fill-rule":                    true,
+	"filter":                       true,
+	"flood-color":                  true,
+	"flood-opacity":                true,
+	"font-family":                  true,
+	"font-size":                    true,
+	"font-size-adjust":             true,
+	"font-stretch":                 true,
+	"font-style":                   true,
+	"font-variant":                 true,
+	"font-weight":                  true,
+	"glyph-orientation-horizontal": true,
+	"glyph-orientation-vertical":   true,
+	"image-rendering":              true,
+	"kerning":                      true,
+	"letter-spacing":               true,
+	"lighting-color":               true,
+	"marker-end":                   true,
+	"marker-mid":                   true,
+	"marker-start":                 true,
+	"mask":                         true,
+	"opacity":                      true,
+	"overflow":                     true,
+	"pointer-events":               true,
+	"shape-rendering":              true,
+	"stop-color":                   true,
+	"stop-opacity":                 true,
+	"stroke":                       true,
+	"stroke-dasharray":             true,
+	"stroke-dashoffset":            true,
+	"stroke-linecap":               true,
+	"stroke-linejoin":              true,
+	"stroke-miterlimit":            true,
+	"stroke-opacity":               true,
+	"stroke-width":                 true,
+	"text-anchor":                  true,
+	"text-decoration":              true,
+	"text-rendering":               true,
+	"unicode-bidi":                 true,
+	"visibility":                   true,
+	"word-spacing":                 true,
+	"writing-mode":                 true,
+}
+
+// presAttrConverter moves declarations of style attributes, and of
+// classes created by MakeStyle, into presentation attributes,
+// see Conf.PresentationAttributes.
+type presAttrConverter struct {
+	classes map[string]string
+	fields  map[reflect.Type]map[string][]int
+}
+
+// elem returns a copy of x with presentation attributes, if
+// x, or one of its tspans, has declarations to be converted;
+// otherwise x is returned.
+func (pc *presAttrConverter) elem(x interface{}) interface{} {
+	e, ok := x.(element)
+	if !ok {
+		return x
+	}
+	var data TextData
+	if t, ok := x.(*text); ok {
+		data = pc.textData(t.Data)
+	}
+	decls, nClass := pc.decls(&e.object().Styling)
+	if decls == nil && data == nil {
+		return x
+	}
+	x = shallowCopy(x)
+	if data != nil {
+		x.(*text).Data = data
+	}
+	if decls != nil {
+		pc.apply(x, x.(element).object(), decls, nClass)
+	}
+	return x
+}
+
+// textData returns a copy of data with converted tspans,
+// or nil, if no tspan has declarations to be converted.
+func (pc *presAttrConverter) textData(data TextData) TextData {
+	var cp TextData
+	for i, x := range data {
+		ts, ok := x.(*tspan)
+		if !ok {
+			continue
+		}
+		sub := pc.textData(ts.Data)
+		decls, nClass := pc.decls(&ts.Styling)
+		if sub == nil && decls == nil {
+			continue
+		}
+		ts2 := *ts
+		if sub != nil {
+			ts2.Data = sub
+		}
+		if decls != nil {
+			pc.apply(&ts2, &ts2.Object, decls, nClass)
+		}
+		if cp == nil {
+			cp = append(TextData(nil), data...)
+		}
+		cp[i] = &ts2
+	}
+	return cp
+}
+
+// decls returns the declarations applying to st, or nil, if there
+// are none. The first nClass declarations are those of its classes
+// created by MakeStyle.
+func (pc *presAttrConverter) decls(st *Styling) (list []declaration, nClass int) {
+	for _, class := range strings.Fields(st.Class) {
+		if style, ok := pc.classes[class]; ok {
+			list = appendDecls(list, style)
+		}
+	}
+	nClass = len(list)
+	return appendDecls(list, st.Style), nClass
+}
+
+func appendDecls(list []declaration, style string) []declaration {
+	for _, s := range strings.Split(style, ";") {
+		i := strings.IndexByte(s, ':')
+		if i == -1 {
+			continue
+		}
+		list = append(list, declaration{strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])})
+	}
+	return list
+}
+
+// apply adds presentation attributes to obj, the Object of
+// element x, for the declarations that correspond to one.
+// Declarations of properties that are already set using an
+// attribute of x, or that are repeated, like the fallback
+// colors created by Paint.Decl, and declarations using
+// CSS-only syntax, are not converted; those of the style attribute remain there,
+// taking precedence over attributes, those of the first nClass
+// declarations, which stem from classes, are left to the
+// stylesheet.
+func (pc *presAttrConverter) apply(x interface{}, obj *Object, decls []declaration, nClass int) {
+	v := reflect.ValueOf(x).Elem()
+	fields, ok := pc.fields[v.Type()]
+	if !ok {
+		fields = make(map[string][]int)
+		attrFields(v.Type(), nil, fields)
+		pc.fields[v.Type()] = fields
+	}
+	set := make(map[string]bool)
+	for _, a := range obj.ExtraAttr {
+		if ea, ok := a.(*extraAttr); ok {
+			set[ea.name] = true
+		}
+	}
+	obj.ExtraAttr = append([]xml.MarshalerAttr(nil), obj.ExtraAttr...)
+	var style []string
+	for i, d := range decls {
+		convert := presentationAttrs[d.prop] && !set[d.prop] && !cssOnlyValue(d.value)
+		if idx, ok := fields[d.prop]; ok && convert {
+			convert = v.FieldByIndex(idx).IsZero()
+		}
+		if !convert {
+			if i >= nClass {
+				style = append(style, d.prop+":"+d.value)
+			}
+			continue
+		}
+		obj.Attr(d.prop, d.value)
+		set[d.prop] = true
+	}
+	obj.Style = strings.Join(style, ";")
+}
+
+// cssOnlyValue reports whether a declared value uses syntax
+// that is not valid within presentation attributes, like var(),
+// calc(), or an !important annotation.
+func cssOnlyValue(v string) bool {
+	return strings.Contains(v, "!") || strings.Contains(v, "var(") || strings.Contains(v, "calc(")
+}
@@ -0,0 +1,154 @@
+package svg
+
+import (
+	"encoding/xml"
+)
+
+// Presentation contains typed presentation attributes, that style
+// an object without CSS. Declarations of the same properties within
+// style attributes, or the embedded stylesheet, take precedence.
+type Presentation struct {
+	Fill          Paint  `xml:"fill,attr,omitempty"`
+	FillOpacity   Length `xml:"fill-opacity,attr,omitempty"`
+	Stroke        Paint  `xml:"stroke,attr,omitempty"`
+	StrokeWidth   Length `xml:"stroke-width,attr,omitempty"`
+	StrokeOpacity Length `xml:"stroke-opacity,attr,omitempty"`
+	Opacity       Length `xml:"opacity,attr,omitempty"`
+
+	// StrokeDashArray contains the lengths of alternating dashes
+	// and gaps; StrokeDashOffset shifts the start of the pattern.
+	// Animating the offset from the length of a path, which may be
+	// normalized using ShapeObject.PathLength, to zero, with a dash
+	// of the same length, draws the path progressively.
+	StrokeDashArray  Floats64 `xml:"stroke-dasharray,attr,omitempty"`
+	StrokeDashOffset float64  `xml:"stroke-dashoffset,attr,omitempty"`
+
+	err error
+}
+
+// SetFill sets the fill attribute.
+func (p *Presentation) SetFill(fill Paint) *Presentation {
+	p.Fill = fill
+	return p
+}
+
+// SetFillOpacity sets the fill-opacity attribute.
+func (p *Presentation) SetFillOpacity(opacity float64) *Presentation {
+	p.FillOpacity = Number(opacity)
+	p.checkNumber("fill-opacity", opacity)
+	return p
+}
+
+// SetStroke sets the stroke attribute.
+func (p *Presentation) SetStroke(stroke Paint) *Presentation {
+	p.Stroke = stroke
+	return p
+}
+
+// SetStrokeWidth sets the stroke-width attribute.
+func (p *Presentation) SetStrokeWidth(w float64) *Presentation {
+	p.StrokeWidth = Number(w)
+	p.checkNumber("stroke-width", w)
+	return p
+}
+
+// SetStrokeOpacity sets the stroke-opacity attribute.
+func (p *Presentation) SetStrokeOpacity(opacity float64) *Presentation {
+	p.StrokeOpacity = Number(opacity)
+	p.checkNumber("stroke-opacity", opacity)
+	return p
+}
+
+// SetOpacity sets the opacity attribute.
+func (p *Presentation) SetOpacity(opacity float64) *Presentation {
+	p.Opacity = Number(opacity)
+	p.checkNumber("opacity", opacity)
+	return p
+}
+
+// SetStrokeDashArray sets the stroke-dasharray attribute
+// to the lengths of alternating dashes and gaps.
+func (p *Presentation) SetStrokeDashArray(lengths ...float64) *Presentation {
+	p.StrokeDashArray = lengths
+	for _, l := range lengths {
+		p.checkNumber("stroke-dasharray", l)
+	}
+	return p
+}
+
+// SetStrokeDashOffset sets the stroke-dashoffset attribute.
+func (p *Presentation) SetStrokeDashOffset(offset float64) *Presentation {
+	p.StrokeDashOffset = offset
+	p.checkNumber("stroke-dashoffset", offset)
+	return p
+}
+
+// decls returns the attributes that are set as declarations.
+func (p *Presentation) decls() []declaration {
+	var list []declaration
+	add := func(prop string, v string) {
+		if v != "" {
+			list = append(list, declaration{prop, v})
+		}
+	}
+	length := func(l Length) string {
+		if l == nil {
+			return ""
+		}
+		a, err := l.MarshalXMLAttr(xml.Name{})
+		if err != nil {
+			return ""
+		}
+		return a.Value
+	}
+	add("fill", string(p.Fill))
+	add("fill-opacity", length(p.FillOpacity))
+	add("stroke", string(p.Stroke))
+	add("stroke-width", length(p.StrokeWidth))
+	add("stroke-opacity", length(p.StrokeOpacity))
+	add("opacity", length(p.Opacity))
+	if p.StrokeDashArray != nil {
+		a, _ := p.StrokeDashArray.MarshalXMLAttr(xml.Name{})
+		add("stroke-dasharray", a.Value)
+	}
+	if p.StrokeDashOffset != 0 {
+		add("stroke-dashoffset", formatFloat(p.StrokeDashOffset))
+	}
+	return list
+}
+
+// setDecls sets the attributes from a list of declarations,
+// like the one returned by decls, clearing the others.
+// Values that cannot be parsed are ignored.
+func (p *Presentation) setDecls(list []declaration) {
+	*p = Presentation{err: p.err}
+	length := func(dst *Length, v string) {
+		if l, err := parseLength(v); err == nil {
+			*dst = l
+		}
+	}
+	for _, d := range list {
+		switch d.prop {
+		case "fill":
+			p.Fill = Paint(d.value)
+		case "fill-opacity":
+			length(&p.FillOpacity, d.value)
+		case "stroke":
+			p.Stroke = Paint(d.value)
+		case "stroke-width":
+			length(&p.StrokeWidth, d.value)
+		case "stroke-opacity":
+			length(&p.StrokeOpacity, d.value)
+		case "opacity":
+			length(&p.Opacity, d.value)
+		case "stroke-dasharray":
+			if f, err := parseFloats(d.value); err == nil {
+				p.StrokeDashArray = f
+			}
+		case "stroke-dashoffset":
+			if f, err := parseFloats(d.value); err == nil && len(f) == 1 {
+				p.StrokeDashOffset = f[0]
+			}
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package svg
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+)
+
+// PruneDefs removes definitions that are not referenced from
+// anywhere within the document, either by a "#id" attribute
+// value, like the href of a <use> element, or by an url(#id)
+// reference, as used in style values and the embedded stylesheet.
+// Definitions are elements with an ID that are children of <defs>
+// elements, and <symbol>, <pattern>, <marker>, and <filter> elements.
+// References from definitions that are removed are not taken into
+// account. Empty <defs> elements without ID are removed too.
+// PruneDefs returns the number of definitions removed.
+func (d *Document) PruneDefs() int {
+	p := &pruner{
+		defs: make(map[string]interface{}),
+		live: make(map[string]bool),
+	}
+	scanRefs(d.Style, p.ref)
+	collectRefs(reflect.ValueOf(&d.Object).Elem(), p.ref)
+	p.visit(d.ElemList, false)
+	p.drain()
+	n := 0
+	d.ElemList = p.remove(d.ElemList, false, &n)
+	return n
+}
+
+type pruner struct {
+	defs  map[string]interface{}
+	live  map[string]bool
+	queue []string
+}
+
+func (p *pruner) ref(id string) {
+	if !p.live[id] {
+		p.live[id] = true
+		p.queue = append(p.queue, id)
+	}
+}
+
+// drain visits the definitions referenced so far,
+// until no new references are found.
+func (p *pruner) drain() {
+	for len(p.queue) != 0 {
+		id := p.queue[0]
+		p.queue = p.queue[1:]
+		if x, ok := p.defs[id]; ok {
+			p.visitElem(x)
+		}
+	}
+}
+
+func (p *pruner) visit(el ElemList, inDefs bool) {
+	for _, x := range el {
+		if id := p.defID(x, inDefs); id != "" {
+			p.defs[id] = x
+			continue
+		}
+		p.visitElem(x)
+	}
+}
+
+func (p *pruner) visitElem(x interface{}) {
+	collectRefs(reflect.ValueOf(x), p.ref)
+	if c, ok := x.(container); ok {
+		_, isDefs := x.(*Defs)
+		p.visit(c.container().ElemList, isDefs)
+	}
+}
+
+// defID returns the ID of x, if x is a definition.
+func (p *pruner) defID(x interface{}, inDefs bool) string {
+	e, ok := x.(element)
+	if !ok {
+		return ""
+	}
+	if !inDefs {
+		switch x.(type) {
+		case *Symbol, *Pattern, *Marker, *Filter:
+		default:
+			return ""
+		}
+	}
+	return e.object().ID
+}
+
+func (p *pruner) remove(el ElemList, inDefs bool, n *int) ElemList {
+	list := el[:0]
+	for _, x := range el {
+		if id := p.defID(x, inDefs); id != "" && !p.live[id] {
+			*n++
+			continue
+		}
+		if c, ok := x.(container); ok {
+			_, isDefs := x.(*Defs)
+			c := c.container()
+			c.ElemList = p.remove(c.ElemList, isDefs, n)
+			if isDefs && len(c.ElemList) == 0 && c.ID == "" {
+				continue
+			}
+		}
+		list = append(list, x)
+	}
+	for i := len(list); i < len(el); i++ {
+		el[i] = nil
+	}
+	return list
+}
+
+var marshalerAttrType = reflect.TypeOf((*xml.MarshalerAttr)(nil)).Elem()
+
+// collectRefs scans the string attributes of the element v,
+// calling ref for each ID referenced. Child elements are not
+// taken into account.
+func collectRefs(v reflect.Value, ref func(id string)) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			collectRefs(v.Elem(), ref)
+		}
+	case reflect.String:
+		scanRefs(v.String(), ref)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			collectRefs(v.Field(i), ref)
+		}
+	case reflect.Slice:
+		if v.Type().Elem() != marshalerAttrType {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			m, ok := v.Index(i).Interface().(xml.MarshalerAttr)
+			if !ok || m == nil {
+				continue
+			}
+			a, err := m.MarshalXMLAttr(xml.Name{})
+			if err == nil {
+				scanRefs(a.Value, ref)
+			}
+		}
+	}
+}
+
+// scanRefs calls ref for each ID referenced by s, either
+// in the form "#id", or using one or more url(#id) expressions.
+func scanRefs(s string, ref func(id string)) {
+	if strings.HasPrefix(s, "#") {
+		ref(s[1:])
+		return
+	}
+	for {
+		i := strings.Index(s, "url(")
+		if i == -1 {
+			return
+		}
+		s = s[i+4:]
+		end := strings.IndexByte(s, ')')
+		if end == -1 {
+			return
+		}
+		id := strings.Trim(strings.TrimSpace(s[:end]), `"'`)
+		if strings.HasPrefix(id, "#") {
+			ref(id[1:])
+		}
+		s = s[end+1:]
+	}
+}
@@ -0,0 +1,194 @@
+package svg
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// SankeyFlow is a weighted flow between two nodes of a Sankey diagram.
+type SankeyFlow struct {
+	From, To string
+	Value    float64
+
+	// Class is added to the class attribute of the flow's ribbon.
+	Class string
+}
+
+// Sankey describes a Sankey diagram to be drawn using Container.Sankey.
+type Sankey struct {
+	Flows []SankeyFlow
+
+	// NodeWidth defaults to 15, NodePadding, the vertical space
+	// between nodes of the same column, to 10.
+	NodeWidth   float64
+	NodePadding float64
+}
+
+type sankeyNode struct {
+	name      string
+	col       int
+	in, out   float64
+	y, h      float64
+	inY, outY float64
+}
+
+func (n *sankeyNode) value() float64 {
+	return math.Max(n.in, n.out)
+}
+
+// Sankey appends a group with class "sankey", containing a Sankey
+// diagram occupying the rectangle at x, y of the specified size.
+// Nodes are created from the names within s.Flows, and assigned to
+// columns according to their longest distance from a source node;
+// nodes without outgoing flows are placed into the last column.
+// The flows must not form cycles.
+// Ribbons, drawn as filled paths bounded by cubic Bézier curves,
+// get class "sankey-link", plus the flow's own class; nodes are
+// drawn as rectangles with class "sankey-node", with labels of
+// class "sankey-label" next to them.
+func (c *Container) Sankey(x, y, w, h float64, s *Sankey) *Container {
+	nw := s.NodeWidth
+	if nw == 0 {
+		nw = 15
+	}
+	pad := s.NodePadding
+	if pad == 0 {
+		pad = 10
+	}
+
+	var nodes []*sankeyNode
+	byName := make(map[string]*sankeyNode)
+	node := func(name string) *sankeyNode {
+		n := byName[name]
+		if n == nil {
+			n = &sankeyNode{name: name}
+			byName[name] = n
+			nodes = append(nodes, n)
+		}
+		return n
+	}
+	for _, f := range s.Flows {
+		node(f.From).out += f.Value
+		node(f.To).in += f.Value
+	}
+
+	// assign columns by longest path; the number of
+	// iterations is limited in case the flows contain cycles
+	ncol := 1
+	for iter := 0; iter < len(nodes); iter++ {
+		changed := false
+		for _, f := range s.Flows {
+			from, to := byName[f.From], byName[f.To]
+			if to.col < from.col+1 {
+				to.col = from.col + 1
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	for _, n := range nodes {
+		if n.col+1 > ncol {
+			ncol = n.col + 1
+		}
+	}
+	for _, n := range nodes {
+		if n.out == 0 {
+			n.col = ncol - 1
+		}
+	}
+	cols := make([][]*sankeyNode, ncol)
+	for _, n := range nodes {
+		cols[n.col] = append(cols[n.col], n)
+	}
+
+	// vertical scale, chosen so that the fullest column fits
+	ky := math.Inf(1)
+	for _, col := range cols {
+		sum := 0.0
+		for _, n := range col {
+			sum += n.value()
+		}
+		if sum > 0 {
+			ky = math.Min(ky, (h-float64(len(col)-1)*pad)/sum)
+		}
+	}
+	if math.IsInf(ky, 1) {
+		ky = 0
+	}
+	colX := func(col int) float64 {
+		if ncol == 1 {
+			return x
+		}
+		return x + float64(col)*(w-nw)/float64(ncol-1)
+	}
+	for _, col := range cols {
+		total := -pad
+		for _, n := range col {
+			n.h = n.value() * ky
+			total += n.h + pad
+		}
+		ny := y + (h-total)/2
+		for _, n := range col {
+			n.y = ny
+			n.inY, n.outY = ny, ny
+			ny += n.h + pad
+		}
+	}
+
+	g := c.Group()
+	g.SetClass("sankey")
+	links := g.Group()
+
+	// order flows by the positions of their nodes,
+	// to avoid ribbons crossing near the nodes
+	flows := make([]SankeyFlow, len(s.Flows))
+	copy(flows, s.Flows)
+	sort.SliceStable(flows, func(i, j int) bool {
+		fi, fj := flows[i], flows[j]
+		if a, b := byName[fi.From].y, byName[fj.From].y; a != b {
+			return a < b
+		}
+		return byName[fi.To].y < byName[fj.To].y
+	})
+	for _, f := range flows {
+		from, to := byName[f.From], byName[f.To]
+		t := f.Value * ky
+		x0 := colX(from.col) + nw
+		x1 := colX(to.col)
+		xm := (x0 + x1) / 2
+		y0, y1 := from.outY, to.inY
+		from.outY += t
+		to.inY += t
+
+		var b strings.Builder
+		b.WriteString("M" + formatPoint([2]float64{x0, y0}))
+		b.WriteString("C" + formatPoint([2]float64{xm, y0}) + " " + formatPoint([2]float64{xm, y1}) + " " + formatPoint([2]float64{x1, y1}))
+		b.WriteString("V" + formatFloat(y1+t))
+		b.WriteString("C" + formatPoint([2]float64{xm, y1 + t}) + " " + formatPoint([2]float64{xm, y0 + t}) + " " + formatPoint([2]float64{x0, y0 + t}))
+		b.WriteString("Z")
+		class := "sankey-link"
+		if f.Class != "" {
+			class += " " + f.Class
+		}
+		links.Path(b.String()).SetClass(class)
+	}
+
+	ng := g.Group()
+	for _, n := range nodes {
+		nx := colX(n.col)
+		ng.Rect(nx, n.y, nw, n.h).SetClass("sankey-node")
+		var l *TextObject
+		if n.col == ncol-1 && ncol > 1 {
+			l = ng.Text(nx-4, n.y+n.h/2, n.name)
+			l.Anchor(AnchorEnd)
+		} else {
+			l = ng.Text(nx+nw+4, n.y+n.h/2, n.name)
+		}
+		l.Dy = EmUnits(0.35)
+		l.SetClass("sankey-label")
+	}
+	return g
+}
@@ -0,0 +1,277 @@
+package svg
+
+import (
+	"math"
+	"strconv"
+)
+
+// Scale maps data values onto coordinates.
+type Scale interface {
+	// Map returns the coordinate corresponding to v.
+	Map(v float64) float64
+
+	// Ticks returns about n values within the scale's domain,
+	// suitable for tick marks on an axis.
+	Ticks(n int) []float64
+
+	// Range returns the coordinates corresponding to the
+	// boundaries of the domain.
+	Range() (r0, r1 float64)
+}
+
+// LinearScale maps the domain [D0, D1] linearly onto the
+// range of coordinates [R0, R1].
+type LinearScale struct {
+	D0, D1 float64
+	R0, R1 float64
+}
+
+// NewLinearScale returns a scale mapping values between d0 and d1
+// linearly onto coordinates between r0 and r1.
+func NewLinearScale(d0, d1, r0, r1 float64) *LinearScale {
+	return &LinearScale{D0: d0, D1: d1, R0: r0, R1: r1}
+}
+
+func (s *LinearScale) Map(v float64) float64 {
+	if s.D1 == s.D0 {
+		return s.R0
+	}
+	return s.R0 + (v-s.D0)/(s.D1-s.D0)*(s.R1-s.R0)
+}
+
+func (s *LinearScale) Ticks(n int) []float64 {
+	return niceTicks(s.D0, s.D1, n)
+}
+
+func (s *LinearScale) Range() (r0, r1 float64) {
+	return s.R0, s.R1
+}
+
+// CoordMapper maps data coordinates onto SVG user coordinates.
+type CoordMapper struct {
+	X, Y Scale
+}
+
+// Map returns the user coordinates of the data point x, y.
+func (m *CoordMapper) Map(x, y float64) (float64, float64) {
+	return m.X.Map(x), m.Y.Map(y)
+}
+
+// niceTicks returns about n multiples of 1, 2, or 5 times a
+// power of ten within [lo, hi].
+func niceTicks(lo, hi float64, n int) []float64 {
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	if n < 1 {
+		n = 1
+	}
+	step := niceStep((hi - lo) / float64(n))
+	if step == 0 {
+		return []float64{lo}
+	}
+	var ticks []float64
+	for i := math.Ceil(lo / step); i <= math.Floor(hi/step); i++ {
+		ticks = append(ticks, i*step+0)
+	}
+	return ticks
+}
+
+// niceStep rounds raw to a multiple of 1, 2, or 5 times a power of ten.
+func niceStep(raw float64) float64 {
+	if raw <= 0 || math.IsInf(raw, 0) || math.IsNaN(raw) {
+		return 0
+	}
+	exp := math.Pow(10, math.Floor(math.Log10(raw)))
+	switch f := raw / exp; {
+	case f < 1.5:
+		return exp
+	case f < 3:
+		return 2 * exp
+	case f < 7:
+		return 5 * exp
+	}
+	return 10 * exp
+}
+
+// stepDecimals returns the number of decimal places needed
+// to format multiples of step.
+func stepDecimals(step float64) int {
+	if step <= 0 {
+		return 0
+	}
+	d := int(-math.Floor(math.Log10(step) + 1e-9))
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// TickFormatter may be implemented by scales that provide
+// their own default formatting of tick labels.
+type TickFormatter interface {
+	// Format returns the label for tick value v, given the
+	// number of ticks n requested.
+	Format(v float64, n int) string
+}
+
+// LogScale maps the domain [D0, D1], which must not include
+// zero or negative values, logarithmically onto the range of
+// coordinates [R0, R1]. Values less than or equal to zero
+// are mapped onto the coordinate of the smaller domain boundary.
+type LogScale struct {
+	D0, D1 float64
+	R0, R1 float64
+}
+
+// NewLogScale returns a scale mapping values between d0 and d1
+// logarithmically onto coordinates between r0 and r1.
+func NewLogScale(d0, d1, r0, r1 float64) *LogScale {
+	return &LogScale{D0: d0, D1: d1, R0: r0, R1: r1}
+}
+
+func (s *LogScale) Map(v float64) float64 {
+	if v <= 0 {
+		v = math.Min(s.D0, s.D1)
+	}
+	l0 := math.Log10(s.D0)
+	l1 := math.Log10(s.D1)
+	if l0 == l1 {
+		return s.R0
+	}
+	return s.R0 + (math.Log10(v)-l0)/(l1-l0)*(s.R1-s.R0)
+}
+
+// Ticks returns powers of ten within the domain. If the
+// domain spans more than n decades, only every k-th power is
+// returned; if it spans less than two decades, multiples
+// of two and five are added.
+func (s *LogScale) Ticks(n int) []float64 {
+	lo, hi := s.D0, s.D1
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	if lo <= 0 || n < 1 {
+		return nil
+	}
+	e0 := math.Floor(math.Log10(lo))
+	e1 := math.Ceil(math.Log10(hi))
+	k := math.Max(1, math.Ceil((e1-e0)/float64(n)))
+	mult := []float64{1}
+	if math.Log10(hi/lo) < 2 {
+		mult = []float64{1, 2, 5}
+	}
+	var ticks []float64
+	for e := math.Ceil(e0/k) * k; e <= e1; e += k {
+		for _, m := range mult {
+			v := m * math.Pow(10, e)
+			if v >= lo && v <= hi {
+				ticks = append(ticks, v)
+			}
+		}
+	}
+	return ticks
+}
+
+func (s *LogScale) Range() (r0, r1 float64) {
+	return s.R0, s.R1
+}
+
+func (s *LogScale) Format(v float64, n int) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// SymlogScale maps the domain [D0, D1] onto the range of
+// coordinates [R0, R1] using a symmetric logarithmic transformation,
+// sign(v)·log10(1+|v|/C), which is approximately linear near zero,
+// and logarithmic for values much larger than C in magnitude.
+// Other than LogScale, it is suitable for domains containing zero
+// or negative values.
+type SymlogScale struct {
+	D0, D1 float64
+	R0, R1 float64
+
+	// C is the constant determining the size of the linear
+	// region around zero; it defaults to 1.
+	C float64
+}
+
+// NewSymlogScale returns a scale mapping values between d0 and d1
+// onto coordinates between r0 and r1 using a symmetric logarithmic
+// transformation with constant c.
+func NewSymlogScale(d0, d1, r0, r1, c float64) *SymlogScale {
+	return &SymlogScale{D0: d0, D1: d1, R0: r0, R1: r1, C: c}
+}
+
+func (s *SymlogScale) transform(v float64) float64 {
+	c := s.C
+	if c <= 0 {
+		c = 1
+	}
+	l := math.Log10(1 + math.Abs(v)/c)
+	if v < 0 {
+		return -l
+	}
+	return l
+}
+
+func (s *SymlogScale) Map(v float64) float64 {
+	t0 := s.transform(s.D0)
+	t1 := s.transform(s.D1)
+	if t0 == t1 {
+		return s.R0
+	}
+	return s.R0 + (s.transform(v)-t0)/(t1-t0)*(s.R1-s.R0)
+}
+
+// Ticks returns zero, if contained in the domain, and values within
+// the domain outside of the linear region between -C and C, which
+// are C multiplied by powers of ten, like C, 10·C, and 100·C, or,
+// for narrow domains, also by multiples of them, like 2·C and 5·C.
+func (s *SymlogScale) Ticks(n int) []float64 {
+	lo, hi := s.D0, s.D1
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	c := s.C
+	if c <= 0 {
+		c = 1
+	}
+	var ticks []float64
+	if lo < 0 {
+		neg := symlogTicks(math.Max(-hi, 0), -lo, c, n)
+		for i := len(neg) - 1; i >= 0; i-- {
+			ticks = append(ticks, -neg[i])
+		}
+	}
+	if lo <= 0 && hi >= 0 {
+		ticks = append(ticks, 0)
+	}
+	if hi > 0 {
+		ticks = append(ticks, symlogTicks(math.Max(lo, 0), hi, c, n)...)
+	}
+	return ticks
+}
+
+// symlogTicks returns ticks for the magnitudes between a and b
+// within the logarithmic region of a SymlogScale, starting at c.
+func symlogTicks(a, b, c float64, n int) []float64 {
+	if b < c {
+		return nil
+	}
+	var ticks []float64
+	for _, v := range (&LogScale{D0: math.Max(a, c) / c, D1: b / c}).Ticks(n) {
+		if v *= c; v >= a && v <= b {
+			ticks = append(ticks, v)
+		}
+	}
+	return ticks
+}
+
+func (s *SymlogScale) Range() (r0, r1 float64) {
+	return s.R0, s.R1
+}
+
+func (s *SymlogScale) Format(v float64, n int) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
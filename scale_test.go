@@ -0,0 +1,27 @@
+package svg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSymlogTicks(t *testing.T) {
+	for _, tc := range []struct {
+		d0, d1, c float64
+		want      []float64
+	}{
+		{-0.5, 100, 1, []float64{0, 1, 10, 100}},
+		{100, -0.5, 0, []float64{0, 1, 10, 100}},
+		{-1000, 1000, 1, []float64{-1000, -100, -10, -1, 0, 1, 10, 100, 1000}},
+		{-50, 500, 10, []float64{-50, -20, -10, 0, 10, 20, 50, 100, 200, 500}},
+		{0.5, 0.9, 1, nil},
+		{5, 30, 1, []float64{5, 10, 20}},
+		{-2000, -20, 1, []float64{-1000, -100}},
+	} {
+		s := NewSymlogScale(tc.d0, tc.d1, 0, 100, tc.c)
+		got := s.Ticks(5)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("[%v, %v], C=%v: got ticks %v, want %v", tc.d0, tc.d1, tc.c, got, tc.want)
+		}
+	}
+}
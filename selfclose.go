@@ -41,9 +41,9 @@ L:
 					continue
 				case 0:
 					// Closing tag matches; now test whether the
-					// previous '>' is part of a </title> tag;
+					// previous '>' is part of a </title> or </desc> tag;
 					// in this case self-closing is not possible
-					if i >= 6 && bytes.Equal(tail[i-6:i], []byte("/title")) {
+					if endsWithChildTag(tail[:i]) {
 						continue
 					}
 					copy(buf[iw:], tail[:i])
@@ -68,12 +68,39 @@ L:
 	return buf[:iw]
 }
 
+// endsWithChildTag reports whether b ends with the closing tag,
+// without '>', of an element that may be a child of the
+// elements in selfClosingTags.
+func endsWithChildTag(b []byte) bool {
+	for _, tag := range childTags {
+		if bytes.HasSuffix(b, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+var childTags = [][]byte{
+	[]byte("/title"),
+	[]byte("/desc"),
+}
+
 var selfClosingTags = [][]byte{
 	[]byte("circle"),
 	[]byte("ellipse"),
+	[]byte("feBlend"),
+	[]byte("feColorMatrix"),
+	[]byte("feComposite"),
+	[]byte("feDropShadow"),
+	[]byte("feFlood"),
+	[]byte("feGaussianBlur"),
+	[]byte("feMergeNode"),
+	[]byte("feOffset"),
+	[]byte("image"),
 	[]byte("line"),
 	[]byte("polygon"),
 	[]byte("polyline"),
 	[]byte("rect"),
+	[]byte("stop"),
 	[]byte("use"),
 }
@@ -0,0 +1,289 @@
+// Package seqdiag creates sequence diagrams, consisting of
+// participants with lifelines, messages exchanged between them,
+// activation boxes, and notes, as SVG documents.
+package seqdiag
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/knieriem/svg"
+)
+
+// Diagram is a sequence diagram. Steps, like messages, are added in
+// chronological order, and are laid out top to bottom.
+type Diagram struct {
+	Participants []string
+
+	// FontSize defaults to 12.
+	FontSize int
+
+	// RowHeight is the vertical distance between
+	// messages; it defaults to 2.5 times the font size.
+	RowHeight int
+
+	// MinSpacing is the minimum distance between lifelines;
+	// it defaults to 120.
+	MinSpacing int
+
+	// Measurer is used to estimate the width of labels;
+	// if nil, svg.DefaultMeasurer is used.
+	Measurer svg.TextMeasurer
+
+	steps []step
+}
+
+type stepKind int
+
+const (
+	message stepKind = iota
+	reply
+	activate
+	deactivate
+	note
+)
+
+type step struct {
+	kind     stepKind
+	from, to int
+	text     string
+}
+
+// New returns a diagram with the specified participants.
+func New(participants ...string) *Diagram {
+	return &Diagram{Participants: participants}
+}
+
+func (d *Diagram) index(p string) int {
+	for i, name := range d.Participants {
+		if name == p {
+			return i
+		}
+	}
+	d.Participants = append(d.Participants, p)
+	return len(d.Participants) - 1
+}
+
+// Message adds a message from one participant to another,
+// drawn as a solid arrow. Participants not known yet are appended
+// to the list of participants.
+func (d *Diagram) Message(from, to, label string) *Diagram {
+	d.steps = append(d.steps, step{kind: message, from: d.index(from), to: d.index(to), text: label})
+	return d
+}
+
+// Reply adds a reply message, drawn as a dashed arrow.
+func (d *Diagram) Reply(from, to, label string) *Diagram {
+	d.steps = append(d.steps, step{kind: reply, from: d.index(from), to: d.index(to), text: label})
+	return d
+}
+
+// Activate starts an activation box on the lifeline of participant p.
+// Activations may be nested.
+func (d *Diagram) Activate(p string) *Diagram {
+	d.steps = append(d.steps, step{kind: activate, from: d.index(p)})
+	return d
+}
+
+// Deactivate ends the innermost activation box of participant p.
+func (d *Diagram) Deactivate(p string) *Diagram {
+	d.steps = append(d.steps, step{kind: deactivate, from: d.index(p)})
+	return d
+}
+
+// Note adds a note right of the lifeline of participant p.
+func (d *Diagram) Note(p, text string) *Diagram {
+	d.steps = append(d.steps, step{kind: note, from: d.index(p), text: text})
+	return d
+}
+
+// Document returns a new SVG document containing the diagram,
+// with its viewBox set to the diagram's size.
+func (d *Diagram) Document(c *svg.Conf) *svg.Document {
+	doc := svg.NewDocument(c)
+	w, h := d.Render(doc, &doc.ElemList)
+	doc.ViewBox = svg.Ints{0, 0, w, h}
+	return doc
+}
+
+const (
+	margin    = 10
+	boxPad    = 10
+	actWidth  = 10
+	arrowSize = 8
+)
+
+// Render appends a group containing the diagram to el,
+// using doc to create styles and IDs, and returns the diagram's size.
+// The arrowheads of messages are drawn using a marker with the ID
+// "seq-arrow", defined within the group. If the diagram has no
+// participants, nothing is appended.
+func (d *Diagram) Render(doc *svg.Document, el *svg.ElemList) (w, h int) {
+	if len(d.Participants) == 0 {
+		return 2 * margin, 2 * margin
+	}
+	fs := d.FontSize
+	if fs == 0 {
+		fs = 12
+	}
+	row := d.RowHeight
+	if row == 0 {
+		row = fs * 5 / 2
+	}
+	m := d.Measurer
+	if m == nil {
+		m = svg.DefaultMeasurer
+	}
+	width := func(s string) int {
+		return int(math.Ceil(m.TextWidth(s, float64(fs))))
+	}
+
+	// horizontal layout
+	boxW := 0
+	for _, p := range d.Participants {
+		boxW = max(boxW, width(p)+2*boxPad)
+	}
+	spacing := d.MinSpacing
+	if spacing == 0 {
+		spacing = 120
+	}
+	spacing = max(spacing, boxW+boxPad)
+	for _, s := range d.steps {
+		if n := abs(s.to - s.from); (s.kind == message || s.kind == reply) && n != 0 {
+			spacing = max(spacing, (width(s.text)+2*boxPad)/n)
+		}
+	}
+	x0 := margin + boxW/2
+	lifeline := func(i int) int {
+		return x0 + i*spacing
+	}
+	boxH := fs + 2*boxPad
+
+	st := func(name, style string) svg.Styling {
+		return doc.MakeStyle(name, style)
+	}
+	lineStyle := st("seq-lifeline", "stroke:#888;stroke-dasharray:4,4")
+	boxStyle := st("seq-box", "fill:#eee;stroke:black")
+	msgStyle := st("seq-message", "stroke:black;fill:none")
+	replyStyle := st("seq-reply", "stroke:black;fill:none;stroke-dasharray:6,3")
+	arrowStyle := st("seq-arrow", "fill:black")
+	actStyle := st("seq-activation", "fill:white;stroke:black")
+	noteStyle := st("seq-note", "fill:#ffc;stroke:#888")
+	labelStyle := st("seq-label", "font-size:"+strconv.Itoa(fs)+"px")
+
+	g := el.Group()
+	g.SetClass("sequence-diagram")
+	arrowID := doc.MakeID("seq-arrow")
+	arrow := g.Defs().Marker(arrowID, arrowSize, arrowSize, arrowSize, arrowSize/2)
+	arrow.ViewBox = svg.Ints{0, 0, arrowSize, arrowSize}
+	arrow.SetOrient(svg.OrientAuto).SetUnits(svg.MarkerUserSpaceOnUse)
+	head := arrow.Polygon()
+	head.AddInt(0, 0)
+	head.AddInt(arrowSize, arrowSize/2)
+	head.AddInt(0, arrowSize)
+	head.WithStyle(arrowStyle)
+	lifelines := g.Group()
+	acts := g.Group()
+	msgs := g.Group()
+	notes := g.Group()
+	heads := g.Group()
+
+	label := func(c *svg.Container, x, y int, s string, a svg.TextAnchor) {
+		t := c.TextInt(x, y, s)
+		if a != "" {
+			t.Anchor(a)
+		}
+		t.WithStyle(labelStyle)
+	}
+
+	// vertical layout of the steps
+	y := margin + boxH + row
+	active := make([][]int, len(d.Participants))
+	actBox := func(p, y0, y1 int) {
+		depth := len(active[p])
+		acts.RectInt(lifeline(p)-actWidth/2+depth*actWidth/2, y0, actWidth, y1-y0).WithStyle(actStyle)
+	}
+	for _, s := range d.steps {
+		switch s.kind {
+		case message, reply:
+			style := msgStyle
+			if s.kind == reply {
+				style = replyStyle
+			}
+			x1, x2 := lifeline(s.from), lifeline(s.to)
+			if s.from == s.to {
+				// self message
+				loop := spacing / 3
+				p := msgs.Path("M" + strconv.Itoa(x1) + "," + strconv.Itoa(y) +
+					" h" + strconv.Itoa(loop) + " v" + strconv.Itoa(row/2) +
+					" H" + strconv.Itoa(x1))
+				p.WithStyle(style)
+				p.SetMarkerEnd(arrowID)
+				label(msgs, x1+loop+4, y+fs/3, s.text, "")
+				y += row / 2
+			} else {
+				l := msgs.LineInt(x1, y, x2, y)
+				l.WithStyle(style)
+				l.SetMarkerEnd(arrowID)
+				label(msgs, (x1+x2)/2, y-fs/2, s.text, svg.AnchorMiddle)
+			}
+			y += row
+		case activate:
+			active[s.from] = append(active[s.from], y-row/2)
+		case deactivate:
+			if n := len(active[s.from]); n != 0 {
+				y0 := active[s.from][n-1]
+				active[s.from] = active[s.from][:n-1]
+				actBox(s.from, y0, y-row/2)
+			}
+		case note:
+			lines := svg.WrapText(s.text, float64(spacing-2*boxPad), float64(fs), m)
+			nw := 0
+			for _, l := range lines {
+				nw = max(nw, width(l))
+			}
+			lh := fs * 6 / 5
+			nh := len(lines)*lh + boxPad
+			x := lifeline(s.from) + actWidth
+			y0 := y - row/2
+			notes.RectInt(x, y0, nw+boxPad, nh).WithStyle(noteStyle)
+			for i, l := range lines {
+				label(notes, x+boxPad/2, y0+boxPad/2+fs+i*lh, l, "")
+			}
+			y += nh
+		}
+	}
+	// close remaining activations
+	for p := range active {
+		for len(active[p]) != 0 {
+			n := len(active[p])
+			y0 := active[p][n-1]
+			active[p] = active[p][:n-1]
+			actBox(p, y0, y-row/2)
+		}
+	}
+
+	end := y
+	for i, p := range d.Participants {
+		x := lifeline(i)
+		lifelines.LineInt(x, margin+boxH, x, end).WithStyle(lineStyle)
+		heads.RectInt(x-boxW/2, margin, boxW, boxH).WithStyle(boxStyle)
+		label(heads, x, margin+boxPad+fs*4/5, p, svg.AnchorMiddle)
+	}
+	w = lifeline(len(d.Participants)-1) + max(boxW/2, spacing/2) + margin
+	return w, end + margin
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
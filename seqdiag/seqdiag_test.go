@@ -0,0 +1,39 @@
+package seqdiag
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDiagram(t *testing.T) {
+	d := New("client", "server")
+	d.Message("client", "server", "request")
+	d.Reply("server", "client", "response")
+	d.Message("server", "server", "log")
+	var buf bytes.Buffer
+	if err := d.Document(nil).Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	if n := strings.Count(got, `<marker `); n != 1 {
+		t.Errorf("got %d markers, want 1", n)
+	}
+	if n := strings.Count(got, `marker-end="url(#seq-arrow)"`); n != 3 {
+		t.Errorf("got %d references to the arrow marker, want 3", n)
+	}
+	if strings.Count(got, "<polygon") != 1 {
+		t.Error("arrowheads are not drawn using the marker only")
+	}
+}
+
+func TestEmptyDiagram(t *testing.T) {
+	doc := New().Document(nil)
+	vb := doc.ViewBox
+	if len(vb) != 4 || vb[2] <= 0 || vb[3] <= 0 {
+		t.Errorf("got viewBox %v, want a positive size", vb)
+	}
+	if len(doc.ElemList) != 0 {
+		t.Errorf("got %d elements, want none", len(doc.ElemList))
+	}
+}
@@ -2,7 +2,6 @@ package svg
 
 import (
 	"encoding/xml"
-	"strconv"
 )
 
 // ShapeObject embeds Object and provides a PathLength attribute
@@ -10,11 +9,46 @@ import (
 type ShapeObject struct {
 	Object
 	PathLength float64 `xml:"pathLength,attr,omitempty"`
+
+	// Marker references, only effective for lines,
+	// polylines, polygons, and paths.
+	MarkerStart string `xml:"marker-start,attr,omitempty"`
+	MarkerMid   string `xml:"marker-mid,attr,omitempty"`
+	MarkerEnd   string `xml:"marker-end,attr,omitempty"`
+
+	// owner is the element embedding the ShapeObject
+	owner hitTester
+}
+
+// SetMarkerStart places the marker with the specified id
+// at the first vertex of the shape.
+func (s *ShapeObject) SetMarkerStart(id string) *ShapeObject {
+	s.MarkerStart = "url(#" + id + ")"
+	return s
+}
+
+// SetMarkerMid places the marker with the specified id at
+// every vertex of the shape except the first and the last.
+func (s *ShapeObject) SetMarkerMid(id string) *ShapeObject {
+	s.MarkerMid = "url(#" + id + ")"
+	return s
+}
+
+// SetMarkerEnd places the marker with the specified id
+// at the last vertex of the shape.
+func (s *ShapeObject) SetMarkerEnd(id string) *ShapeObject {
+	s.MarkerEnd = "url(#" + id + ")"
+	return s
 }
 
 // LineInt draws a line specified by integer coordinates.
 func (el *ElemList) LineInt(x1, y1, x2, y2 int) *ShapeObject {
-	l := &line{X1: float64(x1), Y1: float64(y1), X2: float64(x2), Y2: float64(y2)}
+	return el.Line(float64(x1), float64(y1), float64(x2), float64(y2))
+}
+
+// Line draws a line specified by float coordinates.
+func (el *ElemList) Line(x1, y1, x2, y2 float64) *ShapeObject {
+	l := &line{X1: x1, Y1: y1, X2: x2, Y2: y2}
 	el.append(l)
 	return &l.ShapeObject
 }
@@ -30,25 +64,70 @@ type line struct {
 
 // RectInt draws a rectangle based on integer coordinates.
 func (el *ElemList) RectInt(x, y, w, h int) *Rect {
-	r := &Rect{X: float64(x), Y: float64(y), Width: float64(w), Height: float64(h)}
+	return el.Rect(float64(x), float64(y), float64(w), float64(h))
+}
+
+// Rect draws a rectangle based on float coordinates.
+func (el *ElemList) Rect(x, y, w, h float64) *Rect {
+	r := &Rect{X: x, Y: y, Width: w, Height: h}
 	el.append(r)
 	return r
 }
 
+// Rect is a <rect> element.
 type Rect struct {
-	XMLName     xml.Name `xml:"rect"`
-	X           float64  `xml:"x,attr,omitempty"`
-	Y           float64  `xml:"y,attr,omitempty"`
-	Width       float64  `xml:"width,attr"`
-	Height      float64  `xml:"height,attr"`
-	Rx          float64  `xml:"rx,attr,omitempty"`
-	Ry          float64  `xml:"ry,attr,omitempty"`
-	ShapeObject `xml:"x,attr,omitempty"`
+	XMLName xml.Name `xml:"rect"`
+	X       float64  `xml:"x,attr,omitempty"`
+	Y       float64  `xml:"y,attr,omitempty"`
+	Width   float64  `xml:"width,attr"`
+	Height  float64  `xml:"height,attr"`
+
+	// Rx and Ry are the radii of rounded corners. If only
+	// one of them is set, it is used for both.
+	Rx float64 `xml:"rx,attr,omitempty"`
+	Ry float64 `xml:"ry,attr,omitempty"`
+
+	ShapeObject
+}
+
+// SetRx sets the horizontal corner radius.
+func (r *Rect) SetRx(rx float64) *Rect {
+	r.Rx = rx
+	r.checkNumber("rect", "rx", rx)
+	return r
+}
+
+// SetRy sets the vertical corner radius.
+func (r *Rect) SetRy(ry float64) *Rect {
+	r.Ry = ry
+	r.checkNumber("rect", "ry", ry)
+	return r
+}
+
+// SetPos moves the rectangle's upper left corner to x, y.
+func (r *Rect) SetPos(x, y float64) *Rect {
+	r.X, r.Y = x, y
+	r.checkNumber("rect", "x", x)
+	r.checkNumber("rect", "y", y)
+	return r
+}
+
+// SetSize sets the width and height of the rectangle.
+func (r *Rect) SetSize(w, h float64) *Rect {
+	r.Width, r.Height = w, h
+	r.checkNumber("rect", "width", w)
+	r.checkNumber("rect", "height", h)
+	return r
 }
 
 // CircleInt draws a circle based on integer coordinates.
 func (el *ElemList) CircleInt(cx, cy, r int) *ShapeObject {
-	c := &circle{X: float64(cx), Y: float64(cy), R: float64(r)}
+	return el.Circle(float64(cx), float64(cy), float64(r))
+}
+
+// Circle draws a circle based on float coordinates.
+func (el *ElemList) Circle(cx, cy, r float64) *ShapeObject {
+	c := &circle{X: cx, Y: cy, R: r}
 	el.append(c)
 	return &c.ShapeObject
 }
@@ -63,13 +142,18 @@ type circle struct {
 
 // EllipseInt draws an ellipse based on integer coordinates.
 func (el *ElemList) EllipseInt(cx, cy, rx, ry int) *ShapeObject {
-	e := &ellipse{X: float64(cx), Y: float64(cy), Rx: float64(rx), Ry: float64(ry)}
+	return el.Ellipse(float64(cx), float64(cy), float64(rx), float64(ry))
+}
+
+// Ellipse draws an ellipse based on float coordinates.
+func (el *ElemList) Ellipse(cx, cy, rx, ry float64) *ShapeObject {
+	e := &ellipse{X: cx, Y: cy, Rx: rx, Ry: ry}
 	el.append(e)
 	return &e.ShapeObject
 }
 
 type ellipse struct {
-	XMLName xml.Name `xml:"circle"`
+	XMLName xml.Name `xml:"ellipse"`
 	X       float64  `xml:"cx,attr"`
 	Y       float64  `xml:"cy,attr"`
 	Rx      float64  `xml:"rx,attr"`
@@ -119,14 +203,27 @@ type polygon struct {
 type Points [][2]float64
 
 func (pts Points) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
-	s := make([]string, len(pts))
-	ff := func(f float64) string {
-		return strconv.FormatFloat(f, 'g', -1, 64)
+	for _, pt := range pts {
+		for _, f := range pt {
+			if !validNumber(f) {
+				return xml.Attr{}, &NumberError{Attr: name.Local, Value: f}
+			}
+		}
 	}
+	buf := pts.appendAttr(make([]byte, 0, len(pts)*8))
+	return xml.Attr{Name: name, Value: string(buf)}, nil
+}
+
+func (pts Points) appendAttr(buf []byte) []byte {
 	for i, pt := range pts {
-		s[i] = ff(pt[0]) + "," + ff(pt[1])
+		if i != 0 {
+			buf = append(buf, ' ')
+		}
+		buf = appendFloat(buf, pt[0])
+		buf = append(buf, ',')
+		buf = appendFloat(buf, pt[1])
 	}
-	return makeListAttr(name, s)
+	return buf
 }
 
 // AddInt adds a point specified by integer coordinates.
@@ -0,0 +1,42 @@
+package svg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestShapeAttrs(t *testing.T) {
+	for _, tc := range []struct {
+		add  func(el *ElemList)
+		want string
+	}{
+		{func(el *ElemList) { el.Rect(0, 0, 3, 4) },
+			`<rect width="3" height="4"></rect>`},
+		{func(el *ElemList) { el.Rect(1, 2, 3, 4).SetRx(0.5).SetRy(1) },
+			`<rect x="1" y="2" width="3" height="4" rx="0.5" ry="1"></rect>`},
+		{func(el *ElemList) { el.Rect(0, 0, 1, 1).SetPos(5, 6).SetSize(7, 8).SetRx(2) },
+			`<rect x="5" y="6" width="7" height="8" rx="2"></rect>`},
+		{func(el *ElemList) { el.Rect(1, 2, 3, 4).SetID("r").SetClass("c") },
+			`<rect x="1" y="2" width="3" height="4" id="r" class="c"></rect>`},
+		{func(el *ElemList) { el.Circle(1, 2, 3).SetID("c") },
+			`<circle cx="1" cy="2" r="3" id="c"></circle>`},
+		{func(el *ElemList) { el.Circle(0, 0, 3) },
+			`<circle cx="0" cy="0" r="3"></circle>`},
+		{func(el *ElemList) { el.Ellipse(1, 2, 3, 4) },
+			`<ellipse cx="1" cy="2" rx="3" ry="4"></ellipse>`},
+		{func(el *ElemList) { el.Ellipse(1, 2, 3, 4).SetClass("e") },
+			`<ellipse cx="1" cy="2" rx="3" ry="4" class="e"></ellipse>`},
+	} {
+		d := NewDocument(nil)
+		tc.add(&d.ElemList)
+		var buf bytes.Buffer
+		if err := d.Encode(&buf); err != nil {
+			t.Fatal(err)
+		}
+		want := `<svg xmlns="http://www.w3.org/2000/svg">` + tc.want + `</svg>`
+		if got := strings.TrimSpace(buf.String()); got != want {
+			t.Errorf("got  %s\nwant %s", got, want)
+		}
+	}
+}
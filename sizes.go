@@ -0,0 +1,73 @@
+package svg
+
+// SizeError describes an element with a zero or negative size.
+// A size of zero disables the rendering of an element,
+// negative sizes are an error.
+type SizeError struct {
+	Elem  string // element name, like "rect"
+	ID    string
+	Attr  string
+	Value float64
+}
+
+func (e *SizeError) Error() string {
+	s := "svg: " + e.Elem
+	if e.ID != "" {
+		s += " #" + e.ID
+	}
+	if e.Value == 0 {
+		return s + ": zero " + e.Attr + " disables rendering"
+	}
+	return s + ": negative " + e.Attr + " " + formatFloat(e.Value)
+}
+
+// CheckSizes returns a SizeError for each size attribute within the
+// document, including definitions, that is zero or negative. Checked
+// are the width and height of rectangles, patterns, and markers, and
+// the radii of circles and ellipses. A pattern's width and height
+// are also reported if they are not set, because they default to zero.
+func (d *Document) CheckSizes() []error {
+	var errs []error
+	checkSizes(d.ElemList, &errs)
+	return errs
+}
+
+func checkSizes(el ElemList, errs *[]error) {
+	for _, x := range el {
+		e, ok := x.(element)
+		if !ok {
+			continue
+		}
+		check := func(attr string, v float64) {
+			if v <= 0 {
+				*errs = append(*errs, &SizeError{Elem: elemName(x), ID: e.object().ID, Attr: attr, Value: v})
+			}
+		}
+		checkLength := func(attr string, l Length, def float64) {
+			if l == nil {
+				check(attr, def)
+			} else if n, ok := l.(number); ok {
+				check(attr, float64(n))
+			}
+		}
+		switch v := x.(type) {
+		case *Rect:
+			check("width", v.Width)
+			check("height", v.Height)
+		case *circle:
+			check("r", v.R)
+		case *ellipse:
+			check("rx", v.Rx)
+			check("ry", v.Ry)
+		case *Pattern:
+			checkLength("width", v.Width, 0)
+			checkLength("height", v.Height, 0)
+		case *Marker:
+			checkLength("markerWidth", v.Width, 3)
+			checkLength("markerHeight", v.Height, 3)
+		}
+		if c, ok := x.(container); ok {
+			checkSizes(c.container().ElemList, errs)
+		}
+	}
+}
@@ -0,0 +1,580 @@
+package svg
+
+import (
+	"encoding/xml"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BuildOp is an operation within a snapshot of a document, as
+// returned by Snapshot. It may be encoded as JSON. Operations refer
+// to their parent by its index within the list; the first
+// operation, of type "doc", represents the document itself.
+//
+// Operations are:
+//
+//	"doc":        set the attributes Attrs of the root <svg> element
+//	"style":      define the style class Name, as created by MakeStyle,
+//	              with the definition Value
+//	"stylesheet": set the embedded stylesheet to Value
+//	"elem":       append an element Name, with attributes Attrs,
+//	              to Parent
+//	"title":      set the title of Parent to Value
+//	"desc":       set the description of Parent to Value
+//	"text":       append the character data Value to the text
+//	              element Parent, or the markup Value to the
+//	              foreignObject element Parent
+type BuildOp struct {
+	Op     string      `json:"op"`
+	Parent int         `json:"parent,omitempty"`
+	Name   string      `json:"name,omitempty"`
+	Attrs  []BuildAttr `json:"attrs,omitempty"`
+	Value  string      `json:"value,omitempty"`
+}
+
+// BuildAttr is an attribute within a BuildOp.
+type BuildAttr struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Snapshot returns a list of operations that reproduces the current
+// state of the document when passed to Replay, which may happen in
+// another process, or on a different machine, after encoding the
+// list as JSON. Each element is represented once, with its current
+// attributes, in document order. Snapshot does not record the calls
+// that built the document; the order in which elements have been
+// created, styles made, or attributes changed, is not preserved.
+// Information that does not end up in the encoded document, like
+// conditions installed with When, or levels of detail, is not
+// part of the snapshot.
+func (d *Document) Snapshot() ([]BuildOp, error) {
+	attrs, err := shellAttrs(&plainDocument{XMLName: d.XMLName, ViewBox: d.ViewBox,
+		Width: d.Width, Height: d.Height, Container: Container{Object: d.Object}})
+	if err != nil {
+		return nil, err
+	}
+	lg := &snapshotter{}
+	lg.add(BuildOp{Op: "doc", Attrs: attrs})
+	lg.object(0, &d.Object)
+
+	names := make([]string, 0, len(d.styles.classMap))
+	for name := range d.styles.classMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lg.add(BuildOp{Op: "style", Name: name, Value: d.styles.classMap[name]})
+	}
+	if d.Style != "" {
+		lg.add(BuildOp{Op: "stylesheet", Value: d.Style})
+	}
+	if err := lg.list(0, d.ElemList); err != nil {
+		return nil, err
+	}
+	return lg.ops, nil
+}
+
+type snapshotter struct {
+	ops []BuildOp
+}
+
+func (lg *snapshotter) add(op BuildOp) int {
+	lg.ops = append(lg.ops, op)
+	return len(lg.ops) - 1
+}
+
+func (lg *snapshotter) object(i int, o *Object) {
+	if o.Title != "" {
+		lg.add(BuildOp{Op: "title", Parent: i, Value: o.Title})
+	}
+	if o.Desc != "" {
+		lg.add(BuildOp{Op: "desc", Parent: i, Value: o.Desc})
+	}
+}
+
+func (lg *snapshotter) list(parent int, el ElemList) error {
+	for _, x := range el {
+		if err := lg.elem(parent, x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (lg *snapshotter) elem(parent int, x interface{}) error {
+	if m, ok := x.(*Metadata); ok {
+		lg.add(BuildOp{Op: "elem", Parent: parent, Name: "metadata", Attrs: m.buildAttrs()})
+		return nil
+	}
+	e, ok := x.(element)
+	if !ok {
+		return errors.New("svg: snapshot: unsupported element " + elemName(x))
+	}
+	shell := shallowCopy(x)
+	obj := shell.(element).object()
+	obj.Title, obj.Desc = "", ""
+	switch v := shell.(type) {
+	case container:
+		v.container().ElemList = nil
+	case *text:
+		v.Data = nil
+	case *tspan:
+		v.Data = nil
+	case *LinearGradient:
+		v.Stops = nil
+	case *RadialGradient:
+		v.Stops = nil
+	case *FeMerge:
+		v.Nodes = nil
+	case *ForeignObject:
+		v.Content = ""
+	}
+	attrs, err := shellAttrs(shell)
+	if err != nil {
+		return err
+	}
+	i := lg.add(BuildOp{Op: "elem", Parent: parent, Name: elemName(x), Attrs: attrs})
+	lg.object(i, e.object())
+
+	switch v := x.(type) {
+	case container:
+		return lg.list(i, v.container().ElemList)
+	case *text:
+		return lg.textData(i, v.Data)
+	case *tspan:
+		return lg.textData(i, v.Data)
+	case *LinearGradient:
+		return lg.stops(i, v.Stops)
+	case *RadialGradient:
+		return lg.stops(i, v.Stops)
+	case *ForeignObject:
+		if v.Content != "" {
+			lg.add(BuildOp{Op: "text", Parent: i, Value: v.Content})
+		}
+	case *FeMerge:
+		for j := range v.Nodes {
+			attrs, err := shellAttrs(&v.Nodes[j])
+			if err != nil {
+				return err
+			}
+			lg.add(BuildOp{Op: "elem", Parent: i, Name: "feMergeNode", Attrs: attrs})
+		}
+	}
+	return nil
+}
+
+func (lg *snapshotter) textData(parent int, data TextData) error {
+	for _, x := range data {
+		switch v := x.(type) {
+		case string:
+			lg.add(BuildOp{Op: "text", Parent: parent, Value: v})
+		case *tspan:
+			if err := lg.elem(parent, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (lg *snapshotter) stops(parent int, stops []GradientStop) error {
+	for i := range stops {
+		attrs, err := shellAttrs(&stops[i])
+		if err != nil {
+			return err
+		}
+		lg.add(BuildOp{Op: "elem", Parent: parent, Name: "stop", Attrs: attrs})
+	}
+	return nil
+}
+
+// shellAttrs returns the attributes of the start tag
+// x is encoded into, apart from the namespace declaration.
+func shellAttrs(x interface{}) ([]BuildAttr, error) {
+	buf, err := xml.Marshal(x)
+	if err != nil {
+		return nil, err
+	}
+	dec := xml.NewDecoder(strings.NewReader(string(buf)))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		var attrs []BuildAttr
+		for _, a := range start.Attr {
+			if a.Name.Local == "xmlns" && a.Name.Space == "" {
+				continue
+			}
+			name := a.Name.Local
+			if a.Name.Space != "" {
+				name = a.Name.Space + ":" + name
+			}
+			attrs = append(attrs, BuildAttr{name, a.Value})
+		}
+		return attrs, nil
+	}
+}
+
+// buildElems contains the element types that may be created by Replay.
+var buildElems = map[string]reflect.Type{}
+
+func init() {
+	for _, x := range []interface{}{
+		&Group{}, &Defs{}, &anchor{}, &Symbol{}, &Pattern{}, &Marker{}, &clipPath{},
+		&line{}, &Rect{}, &circle{}, &ellipse{}, &PolyLine{}, &polygon{}, &path{},
+		&text{}, &tspan{}, &use{}, &LinearGradient{}, &RadialGradient{}, &GradientStop{},
+		&Filter{}, &FeGaussianBlur{}, &FeOffset{}, &FeColorMatrix{}, &FeMerge{}, &FeMergeNode{},
+		&FeBlend{}, &FeFlood{}, &FeComposite{}, &FeDropShadow{}, &Image{},
+		&ForeignObject{},
+	} {
+		buildElems[elemName(x)] = reflect.TypeOf(x).Elem()
+	}
+}
+
+// Replay creates a new document using configuration c, and applies
+// the operations of a snapshot to it, as returned by Snapshot.
+func Replay(c *Conf, ops []BuildOp) (*Document, error) {
+	d := NewDocument(c)
+	nodes := make([]interface{}, len(ops))
+	for i, op := range ops {
+		if op.Op != "doc" && (op.Parent < 0 || op.Parent >= i || nodes[op.Parent] == nil) {
+			return nil, errors.New("svg: snapshot: invalid parent in entry " + strconv.Itoa(i))
+		}
+		var parent interface{}
+		if op.Op != "doc" {
+			parent = nodes[op.Parent]
+		}
+		switch op.Op {
+		case "doc":
+			if i != 0 {
+				return nil, errors.New("svg: snapshot: unexpected doc entry")
+			}
+			if err := setAttrs(reflect.ValueOf(d).Elem(), &d.Object, op.Attrs); err != nil {
+				return nil, err
+			}
+			nodes[i] = d
+		case "style":
+			s := &d.styles
+			if s.classMap == nil {
+				s.defMap = make(map[string]string, 16)
+				s.classMap = make(map[string]string, 16)
+			}
+			s.classMap[op.Name] = op.Value
+			if d.conf.StylesheetUnifyStyles {
+				s.defMap[op.Value] = op.Name
+			}
+		case "stylesheet":
+			d.Style = op.Value
+		case "title", "desc":
+			e, ok := parent.(element)
+			if !ok {
+				return nil, errors.New("svg: snapshot: " + op.Op + " of non-element")
+			}
+			if op.Op == "title" {
+				e.object().Title = op.Value
+			} else {
+				e.object().Desc = op.Value
+			}
+		case "text":
+			switch p := parent.(type) {
+			case *TextObject:
+				p.Data = append(p.Data, op.Value)
+			case *ForeignObject:
+				p.Content += op.Value
+			default:
+				return nil, errors.New("svg: snapshot: text outside of text element")
+			}
+		case "elem":
+			x, err := replayElem(parent, op)
+			if err != nil {
+				return nil, err
+			}
+			nodes[i] = x
+		default:
+			return nil, errors.New("svg: snapshot: unknown operation " + op.Op)
+		}
+	}
+	return d, nil
+}
+
+// replayElem creates the element described by op, appends it
+// to parent, and returns the node later entries may refer to.
+func replayElem(parent interface{}, op BuildOp) (interface{}, error) {
+	if op.Name == "metadata" {
+		p, ok := parent.(container)
+		if !ok {
+			return nil, errors.New("svg: snapshot: metadata within non-container")
+		}
+		m, err := replayMetadata(op.Attrs)
+		if err != nil {
+			return nil, err
+		}
+		p.container().append(m)
+		return m, nil
+	}
+	t, ok := buildElems[op.Name]
+	if !ok {
+		return nil, errors.New("svg: snapshot: unknown element " + op.Name)
+	}
+	v := reflect.New(t)
+	x := v.Interface()
+	var obj *Object
+	if e, ok := x.(element); ok {
+		obj = e.object()
+	}
+	if err := setAttrs(v.Elem(), obj, op.Attrs); err != nil {
+		return nil, err
+	}
+
+	switch p := parent.(type) {
+	case container:
+		p.container().append(x)
+	case *TextObject:
+		ts, ok := x.(*tspan)
+		if !ok {
+			return nil, errors.New("svg: snapshot: " + op.Name + " within text element")
+		}
+		p.Data = append(p.Data, ts)
+	case *Gradient:
+		s, ok := x.(*GradientStop)
+		if !ok {
+			return nil, errors.New("svg: snapshot: " + op.Name + " within gradient")
+		}
+		p.Stops = append(p.Stops, *s)
+		return nil, nil
+	case *FeMerge:
+		n, ok := x.(*FeMergeNode)
+		if !ok {
+			return nil, errors.New("svg: snapshot: " + op.Name + " within feMerge")
+		}
+		p.Nodes = append(p.Nodes, *n)
+		return nil, nil
+	default:
+		return nil, errors.New("svg: snapshot: " + op.Name + " within non-container")
+	}
+
+	switch v := x.(type) {
+	case *text:
+		return &v.TextObject, nil
+	case *tspan:
+		return &v.TextObject, nil
+	case *LinearGradient:
+		return &v.Gradient, nil
+	case *RadialGradient:
+		return &v.Gradient, nil
+	}
+	return x, nil
+}
+
+var (
+	lengthType        = reflect.TypeOf((*Length)(nil)).Elem()
+	intsType          = reflect.TypeOf(Ints(nil))
+	floatsType        = reflect.TypeOf(Floats64(nil))
+	pointsType        = reflect.TypeOf(Points(nil))
+	transformListType = reflect.TypeOf(TransformList(nil))
+)
+
+// setAttrs sets the fields of the struct v that correspond to
+// attrs. Attributes without a corresponding field are added
+// to obj as extra attributes.
+func setAttrs(v reflect.Value, obj *Object, attrs []BuildAttr) error {
+	fields := make(map[string][]int)
+	attrFields(v.Type(), nil, fields)
+	for _, a := range attrs {
+		idx, ok := fields[a.Name]
+		if !ok {
+			if obj == nil {
+				return errors.New("svg: snapshot: unknown attribute " + a.Name)
+			}
+			obj.Attr(a.Name, a.Value)
+			continue
+		}
+		if err := setAttr(v.FieldByIndex(idx), a.Value); err != nil {
+			if obj != nil && presentationAttrs[a.Name] {
+				// a keyword, like "inherit"
+				obj.Attr(a.Name, a.Value)
+				continue
+			}
+			return errors.New("svg: snapshot: attribute " + a.Name + ": " + err.Error())
+		}
+	}
+	return nil
+}
+
+// attrFields adds the indices of the fields of struct type t
+// that are encoded as attributes to the fields map. Fields of
+// outer structs take precedence over those of embedded ones.
+func attrFields(t reflect.Type, index []int, fields map[string][]int) {
+	var embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		idx := append(index[:len(index):len(index)], i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		tag := strings.Split(f.Tag.Get("xml"), ",")
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && tag[0] == "" {
+			f.Index = idx
+			embedded = append(embedded, f)
+			continue
+		}
+		if len(tag) < 2 || tag[1] != "attr" || tag[0] == "" {
+			continue
+		}
+		if _, ok := fields[tag[0]]; !ok {
+			fields[tag[0]] = idx
+		}
+	}
+	for _, f := range embedded {
+		attrFields(f.Type, f.Index, fields)
+	}
+}
+
+// setAttr sets field f to the value of the attribute s.
+func setAttr(f reflect.Value, s string) error {
+	switch f.Type() {
+	case lengthType:
+		l, err := parseLength(s)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(&l).Elem())
+		return nil
+	case intsType:
+		var ints Ints
+		for _, field := range strings.Fields(s) {
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				return err
+			}
+			ints = append(ints, n)
+		}
+		f.Set(reflect.ValueOf(ints))
+		return nil
+	case floatsType:
+		fl, err := parseFloats(s)
+		f.Set(reflect.ValueOf(Floats64(fl)))
+		return err
+	case pointsType:
+		fl, err := parseFloats(s)
+		if err != nil {
+			return err
+		}
+		if len(fl)%2 != 0 {
+			return errors.New("odd number of coordinates")
+		}
+		pts := make(Points, len(fl)/2)
+		for i := range pts {
+			pts[i] = [2]float64{fl[2*i], fl[2*i+1]}
+		}
+		f.Set(reflect.ValueOf(pts))
+		return nil
+	case transformListType:
+		tl, err := parseTransformList(s)
+		f.Set(reflect.ValueOf(tl))
+		return err
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(s)
+	case reflect.Float64:
+		// user units are equivalent to px
+		v, err := strconv.ParseFloat(strings.TrimSuffix(s, "px"), 64)
+		if err != nil {
+			return err
+		}
+		f.SetFloat(v)
+	default:
+		return errors.New("unsupported field type " + f.Type().String())
+	}
+	return nil
+}
+
+// parseLength parses the attribute representation of a Length.
+func parseLength(s string) (Length, error) {
+	if strings.HasPrefix(s, "calc(") && strings.HasSuffix(s, ")") {
+		return calcLength(s[len("calc(") : len(s)-1]), nil
+	}
+	ctor := Number
+	for _, u := range []struct {
+		suffix string
+		ctor   func(float64) Length
+	}{
+		{"rem", RemUnits},
+		{"em", EmUnits},
+		{"ex", ExUnits},
+		{"%", Percentage},
+		{"vmin", VminUnits},
+		{"vmax", VmaxUnits},
+		{"vw", VwUnits},
+		{"vh", VhUnits},
+		{"ch", ChUnits},
+		{"px", PxUnits},
+		{"pt", PtUnits},
+		{"pc", PcUnits},
+		{"mm", MmUnits},
+		{"cm", CmUnits},
+		{"in", InUnits},
+	} {
+		if strings.HasSuffix(s, u.suffix) {
+			s, ctor = strings.TrimSuffix(s, u.suffix), u.ctor
+			break
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return ctor(f), nil
+}
+
+// parseFloats parses a list of numbers separated
+// by white space or commas.
+func parseFloats(s string) ([]float64, error) {
+	var list []float64
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	}) {
+		f, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return list, err
+		}
+		list = append(list, f)
+	}
+	return list, nil
+}
+
+// parseTransformList parses the value of a transform attribute.
+func parseTransformList(s string) (TransformList, error) {
+	var tl TransformList
+	for {
+		s = strings.TrimLeft(s, " ,\t\n\r")
+		if s == "" {
+			return tl, nil
+		}
+		open := strings.IndexByte(s, '(')
+		end := strings.IndexByte(s, ')')
+		if open == -1 || end < open {
+			return tl, errors.New("invalid transform list")
+		}
+		args, err := parseFloats(s[open+1 : end])
+		if err != nil {
+			return tl, err
+		}
+		t := Transform{Name: strings.TrimSpace(s[:open])}
+		for _, a := range args {
+			t.Args = append(t.Args, floatArg(a))
+		}
+		tl = append(tl, t)
+		s = s[end+1:]
+	}
+}
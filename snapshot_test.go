@@ -0,0 +1,38 @@
+package svg
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSnapshotReplay(t *testing.T) {
+	d := NewDocument(nil)
+	d.ViewBox = Ints{0, 0, 100, 50}
+	d.Title = "snapshot"
+	st := d.MakeStyle("box", "fill:#eee;stroke:black")
+	g := d.Group()
+	g.Translate(5, 5)
+	g.Rect(0, 0, 20, 10).SetRx(2).WithStyle(st)
+	g.Text(1, 8, "a").AddSpan("b").X = 3
+	d.Path("M0,0 L10,10").SetMarkerEnd("arrow")
+
+	ops, err := d.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := json.Marshal(ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ops2 []BuildOp
+	if err := json.Unmarshal(buf, &ops2); err != nil {
+		t.Fatal(err)
+	}
+	d2, err := Replay(nil, ops2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := encodeString(t, d2), encodeString(t, d); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,288 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+)
+
+// Stream encodes a document incrementally, for documents too large
+// to be built in memory as a whole. Elements are appended to the
+// embedded ElemList, using its methods like Rect or Group, as usual,
+// and may be modified until Flush is called, which encodes them and
+// removes them from the list, so that their memory can be reclaimed.
+//
+//	s, err := d.StartStream(w)
+//	for _, r := range records {
+//		s.Circle(r.X, r.Y, 1)
+//		if len(s.ElemList) >= 1000 {
+//			if err := s.Flush(); err != nil {
+//				return err
+//			}
+//		}
+//	}
+//	return s.Close()
+type Stream struct {
+	ElemList
+
+	enc   *xml.Encoder
+	w     io.Writer
+	p     *preparer
+	o     encodeOptions
+	open  []streamGroup
+	root  streamGroup
+	err   error
+	ended bool
+}
+
+// streamGroup is a container that has been opened using Begin.
+type streamGroup struct {
+	end   xml.EndElement
+	m     affine
+	cull  bool
+	crisp bool
+	skip  bool
+}
+
+// StartStream writes the start tag of the document to w, followed
+// by the embedded stylesheet, and the elements the document contains
+// already, like definitions, which are the only ones <use> elements
+// marked using Expand may refer to. Further elements are appended
+// to the returned Stream. Elements appended to the document itself
+// after StartStream has been called are not encoded.
+// Options are those of Encode, apart from SelfClose, which is
+// not supported by streams.
+func (d *Document) StartStream(w io.Writer, options ...EncodeOption) (*Stream, error) {
+	s := &Stream{w: w}
+	for _, opt := range options {
+		opt(&s.o)
+	}
+	if s.o.selfClose {
+		return nil, errors.New("svg: stream: self-closing tags not supported")
+	}
+	if s.o.header {
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return nil, err
+		}
+	}
+	s.enc = xml.NewEncoder(w)
+	if s.o.prefix != "" || s.o.indent != "" {
+		s.enc.Indent(s.o.prefix, s.o.indent)
+	}
+	p, m := d.preparer()
+	s.p = p
+	s.root = streamGroup{m: m, cull: p.cull}
+	doc := d.prepared()
+	shell := *doc
+	shell.Style = ""
+	shell.Title, shell.Desc = "", ""
+	shell.ElemList = nil
+	start, err := startTag((*plainDocument)(&shell))
+	if err != nil {
+		return nil, err
+	}
+	s.root.end = start.End()
+	if err = s.enc.EncodeToken(start); err != nil {
+		return nil, err
+	}
+	for _, child := range []struct{ name, content string }{
+		{"style", doc.Style},
+		{"title", doc.Title},
+		{"desc", doc.Desc},
+	} {
+		if child.content != "" {
+			err = s.enc.EncodeElement(child.content, xml.StartElement{Name: xml.Name{Local: child.name}})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err = s.encode(doc.ElemList); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// top returns the innermost open container, or the document.
+func (s *Stream) top() *streamGroup {
+	if n := len(s.open); n != 0 {
+		return &s.open[n-1]
+	}
+	return &s.root
+}
+
+// Flush encodes the elements appended to the stream since the
+// last call of Flush or Begin, into the innermost container opened
+// using Begin, or into the document, and writes them to the
+// underlying writer. The elements are removed from the list.
+func (s *Stream) Flush() error {
+	if s.err != nil {
+		return s.err
+	}
+	g := s.top()
+	if !g.skip {
+		list := s.p.list(s.ElemList, g.m, g.cull, g.crisp)
+		if s.err = s.encode(list); s.err == nil {
+			s.err = s.enc.Flush()
+		}
+	}
+	for i := range s.ElemList {
+		s.ElemList[i] = nil
+	}
+	s.ElemList = s.ElemList[:0]
+	return s.err
+}
+
+func (s *Stream) encode(list ElemList) error {
+	if err := firstNumberError(list); err != nil {
+		return err
+	}
+	if s.o.indent != "" {
+		indentHints(list, s.o.prefix, s.o.indent)
+	}
+	formatNumbers(list)
+	for _, x := range list {
+		if err := s.enc.Encode(x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Begin opens the container c, which must be the last element
+// appended to the stream, like a group returned by the Group method,
+// so that elements appended later, until End is called, are encoded
+// as its children. Its attributes, and the children it contains
+// already, are encoded immediately. Begin may be nested.
+func (s *Stream) Begin(c *Container) error {
+	n := len(s.ElemList)
+	if n == 0 {
+		return errors.New("svg: stream: Begin: no element appended")
+	}
+	x := s.ElemList[n-1]
+	if cc, ok := x.(container); !ok || cc.container() != c {
+		return errors.New("svg: stream: Begin: container is not the last element appended")
+	}
+	s.ElemList = s.ElemList[:n-1]
+	if err := s.Flush(); err != nil {
+		return err
+	}
+	g := s.top()
+	sg := streamGroup{skip: g.skip}
+	children := c.ElemList
+	if !sg.skip {
+		c.ElemList = nil
+		list := s.p.list(ElemList{x}, g.m, g.cull, g.crisp)
+		c.ElemList = children
+		if len(list) == 0 {
+			sg.skip = true
+		} else {
+			shell := list[0].(container).container()
+			own, ok := c.TransformList.matrix()
+			sg.m = g.m.mul(own)
+			sg.cull = g.cull && ok && len(c.CSSTransform) == 0
+			sg.crisp = g.crisp || c.crisp
+			start, err := startTag(withNumberFormat(list[0]))
+			if err != nil {
+				s.err = err
+				return err
+			}
+			sg.end = start.End()
+			if s.err = s.enc.EncodeToken(start); s.err != nil {
+				return s.err
+			}
+			for _, child := range []struct{ name, content string }{
+				{"title", shell.Title},
+				{"desc", shell.Desc},
+			} {
+				if child.content != "" {
+					s.err = s.enc.EncodeElement(child.content, xml.StartElement{Name: xml.Name{Local: child.name}})
+					if s.err != nil {
+						return s.err
+					}
+				}
+			}
+		}
+	}
+	s.open = append(s.open, sg)
+	s.ElemList = append(s.ElemList, children...)
+	c.ElemList = nil
+	return s.Flush()
+}
+
+// End flushes the stream, and closes the container
+// opened by the last call of Begin.
+func (s *Stream) End() error {
+	if len(s.open) == 0 {
+		return errors.New("svg: stream: End without Begin")
+	}
+	if err := s.Flush(); err != nil {
+		return err
+	}
+	g := s.open[len(s.open)-1]
+	s.open = s.open[:len(s.open)-1]
+	if !g.skip {
+		s.err = s.enc.EncodeToken(g.end)
+	}
+	return s.err
+}
+
+// Close flushes the stream, closes the containers still open,
+// and finishes the document.
+func (s *Stream) Close() error {
+	if s.ended {
+		return s.err
+	}
+	for len(s.open) != 0 {
+		if err := s.End(); err != nil {
+			return err
+		}
+	}
+	if err := s.Flush(); err != nil {
+		return err
+	}
+	s.ended = true
+	if s.err = s.enc.EncodeToken(s.root.end); s.err != nil {
+		return s.err
+	}
+	if s.err = s.enc.Flush(); s.err != nil {
+		return s.err
+	}
+	_, s.err = io.WriteString(s.w, "\n")
+	return s.err
+}
+
+// startTag returns the start tag of element x, as it would be
+// encoded, ignoring its content.
+func startTag(x interface{}) (xml.StartElement, error) {
+	buf, err := xml.Marshal(x)
+	if err != nil {
+		return xml.StartElement{}, err
+	}
+	dec := xml.NewDecoder(bytes.NewReader(buf))
+	for {
+		tok, err := dec.RawToken()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		// Keep prefixes as part of the local names, so that the
+		// encoder writes names as they are.
+		start.Name = rawName(start.Name)
+		for i := range start.Attr {
+			start.Attr[i].Name = rawName(start.Attr[i].Name)
+		}
+		return start, nil
+	}
+}
+
+func rawName(n xml.Name) xml.Name {
+	if n.Space == "" {
+		return n
+	}
+	return xml.Name{Local: n.Space + ":" + n.Local}
+}
@@ -0,0 +1,265 @@
+package svg
+
+import (
+	"math"
+)
+
+// LineCap specifies the shape at the ends of open subpaths
+// when they are stroked.
+type LineCap string
+
+const (
+	CapButt   LineCap = "butt"
+	CapRound  LineCap = "round"
+	CapSquare LineCap = "square"
+)
+
+// LineJoin specifies the shape at the corners of paths
+// when they are stroked.
+type LineJoin string
+
+const (
+	JoinMiter LineJoin = "miter"
+	JoinRound LineJoin = "round"
+	JoinBevel LineJoin = "bevel"
+)
+
+// StrokeOutline contains the parameters used by StrokesToPaths.
+type StrokeOutline struct {
+	Width float64
+	Cap   LineCap  // defaults to CapButt
+	Join  LineJoin // defaults to JoinMiter
+
+	// MiterLimit is the limit of the ratio of the miter length
+	// to the stroke width. If it is exceeded, a bevel join is used.
+	// It defaults to 4.
+	MiterLimit float64
+
+	// Tolerance is the maximum deviation of the straight lines
+	// used to approximate curves. It defaults to 0.1.
+	Tolerance float64
+}
+
+// StrokesToPaths replaces the lines, polylines, polygons and paths
+// within the container, and within its descendants, by paths
+// describing the outlines of these elements, as they would appear
+// if stroked according to the parameters in so. This is needed
+// for cutting machines and engravers that only understand
+// filled contours.
+//
+// The outlines consist of overlapping closed subpaths, one for each
+// line segment, join and cap, all having the same orientation, so
+// that they must be filled using the nonzero fill rule.
+// Curves are approximated by straight lines. The ID, title,
+// transformation, and styling of the original elements are kept;
+// it is up to the caller to adjust the styling, so that the
+// resulting paths are filled instead of stroked.
+// Definitions and symbols are left untouched.
+func (c *Container) StrokesToPaths(so StrokeOutline) {
+	if so.Cap == "" {
+		so.Cap = CapButt
+	}
+	if so.Join == "" {
+		so.Join = JoinMiter
+	}
+	if so.MiterLimit == 0 {
+		so.MiterLimit = 4
+	}
+	strokeList(c.ElemList, &so)
+}
+
+func strokeList(el ElemList, so *StrokeOutline) {
+	for i, x := range el {
+		var sub []subpath
+		var shape *ShapeObject
+		switch v := x.(type) {
+		case *Defs, *Symbol, *Pattern, *Marker, *Filter:
+			continue
+		case container:
+			strokeList(v.container().ElemList, so)
+			continue
+		case *line:
+			sub = []subpath{{pts: [][2]float64{{v.X1, v.Y1}, {v.X2, v.Y2}}}}
+			shape = &v.ShapeObject
+		case *PolyLine:
+			sub = []subpath{{pts: v.Points}}
+			shape = &v.ShapeObject
+		case *CompactPolyLine:
+			sub = []subpath{{pts: v.Points.points()}}
+			shape = &v.ShapeObject
+		case *polygon:
+			sub = []subpath{{pts: v.Points, closed: true}}
+			shape = &v.ShapeObject
+		case *path:
+			segs, err := parsePath(v.D)
+			if err != nil {
+				continue
+			}
+			sub = flattenPath(segs, so.Tolerance)
+			shape = &v.ShapeObject
+		default:
+			continue
+		}
+		var segs []pathSeg
+		for _, s := range sub {
+			segs = so.outline(segs, s)
+		}
+		el[i] = bindShape(&path{D: formatPath(segs), ShapeObject: *shape})
+	}
+}
+
+// outline appends the outline of the subpath s to segs.
+func (so *StrokeOutline) outline(segs []pathSeg, s subpath) []pathSeg {
+	w := so.Width / 2
+
+	// remove consecutive duplicate points
+	pts := make([][2]float64, 0, len(s.pts))
+	for _, pt := range s.pts {
+		if n := len(pts); n == 0 || pts[n-1] != pt {
+			pts = append(pts, pt)
+		}
+	}
+	if s.closed && len(pts) > 1 && pts[0] == pts[len(pts)-1] {
+		pts = pts[:len(pts)-1]
+	}
+	switch len(pts) {
+	case 0:
+		return segs
+	case 1:
+		if s.closed {
+			return segs
+		}
+		// a zero length subpath is drawn for round and square caps
+		pt := pts[0]
+		switch so.Cap {
+		case CapRound:
+			segs = append(segs, ellipseSegs(pt[0], pt[1], w, w)...)
+		case CapSquare:
+			segs = appendPolygon(segs,
+				[2]float64{pt[0] - w, pt[1] - w},
+				[2]float64{pt[0] + w, pt[1] - w},
+				[2]float64{pt[0] + w, pt[1] + w},
+				[2]float64{pt[0] - w, pt[1] + w})
+		}
+		return segs
+	}
+
+	n := len(pts)
+	nseg := n - 1
+	if s.closed {
+		nseg = n
+	}
+	for i := 0; i < nseg; i++ {
+		p0, p1 := pts[i], pts[(i+1)%n]
+		nv := normal(p0, p1, w)
+		segs = appendPolygon(segs,
+			[2]float64{p0[0] + nv[0], p0[1] + nv[1]},
+			[2]float64{p1[0] + nv[0], p1[1] + nv[1]},
+			[2]float64{p1[0] - nv[0], p1[1] - nv[1]},
+			[2]float64{p0[0] - nv[0], p0[1] - nv[1]})
+	}
+
+	// joins
+	for i := 0; i < n; i++ {
+		if !s.closed && (i == 0 || i == n-1) {
+			continue
+		}
+		prev, pt, next := pts[(i+n-1)%n], pts[i], pts[(i+1)%n]
+		segs = so.join(segs, prev, pt, next, w)
+	}
+
+	// caps
+	if !s.closed {
+		segs = so.cap(segs, pts[1], pts[0], w)
+		segs = so.cap(segs, pts[n-2], pts[n-1], w)
+	}
+	return segs
+}
+
+// join appends the shape of the join at pt, between the
+// segments prev→pt and pt→next.
+func (so *StrokeOutline) join(segs []pathSeg, prev, pt, next [2]float64, w float64) []pathSeg {
+	if so.Join == JoinRound {
+		return append(segs, ellipseSegs(pt[0], pt[1], w, w)...)
+	}
+	n1 := normal(prev, pt, w)
+	n2 := normal(pt, next, w)
+	d1 := [2]float64{pt[0] - prev[0], pt[1] - prev[1]}
+	d2 := [2]float64{next[0] - pt[0], next[1] - pt[1]}
+	cross := d1[0]*d2[1] - d1[1]*d2[0]
+	if cross == 0 {
+		return segs
+	}
+
+	// select the outer side of the corner
+	s := 1.0
+	if cross > 0 {
+		s = -1
+	}
+	a := [2]float64{pt[0] + s*n1[0], pt[1] + s*n1[1]}
+	b := [2]float64{pt[0] + s*n2[0], pt[1] + s*n2[1]}
+	if so.Join == JoinMiter {
+		// cosine of half the turning angle
+		cosHalf := math.Sqrt((1 + (n1[0]*n2[0]+n1[1]*n2[1])/(w*w)) / 2)
+		if cosHalf > 0 && 1/cosHalf <= so.MiterLimit {
+			m := [2]float64{n1[0] + n2[0], n1[1] + n2[1]}
+			l := w / cosHalf / dist(m[0], m[1])
+			tip := [2]float64{pt[0] + s*m[0]*l, pt[1] + s*m[1]*l}
+			return appendPolygon(segs, pt, a, tip, b)
+		}
+	}
+	return appendPolygon(segs, pt, a, b)
+}
+
+// cap appends the shape of the cap at the end point pt
+// of the segment prev→pt.
+func (so *StrokeOutline) cap(segs []pathSeg, prev, pt [2]float64, w float64) []pathSeg {
+	switch so.Cap {
+	case CapRound:
+		segs = append(segs, ellipseSegs(pt[0], pt[1], w, w)...)
+	case CapSquare:
+		nv := normal(prev, pt, w)
+		d := [2]float64{-nv[1], nv[0]}
+		if (pt[0]-prev[0])*d[0]+(pt[1]-prev[1])*d[1] < 0 {
+			d[0], d[1] = -d[0], -d[1]
+		}
+		segs = appendPolygon(segs,
+			[2]float64{pt[0] + nv[0], pt[1] + nv[1]},
+			[2]float64{pt[0] + nv[0] + d[0], pt[1] + nv[1] + d[1]},
+			[2]float64{pt[0] - nv[0] + d[0], pt[1] - nv[1] + d[1]},
+			[2]float64{pt[0] - nv[0], pt[1] - nv[1]})
+	}
+	return segs
+}
+
+// normal returns a vector of length w perpendicular to p0→p1.
+func normal(p0, p1 [2]float64, w float64) [2]float64 {
+	dx := p1[0] - p0[0]
+	dy := p1[1] - p0[1]
+	l := dist(dx, dy)
+	return [2]float64{-dy / l * w, dx / l * w}
+}
+
+// appendPolygon appends a closed subpath consisting of pts, making
+// sure it has the same orientation as the subpaths created by
+// ellipseSegs.
+func appendPolygon(segs []pathSeg, pts ...[2]float64) []pathSeg {
+	area := 0.0
+	for i, p := range pts {
+		q := pts[(i+1)%len(pts)]
+		area += p[0]*q[1] - q[0]*p[1]
+	}
+	if area < 0 {
+		for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+			pts[i], pts[j] = pts[j], pts[i]
+		}
+	}
+	for i, p := range pts {
+		cmd := byte('L')
+		if i == 0 {
+			cmd = 'M'
+		}
+		segs = append(segs, pathSeg{cmd: cmd, pts: [3][2]float64{p}})
+	}
+	return append(segs, pathSeg{cmd: 'Z'})
+}
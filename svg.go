@@ -36,6 +36,51 @@ type Conf struct {
 	// Embedded, if set, makes sure that the SVG 'xmlns' attribute
 	// is left out of the generated SVG.
 	Embedded bool
+
+	// CullToViewBox, if set, leaves out shapes, when encoding the
+	// document, whose bounding boxes, after applying transformations,
+	// lie completely outside of the viewBox. This is useful for
+	// generators producing content for a window over a large world.
+	// Stroke widths are not taken into account; text and <use>
+	// elements are never left out.
+	CullToViewBox bool
+
+	// ExplicitZeros, if set, makes sure that x and y attributes
+	// of rectangles, text, <use>, <symbol>, <pattern> and <image>
+	// elements are emitted even if they are zero, instead of
+	// relying on the default value. Object.KeepZero provides
+	// the same for individual attributes.
+	ExplicitZeros bool
+
+	// CirclesAsPaths, if set, makes sure circles and ellipses are
+	// encoded as <path> elements consisting of two elliptical arcs,
+	// for converters that do not support these elements.
+	CirclesAsPaths bool
+
+	// PresentationAttributes, if set, makes sure declarations of
+	// style attributes, and of styles created using MakeStyle, are
+	// encoded as presentation attributes, like fill="red", which have
+	// the broadest support among renderers. Declarations of properties
+	// that have no presentation attribute, or that cannot be converted
+	// without changing their precedence, remain in the style attribute,
+	// or in the embedded stylesheet, respectively.
+	PresentationAttributes bool
+
+	// Precision, if positive, is the number of decimals that
+	// coordinates, lengths, and arguments of transformations are
+	// rounded to when encoding the document, which reduces its size
+	// considerably in case of computed geometry. Only non-integer
+	// numbers within path data are rounded.
+	Precision int
+
+	// ClampNonFinite, if set, makes sure that NaN values are replaced
+	// by zero, and infinities by the largest float32 values, when
+	// encoding the document, instead of failing with a NumberError.
+	// This applies to the coordinates of shapes, points, lengths, path
+	// data, and arguments of transformations, as set by constructors,
+	// or directly. Document.CheckNumbers reports the invalid values
+	// regardless, so that they can be logged.
+	ClampNonFinite bool
 }
 
 // Document contains the SVG document.
@@ -58,6 +103,14 @@ type Document struct {
 
 	NameSpace string `xml:"xmlns,attr,omitempty"`
 	conf      *Conf
+
+	layers      []layer
+	namedLayers []namedLayer
+	lods        []*LODGroup
+
+	transforms map[string]TransformList
+
+	nAutoIDs int
 }
 
 // NewDocument creates an empty SVG document.
@@ -84,6 +137,13 @@ func (d *Document) MakeID(id string) string {
 	return id
 }
 
+// autoID returns a new ID, made unique by appending
+// a number to prefix, for elements created by helpers.
+func (d *Document) autoID(prefix string) string {
+	d.nAutoIDs++
+	return d.MakeID(prefix + "-" + strconv.Itoa(d.nAutoIDs))
+}
+
 // MakeStyle returns a Styling that may be applied to stylable
 // objects using the WithStyle method.
 // If Conf.GenerateEmbeddedStylesheet is set, style
@@ -160,11 +220,34 @@ type Stylable interface {
 type ElemList []interface{}
 
 func (el *ElemList) append(i interface{}) {
-	*el = append(*el, i)
+	recordNumberError(i)
+	*el = append(*el, bindShape(i))
 }
 
 func (el *ElemList) UseObjectInt(x, y int, id string) *Object {
-	u := &use{X: float64(x), Y: float64(y), Href: "#" + id}
+	return el.UseObject(float64(x), float64(y), id)
+}
+
+// UseObject appends a <use> element referring to the element
+// with the specified id, placed using float coordinates.
+func (el *ElemList) UseObject(x, y float64, id string) *Object {
+	u := &use{X: x, Y: y, Href: "#" + id}
+	el.append(u)
+	return &u.Object
+}
+
+// UseSymbol appends a <use> element referring to the symbol s,
+// which must have an ID, placed at x, y. If w and h are not zero,
+// they define the size of the viewport the symbol is fitted into;
+// otherwise the symbol's own size is used.
+func (el *ElemList) UseSymbol(s *Symbol, x, y, w, h float64) *Object {
+	u := &use{X: x, Y: y, Href: "#" + s.ID}
+	if w != 0 {
+		u.Width = Number(w)
+	}
+	if h != 0 {
+		u.Height = Number(h)
+	}
 	el.append(u)
 	return &u.Object
 }
@@ -173,6 +256,8 @@ type use struct {
 	XMLName xml.Name `xml:"use"`
 	X       float64  `xml:"x,attr,omitempty"`
 	Y       float64  `xml:"y,attr,omitempty"`
+	Width   Length   `xml:"width,attr,omitempty"`
+	Height  Length   `xml:"height,attr,omitempty"`
 	Href    string   `xml:"href,attr,omitempty"`
 	Object
 }
@@ -181,6 +266,22 @@ type use struct {
 type Container struct {
 	Object
 	ElemList `xml:",omitempty"`
+
+	when  func() bool
+	onTop bool
+	crisp bool
+}
+
+func (c *Container) container() *Container {
+	return c
+}
+
+// WhenFunc installs a predicate that is evaluated each time the
+// document is encoded. If it returns false, the container
+// and all its child elements are left out.
+func (c *Container) WhenFunc(pred func() bool) *Container {
+	c.when = pred
+	return c
 }
 
 // Group is used as a container to group other SVG elements.
@@ -210,6 +311,20 @@ func (el *ElemList) Group() *Container {
 	return &g.Container
 }
 
+// Link appends an <a> element, making its child elements
+// a hyperlink to href.
+func (el *ElemList) Link(href string) *Container {
+	a := &anchor{Href: href}
+	el.append(a)
+	return &a.Container
+}
+
+type anchor struct {
+	XMLName xml.Name `xml:"a"`
+	Href    string   `xml:"href,attr,omitempty"`
+	Container
+}
+
 // Symbol is used as a container to group other SVG elements
 // that won't be displayed initially.
 type Symbol struct {
@@ -222,6 +337,11 @@ type Symbol struct {
 	Height  Length `xml:"height,attr,omitempty"`
 	ViewBox Ints   `xml:"viewBox,attr,omitempty"`
 
+	// PreserveAspectRatio controls how the viewBox is fitted into
+	// the viewport defined by a referencing <use> element,
+	// like "xMidYMid meet", the default, or "none".
+	PreserveAspectRatio string `xml:"preserveAspectRatio,attr,omitempty"`
+
 	RefX float64 `xml:"refX,attr,omitempty"`
 	RefY float64 `xml:"refY,attr,omitempty"`
 
@@ -236,6 +356,18 @@ func (el *ElemList) Symbol(id string) *Symbol {
 	return s
 }
 
+// SetViewBox sets the viewBox of the symbol.
+func (s *Symbol) SetViewBox(x, y, w, h int) *Symbol {
+	s.ViewBox = Ints{x, y, w, h}
+	return s
+}
+
+// SetPreserveAspectRatio sets the preserveAspectRatio attribute.
+func (s *Symbol) SetPreserveAspectRatio(par string) *Symbol {
+	s.PreserveAspectRatio = par
+	return s
+}
+
 // PreAlloc preallocates memory for the given number of elements.
 func (c *Container) PreAlloc(n int) *Container {
 	if c.ElemList == nil {
@@ -254,9 +386,28 @@ func (c *Container) SetID(id string) *Container {
 type Object struct {
 	ID            string `xml:"id,attr,omitempty"`
 	TransformList `xml:"transform,attr,omitempty"`
+	// CSSTransform contains transformations that are encoded
+	// as CSS transform property, see CSSTransformList.
+	CSSTransform CSSTransformList `xml:"-"`
 	Styling
-	ExtraAttr []xml.MarshalerAttr `xml:",attr,omitempty"`
-	Title     string              `xml:"title,omitempty"`
+	Presentation
+	FilterRef      string              `xml:"filter,attr,omitempty"`
+	Role           Role                `xml:"role,attr,omitempty"`
+	AriaLabel      string              `xml:"aria-label,attr,omitempty"`
+	AriaLabelledBy string              `xml:"aria-labelledby,attr,omitempty"`
+	ExtraAttr      []xml.MarshalerAttr `xml:",attr,omitempty"`
+	Title          string              `xml:"title,omitempty"`
+	Desc           string              `xml:"desc,omitempty"`
+
+	omit   bool
+	expand bool
+	z      int
+	err    error
+	zeros  []string
+}
+
+func (o *Object) object() *Object {
+	return o
 }
 
 func (o *Object) SetID(id string) *Object {
@@ -264,13 +415,63 @@ func (o *Object) SetID(id string) *Object {
 	return o
 }
 
+// KeepZero marks numeric attributes of the element, like "x" or
+// "rx", to be emitted even if they are zero, instead of being left
+// out in favour of their default value. This includes values that
+// are rounded to zero because of Conf.Precision.
+func (o *Object) KeepZero(attrs ...string) *Object {
+	o.zeros = append(o.zeros, attrs...)
+	return o
+}
+
+// SetZ sets the z-index of the object. When encoding, the children
+// of a container are ordered by their z-index, so that objects with
+// higher indices are drawn above their siblings; objects with equal
+// indices, which are zero by default, keep their order. Within a
+// Stream, objects are only ordered relative to siblings written
+// at the same time.
+func (o *Object) SetZ(z int) *Object {
+	o.z = z
+	return o
+}
+
 // SetTitle adds a <title> element to the object.
 func (o *Object) SetTitle(content string) *Object {
 	o.Title = content
 	return o
 }
 
-// Attr adds an arbitrary attribute to the object.
+// SetDesc adds a <desc> element to the object.
+func (o *Object) SetDesc(content string) *Object {
+	o.Desc = content
+	return o
+}
+
+// When marks the object to be left out of the encoded document,
+// if cond is false. This allows generators to toggle optional
+// parts of a drawing without building separate element trees.
+func (o *Object) When(cond bool) *Object {
+	o.omit = !cond
+	return o
+}
+
+// Expand marks a <use> element to be replaced, when encoding the
+// document, by a group containing a copy of the element it references,
+// for consumers that mishandle the styling of <use> instances.
+// The group takes over the use element's ID, styling, and transformation,
+// extended by a translation to its x and y coordinates. IDs within
+// the copy are removed. If the referenced element is a <symbol>, its
+// children are copied, ignoring its viewBox. Expand has no effect
+// on other elements.
+func (o *Object) Expand() *Object {
+	o.expand = true
+	return o
+}
+
+// Attr adds an arbitrary attribute to the object. Its value is
+// escaped when encoding; its name must be a valid XML name, optionally
+// having a prefix, like "data-id", or "xml:lang", otherwise encoding
+// the document fails.
 func (o *Object) Attr(name, value string) {
 	a := &extraAttr{name: name, value: value}
 	o.ExtraAttr = append(o.ExtraAttr, a)
@@ -283,6 +484,9 @@ type extraAttr struct {
 
 func (xa *extraAttr) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
 	var a xml.Attr
+	if !validAttrName(xa.name) {
+		return a, invalidAttrNameError(xa.name)
+	}
 	a.Name.Local = xa.name
 	a.Value = xa.value
 	return a, nil
@@ -309,7 +513,10 @@ type Floats64 []float64
 func (f Floats64) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
 	s := make([]string, len(f))
 	for i, v := range f {
-		s[i] = strconv.FormatFloat(v, 'g', -1, 64)
+		if !validNumber(v) {
+			return xml.Attr{}, &NumberError{Attr: name.Local, Value: v}
+		}
+		s[i] = formatFloat(v)
 	}
 	return makeListAttr(name, s)
 }
@@ -372,7 +579,27 @@ func (p percentage) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
 
 func marshalLengthAttr(name xml.Name, f float64, unit string) (xml.Attr, error) {
 	var a xml.Attr
+	if !validNumber(f) {
+		return a, &NumberError{Attr: name.Local, Value: f}
+	}
 	a.Name = name
-	a.Value = strconv.FormatFloat(f, 'g', -1, 64) + unit
+	a.Value = formatFloat(f) + unit
 	return a, nil
 }
+
+// formatFloat returns the shortest representation of f,
+// omitting the "+" sign of positive exponents.
+func formatFloat(f float64) string {
+	return string(appendFloat(nil, f))
+}
+
+func appendFloat(buf []byte, f float64) []byte {
+	n := len(buf)
+	buf = strconv.AppendFloat(buf, f, 'g', -1, 64)
+	for i := n; i < len(buf)-1; i++ {
+		if buf[i] == 'e' && buf[i+1] == '+' {
+			return append(buf[:i+1], buf[i+2:]...)
+		}
+	}
+	return buf
+}
@@ -0,0 +1,71 @@
+package svg
+
+// Swimlanes describes a set of lanes, as used in process or
+// sequence diagrams, drawn using Container.Swimlanes.
+type Swimlanes struct {
+	Labels []string
+
+	// Vertical, if set, arranges the lanes as columns,
+	// with headers at the top; otherwise the lanes are rows,
+	// with headers at the left side.
+	Vertical bool
+
+	// HeaderSize is the width of the headers of horizontal lanes,
+	// or the height of the headers of vertical lanes;
+	// it defaults to 100 and 30, respectively.
+	HeaderSize float64
+}
+
+// Swimlanes appends a group with class "swimlanes", dividing the
+// rectangle at x, y of the specified size into equally sized lanes,
+// one for each label of s. Each lane consists of a background
+// rectangle, with classes "lane" and "lane-even" or "lane-odd",
+// allowing alternating backgrounds, and a header containing the
+// label, with classes "lane-header" and "lane-label".
+// For each lane, a container is returned, whose origin is
+// translated to the top left corner of the lane's content area,
+// i.e. the area right of, or below, the header.
+func (c *Container) Swimlanes(x, y, w, h float64, s *Swimlanes) []*Container {
+	n := len(s.Labels)
+	hs := s.HeaderSize
+	if hs == 0 {
+		hs = 100
+		if s.Vertical {
+			hs = 30
+		}
+	}
+	g := c.Group()
+	g.SetClass("swimlanes")
+	bg := g.Group()
+	lanes := make([]*Container, n)
+	for i, label := range s.Labels {
+		class := "lane lane-even"
+		if i%2 == 1 {
+			class = "lane lane-odd"
+		}
+		var lx, ly, lw, lh float64 // lane
+		var hw, hh float64         // header size
+		var cx, cy float64         // content origin
+		if s.Vertical {
+			lw, lh = w/float64(n), h
+			lx, ly = x+float64(i)*lw, y
+			hw, hh = lw, hs
+			cx, cy = lx, ly+hs
+		} else {
+			lw, lh = w, h/float64(n)
+			lx, ly = x, y+float64(i)*lh
+			hw, hh = hs, lh
+			cx, cy = lx+hs, ly
+		}
+		bg.Rect(lx, ly, lw, lh).SetClass(class)
+		bg.Rect(lx, ly, hw, hh).SetClass("lane-header")
+		t := bg.Text(lx+hw/2, ly+hh/2, label)
+		t.Anchor(AnchorMiddle)
+		t.Dy = EmUnits(0.35)
+		t.SetClass("lane-label")
+		lane := g.Group()
+		lane.Translate(cx, cy)
+		lanes[i] = lane
+	}
+	return lanes
+}
@@ -0,0 +1,101 @@
+package svg
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Placeholder returns the placeholder for the named value,
+// "{{name}}", which may be used within text content and
+// attribute values of a template document, to be substituted
+// by Fill.
+func Placeholder(name string) string {
+	return "{{" + name + "}}"
+}
+
+// Fill returns a copy of the document, with placeholders within
+// text content, titles, descriptions, the stylesheet, and attribute
+// values replaced by the corresponding values. Placeholders without
+// a value are kept. Since values are inserted before encoding, they
+// are escaped like any other content. The document itself is not
+// modified, so it may serve as a template for many filled copies.
+func (d *Document) Fill(values map[string]string) *Document {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	oldnew := make([]string, 0, 2*len(names))
+	for _, name := range names {
+		oldnew = append(oldnew, Placeholder(name), values[name])
+	}
+	doc := *d
+	fillValue(reflect.ValueOf(&doc).Elem(), strings.NewReplacer(oldnew...))
+	return &doc
+}
+
+var (
+	elemListType = reflect.TypeOf(ElemList(nil))
+	textDataType = reflect.TypeOf(TextData(nil))
+)
+
+// fillValue substitutes placeholders within the strings of v,
+// which must be settable. Slices containing strings or elements
+// are replaced by copies, so that the original values, shared
+// with the template, are not modified.
+func fillValue(v reflect.Value, r *strings.Replacer) {
+	switch v.Kind() {
+	case reflect.String:
+		if s := v.String(); strings.Contains(s, "{{") {
+			v.SetString(r.Replace(s))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			fillValue(v.Field(i), r)
+		}
+	case reflect.Slice:
+		switch v.Type() {
+		case elemListType, textDataType:
+			if v.IsNil() {
+				return
+			}
+			list := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			for i := 0; i < v.Len(); i++ {
+				list.Index(i).Set(reflect.ValueOf(fillElem(v.Index(i).Interface(), r)))
+			}
+			v.Set(list)
+		case reflect.SliceOf(marshalerAttrType):
+			if v.IsNil() {
+				return
+			}
+			list := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			for i := 0; i < v.Len(); i++ {
+				a := v.Index(i).Interface()
+				if xa, ok := a.(*extraAttr); ok {
+					a = &extraAttr{name: xa.name, value: r.Replace(xa.value)}
+				}
+				list.Index(i).Set(reflect.ValueOf(a))
+			}
+			v.Set(list)
+		}
+	}
+}
+
+// fillElem returns a filled copy of x, which
+// may be an element, or text content.
+func fillElem(x interface{}, r *strings.Replacer) interface{} {
+	if s, ok := x.(string); ok {
+		return r.Replace(s)
+	}
+	v := reflect.ValueOf(x)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return x
+	}
+	cp := shallowCopy(x)
+	fillValue(reflect.ValueOf(cp).Elem(), r)
+	return cp
+}
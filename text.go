@@ -18,7 +18,12 @@ const (
 
 // TextInt places a text element using integer coordinates.
 func (el *ElemList) TextInt(x, y int, content string) *TextObject {
-	t := &text{TextObject: TextObject{X: float64(x), Y: float64(y)}}
+	return el.Text(float64(x), float64(y), content)
+}
+
+// Text places a text element using float coordinates.
+func (el *ElemList) Text(x, y float64, content string) *TextObject {
+	t := &text{TextObject: TextObject{X: x, Y: y}}
 	if content != "" {
 		t.Data = append(t.Data, content)
 	}
@@ -57,6 +62,21 @@ func (t *TextObject) Anchor(a TextAnchor) *TextObject {
 	return t
 }
 
+// FitWidth makes sure the text fits into the specified width,
+// given its natural width, as estimated from font metrics.
+// If the natural width exceeds width, textLength is set to width,
+// and lengthAdjust to spacingAndGlyphs, so that renderers
+// compress the glyphs horizontally. Texts that already fit are
+// left unchanged, i.e. they are not stretched.
+func (t *TextObject) FitWidth(width, naturalWidth float64) *TextObject {
+	if naturalWidth <= width {
+		return t
+	}
+	t.TextLength = Number(width)
+	t.LengthAdjust = SpacingAndGlyphs
+	return t
+}
+
 // AddSpan adds a <tspan> element to the parent <text> (or <tspan>) element.
 func (t *TextObject) AddSpan(content string) *TextObject {
 	ts := new(tspan)
@@ -103,7 +123,7 @@ func (list TextData) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 			if x.restoreIndent != "" {
 				e.Indent("", "")
 			}
-			err = e.Encode(d)
+			err = e.Encode(withNumberFormat(x))
 			if x.restoreIndent != "" {
 				e.Indent(x.restorePrefix, x.restoreIndent)
 			}
@@ -0,0 +1,185 @@
+package svg
+
+import (
+	"math"
+	"time"
+)
+
+// TimeScale maps points in time between T0 and T1 linearly onto
+// coordinates between R0 and R1. As required by the Scale
+// interface, times are represented as float64 values, in seconds
+// since the Unix epoch; see Seconds and Time.
+// Ticks are placed at multiples of seconds, minutes, hours, days,
+// weeks, months, or years, within the location of T0.
+type TimeScale struct {
+	T0, T1 time.Time
+	R0, R1 float64
+}
+
+// NewTimeScale returns a scale mapping times between t0 and t1
+// linearly onto coordinates between r0 and r1.
+func NewTimeScale(t0, t1 time.Time, r0, r1 float64) *TimeScale {
+	return &TimeScale{T0: t0, T1: t1, R0: r0, R1: r1}
+}
+
+// Seconds converts t into the representation used by the Scale
+// interface methods.
+func Seconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
+
+// Time converts v, as returned by TimeScale.Ticks, into a time.Time
+// value within the location of T0.
+func (s *TimeScale) Time(v float64) time.Time {
+	sec, frac := math.Modf(v)
+	return time.Unix(int64(sec), int64(frac*1e9)).In(s.T0.Location())
+}
+
+func (s *TimeScale) Map(v float64) float64 {
+	t0 := Seconds(s.T0)
+	t1 := Seconds(s.T1)
+	if t0 == t1 {
+		return s.R0
+	}
+	return s.R0 + (v-t0)/(t1-t0)*(s.R1-s.R0)
+}
+
+// MapTime returns the coordinate corresponding to t.
+func (s *TimeScale) MapTime(t time.Time) float64 {
+	return s.Map(Seconds(t))
+}
+
+func (s *TimeScale) Range() (r0, r1 float64) {
+	return s.R0, s.R1
+}
+
+func (s *TimeScale) Ticks(n int) []float64 {
+	t0, t1 := s.T0, s.T1
+	if t1.Before(t0) {
+		t0, t1 = t1, t0
+	}
+	iv := s.interval(n)
+	var ticks []float64
+	t := iv.floor(t0)
+	if t.Before(t0) {
+		t = iv.add(t)
+	}
+	for ; !t.After(t1); t = iv.add(t) {
+		ticks = append(ticks, Seconds(t))
+	}
+	return ticks
+}
+
+// Format returns a label for tick value v, using a layout
+// depending on the interval chosen for about n ticks.
+func (s *TimeScale) Format(v float64, n int) string {
+	return s.Time(v).Format(s.interval(n).layout)
+}
+
+type timeUnit int
+
+const (
+	unitSecond timeUnit = iota
+	unitMinute
+	unitHour
+	unitDay
+	unitWeek
+	unitMonth
+	unitYear
+)
+
+type timeInterval struct {
+	unit   timeUnit
+	n      int
+	approx time.Duration
+	layout string
+}
+
+const (
+	day   = 24 * time.Hour
+	month = 30 * day
+	year  = 365 * day
+)
+
+var timeIntervals = []timeInterval{
+	{unitSecond, 1, time.Second, "15:04:05"},
+	{unitSecond, 5, 5 * time.Second, "15:04:05"},
+	{unitSecond, 15, 15 * time.Second, "15:04:05"},
+	{unitSecond, 30, 30 * time.Second, "15:04:05"},
+	{unitMinute, 1, time.Minute, "15:04"},
+	{unitMinute, 5, 5 * time.Minute, "15:04"},
+	{unitMinute, 15, 15 * time.Minute, "15:04"},
+	{unitMinute, 30, 30 * time.Minute, "15:04"},
+	{unitHour, 1, time.Hour, "15:04"},
+	{unitHour, 3, 3 * time.Hour, "15:04"},
+	{unitHour, 6, 6 * time.Hour, "Jan 2 15:04"},
+	{unitHour, 12, 12 * time.Hour, "Jan 2 15:04"},
+	{unitDay, 1, day, "Jan 2"},
+	{unitDay, 2, 2 * day, "Jan 2"},
+	{unitWeek, 1, 7 * day, "Jan 2"},
+	{unitMonth, 1, month, "Jan 2006"},
+	{unitMonth, 3, 3 * month, "Jan 2006"},
+	{unitMonth, 6, 6 * month, "Jan 2006"},
+	{unitYear, 1, year, "2006"},
+}
+
+// interval selects the smallest interval resulting in
+// at most n ticks.
+func (s *TimeScale) interval(n int) timeInterval {
+	if n < 1 {
+		n = 1
+	}
+	span := s.T1.Sub(s.T0)
+	if span < 0 {
+		span = -span
+	}
+	for _, iv := range timeIntervals {
+		if span/iv.approx <= time.Duration(n) {
+			return iv
+		}
+	}
+	years := niceStep(float64(span) / float64(year) / float64(n))
+	return timeInterval{unitYear, int(math.Max(years, 1)), year, "2006"}
+}
+
+// floor truncates t to a multiple of the interval.
+func (iv timeInterval) floor(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	h, mi, sec := t.Clock()
+	loc := t.Location()
+	switch iv.unit {
+	case unitSecond:
+		return time.Date(y, mo, d, h, mi, sec-sec%iv.n, 0, loc)
+	case unitMinute:
+		return time.Date(y, mo, d, h, mi-mi%iv.n, 0, 0, loc)
+	case unitHour:
+		return time.Date(y, mo, d, h-h%iv.n, 0, 0, 0, loc)
+	case unitDay:
+		return time.Date(y, mo, d-(d-1)%iv.n, 0, 0, 0, 0, loc)
+	case unitWeek:
+		// weeks start on Monday
+		return time.Date(y, mo, d-(int(t.Weekday())+6)%7, 0, 0, 0, 0, loc)
+	case unitMonth:
+		return time.Date(y, mo-(mo-1)%time.Month(iv.n), 1, 0, 0, 0, 0, loc)
+	}
+	return time.Date(y-y%iv.n, 1, 1, 0, 0, 0, 0, loc)
+}
+
+// add advances t by the interval.
+func (iv timeInterval) add(t time.Time) time.Time {
+	switch iv.unit {
+	case unitSecond:
+		return t.Add(time.Duration(iv.n) * time.Second)
+	case unitMinute:
+		return t.Add(time.Duration(iv.n) * time.Minute)
+	case unitHour:
+		return t.Add(time.Duration(iv.n) * time.Hour)
+	case unitDay:
+		return t.AddDate(0, 0, iv.n)
+	case unitWeek:
+		return t.AddDate(0, 0, 7*iv.n)
+	case unitMonth:
+		return t.AddDate(0, iv.n, 0)
+	}
+	return t.AddDate(iv.n, 0, 0)
+}
@@ -2,6 +2,7 @@ package svg
 
 import (
 	"encoding/xml"
+	"math"
 	"strconv"
 	"strings"
 )
@@ -42,6 +43,11 @@ func (tl *TransformList) TranslateInt(x, y int) *TransformList {
 	return tl.append(translateInt(x, y))
 }
 
+// Translate performs a translation by x and y.
+func (tl *TransformList) Translate(x, y float64) *TransformList {
+	return tl.append(Transform{Name: "translate", Args: []TransformArg{floatArg(x), floatArg(y)}})
+}
+
 func translateInt(x, y int) Transform {
 	return Transform{Name: "translate", Args: []TransformArg{intArg(x), intArg(y)}}
 }
@@ -52,6 +58,12 @@ func (tl *TransformList) RotateOrig(degrees float64) *TransformList {
 	return tl.append(ftrans("rotate", degrees))
 }
 
+// RotateAround adds a rotation by the specified number of degrees
+// around the point cx, cy.
+func (tl *TransformList) RotateAround(degrees, cx, cy float64) *TransformList {
+	return tl.append(Transform{Name: "rotate", Args: []TransformArg{floatArg(degrees), floatArg(cx), floatArg(cy)}})
+}
+
 // Scale performs a scale transformation by x.
 func (tl *TransformList) Scale(x float64) *TransformList {
 	return tl.append(ftrans("scale", x))
@@ -72,6 +84,12 @@ func (tl *TransformList) SkewY(degrees float64) *TransformList {
 	return tl.append(ftrans("skewY", degrees))
 }
 
+// Matrix performs the transformation specified by the
+// matrix [a c e; b d f; 0 0 1].
+func (tl *TransformList) Matrix(a, b, c, d, e, f float64) *TransformList {
+	return tl.append(Matrix{a, b, c, d, e, f}.Transform())
+}
+
 func ftrans(name string, f float64) Transform {
 	return Transform{Name: name, Args: []TransformArg{floatArg(f)}}
 }
@@ -82,4 +100,236 @@ func (i intArg) String() string { return strconv.Itoa(int(i)) }
 
 type floatArg float64
 
-func (f floatArg) String() string { return strconv.FormatFloat(float64(f), 'g', -1, 64) }
+func (f floatArg) String() string { return formatFloat(float64(f)) }
+
+// affine is a 2D affine transformation matrix, with elements
+// ordered like the arguments of the SVG matrix() transform function.
+type affine [6]float64
+
+var identity = affine{1, 0, 0, 1, 0, 0}
+
+// mul returns the product m·n, i.e. a transformation applying
+// n first, then m.
+func (m affine) mul(n affine) affine {
+	return affine{
+		m[0]*n[0] + m[2]*n[1],
+		m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3],
+		m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4],
+		m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+func (m affine) apply(pt [2]float64) [2]float64 {
+	return [2]float64{
+		m[0]*pt[0] + m[2]*pt[1] + m[4],
+		m[1]*pt[0] + m[3]*pt[1] + m[5],
+	}
+}
+
+// isTranslation reports whether m only translates.
+func (m affine) isTranslation() bool {
+	return m[0] == 1 && m[1] == 0 && m[2] == 0 && m[3] == 1
+}
+
+// isAxisAligned reports whether m only translates and scales.
+func (m affine) isAxisAligned() bool {
+	return m[1] == 0 && m[2] == 0
+}
+
+// isSimilarity reports whether m preserves angles, i.e. whether
+// it maps circles onto circles.
+func (m affine) isSimilarity() bool {
+	const eps = 1e-12
+	sx := m[0]*m[0] + m[1]*m[1]
+	sy := m[2]*m[2] + m[3]*m[3]
+	return math.Abs(sx-sy) <= eps*sx && math.Abs(m[0]*m[2]+m[1]*m[3]) <= eps*sx
+}
+
+// transform returns a matrix() Transform representing m.
+func (m affine) transform() Transform {
+	args := make([]TransformArg, len(m))
+	for i, f := range m {
+		args[i] = floatArg(f)
+	}
+	return Transform{Name: "matrix", Args: args}
+}
+
+// matrix returns the combined transformation matrix of tl. If tl
+// contains transformations that cannot be interpreted, false is
+// returned.
+func (tl TransformList) matrix() (affine, bool) {
+	m := identity
+	for _, t := range tl {
+		tm, ok := t.matrix()
+		if !ok {
+			return m, false
+		}
+		m = m.mul(tm)
+	}
+	return m, true
+}
+
+func (t Transform) matrix() (affine, bool) {
+	a := make([]float64, len(t.Args))
+	for i, arg := range t.Args {
+		f, err := strconv.ParseFloat(arg.String(), 64)
+		if err != nil {
+			return identity, false
+		}
+		a[i] = f
+	}
+	switch {
+	case t.Name == "matrix" && len(a) == 6:
+		return affine{a[0], a[1], a[2], a[3], a[4], a[5]}, true
+	case t.Name == "translate" && len(a) == 1:
+		return affine{1, 0, 0, 1, a[0], 0}, true
+	case t.Name == "translate" && len(a) == 2:
+		return affine{1, 0, 0, 1, a[0], a[1]}, true
+	case t.Name == "scale" && len(a) == 1:
+		return affine{a[0], 0, 0, a[0], 0, 0}, true
+	case t.Name == "scale" && len(a) == 2:
+		return affine{a[0], 0, 0, a[1], 0, 0}, true
+	case t.Name == "rotate" && (len(a) == 1 || len(a) == 3):
+		sin, cos := math.Sincos(a[0] * math.Pi / 180)
+		m := affine{cos, sin, -sin, cos, 0, 0}
+		if len(a) == 3 {
+			m = affine{1, 0, 0, 1, a[1], a[2]}.mul(m).mul(affine{1, 0, 0, 1, -a[1], -a[2]})
+		}
+		return m, true
+	case t.Name == "skewX" && len(a) == 1:
+		return affine{1, 0, math.Tan(a[0] * math.Pi / 180), 1, 0, 0}, true
+	case t.Name == "skewY" && len(a) == 1:
+		return affine{1, math.Tan(a[0] * math.Pi / 180), 0, 1, 0, 0}, true
+	}
+	return identity, false
+}
+
+// LocalFrame is a group with a local coordinate system, as created by
+// WithLocalFrame. Elements added to its Container are specified in
+// local coordinates; its methods map points and lengths between the
+// local system and that of the parent container.
+type LocalFrame struct {
+	*Container
+	parent *Container
+	m      affine // maps local to parent coordinates
+}
+
+// WithLocalFrame appends a group with a local coordinate system
+// having its origin at originX, originY of the container's system,
+// rotated by the specified number of degrees, and scaled by scaleX
+// and scaleY. Elements added to the returned frame can be
+// specified using the natural coordinates of a sub-drawing,
+// e.g. a y-axis pointing upwards by passing a negative scaleY.
+// Note that text will be mirrored too in this case; LocalFrame.Text
+// places upright text instead.
+func (c *Container) WithLocalFrame(originX, originY, scaleX, scaleY, rotation float64) *LocalFrame {
+	g := c.Group()
+	tl := &g.TransformList
+	if originX != 0 || originY != 0 {
+		tl.Translate(originX, originY)
+	}
+	if rotation != 0 {
+		tl.RotateOrig(rotation)
+	}
+	if scaleX != 1 || scaleY != 1 {
+		tl.ScaleXY(scaleX, scaleY)
+	}
+	m, _ := tl.matrix()
+	return &LocalFrame{Container: g, parent: c, m: m}
+}
+
+// Point maps the local point x, y into the coordinate
+// system of the frame's parent container.
+func (f *LocalFrame) Point(x, y float64) (float64, float64) {
+	pt := f.m.apply([2]float64{x, y})
+	return pt[0], pt[1]
+}
+
+// Local maps the point x, y of the parent container's coordinate
+// system into the frame's local system. If the frame's scale is
+// zero, false is returned.
+func (f *LocalFrame) Local(x, y float64) (float64, float64, bool) {
+	inv, ok := f.m.invert()
+	if !ok {
+		return 0, 0, false
+	}
+	pt := inv.apply([2]float64{x, y})
+	return pt[0], pt[1], true
+}
+
+// Length maps a local length into the coordinate system of the
+// parent container. If the frame is scaled differently along its
+// axes, the geometric mean of the scale factors is used.
+func (f *LocalFrame) Length(l float64) float64 {
+	return l * math.Sqrt(math.Abs(f.m[0]*f.m[3]-f.m[1]*f.m[2]))
+}
+
+// Text places a text element at the local point x, y, which, unlike
+// text added to the frame's Container, is neither mirrored, rotated,
+// nor scaled. It is appended to the parent container, after the
+// frame's group.
+func (f *LocalFrame) Text(x, y float64, content string) *TextObject {
+	px, py := f.Point(x, y)
+	return f.parent.Text(px, py, content)
+}
+
+// invert returns the inverse of m. If m is not invertible,
+// false is returned.
+func (m affine) invert() (affine, bool) {
+	det := m[0]*m[3] - m[1]*m[2]
+	if det == 0 {
+		return identity, false
+	}
+	return affine{
+		m[3] / det,
+		-m[1] / det,
+		-m[2] / det,
+		m[0] / det,
+		(m[2]*m[5] - m[3]*m[4]) / det,
+		(m[1]*m[4] - m[0]*m[5]) / det,
+	}, true
+}
+
+// Matrix is a 2D affine transformation matrix [a c e; b d f; 0 0 1],
+// with elements ordered like the arguments of the SVG matrix()
+// transform function: a, b, c, d, e, f.
+type Matrix [6]float64
+
+// Identity is the identity transformation.
+var Identity = Matrix(identity)
+
+// Multiply returns the product m·n, i.e. a transformation
+// applying n first, then m.
+func (m Matrix) Multiply(n Matrix) Matrix {
+	return Matrix(affine(m).mul(affine(n)))
+}
+
+// Invert returns the inverse of m. If m is not invertible,
+// false is returned.
+func (m Matrix) Invert() (Matrix, bool) {
+	inv, ok := affine(m).invert()
+	return Matrix(inv), ok
+}
+
+// Apply returns the point x, y transformed by m.
+func (m Matrix) Apply(x, y float64) (float64, float64) {
+	pt := affine(m).apply([2]float64{x, y})
+	return pt[0], pt[1]
+}
+
+// Transform returns a matrix() Transform representing m,
+// that may be appended to a TransformList.
+func (m Matrix) Transform() Transform {
+	return affine(m).transform()
+}
+
+// ToMatrix returns the combined transformation matrix of tl, which
+// maps coordinates of the element's system onto those of its parent.
+// If tl contains transformations that cannot be interpreted,
+// false is returned.
+func (tl TransformList) ToMatrix() (Matrix, bool) {
+	m, ok := tl.matrix()
+	return Matrix(m), ok
+}
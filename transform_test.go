@@ -0,0 +1,38 @@
+package svg
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestLocalFrame(t *testing.T) {
+	d := NewDocument(nil)
+	f := d.WithLocalFrame(10, 100, 2, -2, 0)
+	if x, y := f.Point(5, 10); x != 20 || y != 80 {
+		t.Errorf("Point(5, 10) = %v, %v, want 20, 80", x, y)
+	}
+	if x, y, ok := f.Local(20, 80); !ok || x != 5 || y != 10 {
+		t.Errorf("Local(20, 80) = %v, %v, %v, want 5, 10, true", x, y, ok)
+	}
+	if l := f.Length(3); l != 6 {
+		t.Errorf("Length(3) = %v, want 6", l)
+	}
+
+	r := d.WithLocalFrame(0, 0, 1, 1, 90)
+	if x, y := r.Point(1, 0); math.Abs(x) > 1e-12 || math.Abs(y-1) > 1e-12 {
+		t.Errorf("rotated Point(1, 0) = %v, %v, want 0, 1", x, y)
+	}
+
+	f.Circle(5, 10, 1)
+	f.Text(5, 10, "label")
+	got := encodeString(t, d)
+	for _, want := range []string{
+		`<g transform="translate(10,100) scale(2,-2)"><circle cx="5" cy="10" r="1"></circle></g>`,
+		`<text x="20" y="80">label</text>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got %s, want it to contain %s", got, want)
+		}
+	}
+}
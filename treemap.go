@@ -0,0 +1,196 @@
+package svg
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// TreemapNode is a node of a weighted tree to be drawn using
+// Container.Treemap.
+type TreemapNode struct {
+	Label string
+
+	// Value is the weight of a leaf node; the weight
+	// of an inner node is the sum of its children's weights.
+	Value float64
+
+	// Class is added to the class attribute of the node's group,
+	// and may be used to specify its fill color.
+	Class string
+
+	// Fill, if set, is used as fill color of the node's cell,
+	// overriding any class based styling. The label is then
+	// drawn in black or white, whichever is more readable.
+	Fill Paint
+
+	Children []*TreemapNode
+}
+
+func (n *TreemapNode) weight() float64 {
+	if len(n.Children) == 0 {
+		return math.Max(n.Value, 0)
+	}
+	sum := 0.0
+	for _, c := range n.Children {
+		sum += c.weight()
+	}
+	return sum
+}
+
+// Treemap describes a treemap to be drawn using Container.Treemap.
+type Treemap struct {
+	Root *TreemapNode
+
+	// Padding is the space between the border of an inner node
+	// and the area occupied by its children.
+	Padding float64
+
+	// HeaderSize is the additional space reserved at the top
+	// of inner nodes for their labels.
+	HeaderSize float64
+}
+
+// Treemap appends a group with class "treemap", dividing the
+// rectangle at x, y of the specified size into nested rectangles,
+// one for each node of the tree t.Root, with areas proportional to
+// the nodes' weights, using the squarified layout algorithm.
+// Each node is represented by a group with classes "treemap-node",
+// "treemap-depth-N", where N is the node's depth, and the node's own
+// class; it contains a rectangle with class "treemap-cell",
+// the label with class "treemap-label", and the groups of its
+// children. The root node itself is not drawn.
+func (c *Container) Treemap(x, y, w, h float64, t *Treemap) *Container {
+	g := c.Group()
+	g.SetClass("treemap")
+	if t.Root != nil {
+		t.layoutChildren(g, t.Root, 1, BBox{X: x, Y: y, Width: w, Height: h})
+	}
+	return g
+}
+
+func (t *Treemap) layoutChildren(g *Container, n *TreemapNode, depth int, r BBox) {
+	weights := make([]float64, len(n.Children))
+	for i, child := range n.Children {
+		weights[i] = child.weight()
+	}
+	for i, b := range squarify(weights, r) {
+		child := n.Children[i]
+		if b.Width <= 0 || b.Height <= 0 {
+			continue
+		}
+		cg := g.Group()
+		class := "treemap-node treemap-depth-" + strconv.Itoa(depth)
+		if child.Class != "" {
+			class += " " + child.Class
+		}
+		cg.SetClass(class)
+		cell := cg.Rect(b.X, b.Y, b.Width, b.Height)
+		cell.SetClass("treemap-cell")
+		if child.Fill != "" {
+			cell.SetStyle(child.Fill.Decl("fill"))
+		}
+		if child.Label != "" {
+			l := cg.Text(b.X+3, b.Y+3, child.Label)
+			l.Dy = EmUnits(1)
+			l.SetClass("treemap-label")
+			if child.Fill != "" {
+				l.SetStyle(TextColor(child.Fill).Decl("fill"))
+			}
+		}
+		if len(child.Children) != 0 {
+			p := t.Padding
+			inner := BBox{
+				X:      b.X + p,
+				Y:      b.Y + p + t.HeaderSize,
+				Width:  b.Width - 2*p,
+				Height: b.Height - 2*p - t.HeaderSize,
+			}
+			if inner.Width > 0 && inner.Height > 0 {
+				t.layoutChildren(cg, child, depth+1, inner)
+			}
+		}
+	}
+}
+
+// squarify divides r into rectangles with areas proportional
+// to weights, trying to keep their aspect ratios close to one.
+// The rectangles are returned in the order of weights.
+func squarify(weights []float64, r BBox) []BBox {
+	boxes := make([]BBox, len(weights))
+	sum := 0.0
+	for _, w := range weights {
+		sum += w
+	}
+	if sum <= 0 {
+		return boxes
+	}
+	scale := r.Width * r.Height / sum
+
+	// process items in order of decreasing weight
+	order := make([]int, 0, len(weights))
+	for i, w := range weights {
+		if w > 0 {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return weights[order[i]] > weights[order[j]]
+	})
+
+	// worst returns the highest aspect ratio within a row
+	// of the specified total area, laid out along a side of length s.
+	worst := func(area, amin, amax, s float64) float64 {
+		s2 := s * s
+		return math.Max(s2*amax/(area*area), area*area/(s2*amin))
+	}
+	layout := func(row []int, area float64) {
+		if r.Width >= r.Height {
+			// vertical strip at the left side
+			sw := area / r.Height
+			y := r.Y
+			for _, i := range row {
+				h := weights[i] * scale / sw
+				boxes[i] = BBox{X: r.X, Y: y, Width: sw, Height: h}
+				y += h
+			}
+			r.X += sw
+			r.Width -= sw
+		} else {
+			// horizontal strip at the top
+			sh := area / r.Width
+			x := r.X
+			for _, i := range row {
+				w := weights[i] * scale / sh
+				boxes[i] = BBox{X: x, Y: r.Y, Width: w, Height: sh}
+				x += w
+			}
+			r.Y += sh
+			r.Height -= sh
+		}
+	}
+
+	var row []int
+	var area, amin, amax float64
+	for _, i := range order {
+		a := weights[i] * scale
+		if len(row) != 0 {
+			s := math.Min(r.Width, r.Height)
+			if worst(area+a, math.Min(amin, a), math.Max(amax, a), s) > worst(area, amin, amax, s) {
+				layout(row, area)
+				row = row[:0]
+			}
+		}
+		if len(row) == 0 {
+			area, amin, amax = 0, a, a
+		}
+		row = append(row, i)
+		area += a
+		amin = math.Min(amin, a)
+		amax = math.Max(amax, a)
+	}
+	if len(row) != 0 {
+		layout(row, area)
+	}
+	return boxes
+}
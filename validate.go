@@ -0,0 +1,107 @@
+package svg
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// validAttrName reports whether name is a valid XML name, optionally
+// qualified by a prefix, that may be used as an attribute name.
+func validAttrName(name string) bool {
+	if i := strings.IndexByte(name, ':'); i != -1 {
+		return validNCName(name[:i]) && validNCName(name[i+1:])
+	}
+	return validNCName(name)
+}
+
+// validNCName reports whether s is an XML name without colons.
+func validNCName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r == utf8.RuneError || !isNameChar(r, i == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNameChar reports whether r may appear within an XML name,
+// or, if start is set, at its start, as defined by XML 1.0.
+func isNameChar(r rune, start bool) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_',
+		r >= 0xC0 && r <= 0xD6, r >= 0xD8 && r <= 0xF6, r >= 0xF8 && r <= 0x2FF,
+		r >= 0x370 && r <= 0x37D, r >= 0x37F && r <= 0x1FFF, r >= 0x200C && r <= 0x200D,
+		r >= 0x2070 && r <= 0x218F, r >= 0x2C00 && r <= 0x2FEF, r >= 0x3001 && r <= 0xD7FF,
+		r >= 0xF900 && r <= 0xFDCF, r >= 0xFDF0 && r <= 0xFFFD, r >= 0x10000 && r <= 0xEFFFF:
+		return true
+	case start:
+		return false
+	}
+	return r == '-' || r == '.' || r >= '0' && r <= '9' || r == 0xB7 ||
+		r >= 0x300 && r <= 0x36F || r >= 0x203F && r <= 0x2040
+}
+
+// checkMarkup returns an error if the markup s, to be written
+// verbatim as content of an element, is not well-formed, i.e. if
+// its tags are unbalanced, or it contains declarations, like
+// <!DOCTYPE>, which are not allowed within an element.
+func checkMarkup(s string) error {
+	dec := xml.NewDecoder(strings.NewReader(s))
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.Directive:
+			return errors.New("unexpected declaration <!" + string(t) + ">")
+		case xml.ProcInst:
+			if strings.EqualFold(t.Target, "xml") {
+				return errors.New("unexpected XML declaration")
+			}
+		}
+	}
+	if depth != 0 {
+		return errors.New("unclosed element")
+	}
+	return nil
+}
+
+// plainForeignObject has the same layout as ForeignObject,
+// but lacks its MarshalXML method.
+type plainForeignObject ForeignObject
+
+// MarshalXML encodes the element, after making sure that its
+// content is well-formed markup, which would otherwise result
+// in a malformed document.
+func (f *ForeignObject) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := checkMarkup(f.Content); err != nil {
+		return errors.New("svg: foreignObject: invalid content: " + err.Error())
+	}
+	// the start element passed by the encoder is named after
+	// the field or type containing f, like "ElemList"
+	start.Name = f.XMLName
+	if start.Name.Local == "" {
+		start.Name.Local = "foreignObject"
+	}
+	return e.EncodeElement(withNumberFormat((*plainForeignObject)(f)), start)
+}
+
+func invalidAttrNameError(name string) error {
+	return errors.New("svg: invalid attribute name " + strconv.Quote(name))
+}
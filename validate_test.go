@@ -0,0 +1,145 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+func TestValidAttrName(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		valid bool
+	}{
+		{"fill", true},
+		{"data-x", true},
+		{"xlink:href", true},
+		{"_a.b-1", true},
+		{"é", true},
+		{"", false},
+		{"a b", false},
+		{"1x", false},
+		{"-x", false},
+		{"x:", false},
+		{":x", false},
+		{"a:b:c", false},
+		{`a"=x`, false},
+		{"a>", false},
+		{"a\x00", false},
+		{"a\xff", false},
+	} {
+		if got := validAttrName(tc.name); got != tc.valid {
+			t.Errorf("validAttrName(%q) = %v, want %v", tc.name, got, tc.valid)
+		}
+	}
+}
+
+func TestCheckMarkup(t *testing.T) {
+	for _, tc := range []struct {
+		markup string
+		valid  bool
+	}{
+		{"", true},
+		{"text", true},
+		{"<a>b</a><c/>", true},
+		{"<!-- x -->", true},
+		{"<![CDATA[<a>]]>", true},
+		{"</a><a>", false},
+		{"<a>", false},
+		{"<a></b>", false},
+		{"<!DOCTYPE x>", false},
+		{"<?xml ?>", false},
+		{`<?xml version="1.0"?><a/>`, false},
+		{"a & b", false},
+		{"<a b=c/>", false},
+	} {
+		err := checkMarkup(tc.markup)
+		if (err == nil) != tc.valid {
+			t.Errorf("checkMarkup(%q) = %v, want valid = %v", tc.markup, err, tc.valid)
+		}
+	}
+}
+
+// wellFormed returns an error if b is not a well-formed
+// XML document.
+func wellFormed(b []byte) error {
+	dec := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		_, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func TestEncodeRejectsMalformed(t *testing.T) {
+	for _, name := range []string{"a b", "1x", "x:", ":x", `a"=x`} {
+		d := NewDocument(nil)
+		d.Rect(0, 0, 1, 1).Attr(name, "v")
+		var buf bytes.Buffer
+		if err := d.Encode(&buf); err == nil {
+			t.Errorf("attribute %q: missing error, got %s", name, buf.Bytes())
+		}
+	}
+	for _, content := range []string{"</a><a>", "<!DOCTYPE x>", "<?xml ?>", "<a>"} {
+		d := NewDocument(nil)
+		f := d.ForeignObject(0, 0, 1, 1, "")
+		f.Content = content
+		var buf bytes.Buffer
+		if err := d.Encode(&buf); err == nil {
+			t.Errorf("content %q: missing error, got %s", content, buf.Bytes())
+		}
+		if _, err := xml.Marshal(f); err == nil {
+			t.Errorf("content %q: xml.Marshal: missing error", content)
+		}
+	}
+}
+
+func TestForeignObjectName(t *testing.T) {
+	d := NewDocument(nil)
+	d.ForeignObject(0, 0, 1, 1, "<p>x</p>")
+	var buf bytes.Buffer
+	if err := d.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("<foreignObject ")) {
+		t.Errorf("missing foreignObject element: %s", buf.Bytes())
+	}
+}
+
+// TestRandomEscapeHatches encodes documents with random attribute names
+// and foreignObject content, and checks that encoding either fails,
+// or results in a well-formed document.
+func TestRandomEscapeHatches(t *testing.T) {
+	const alphabet = `ab:-_.1 "'=<>/&;!?[]` + "\x00\t\n\xc3\xa9\xff"
+	rnd := rand.New(rand.NewSource(1))
+	random := func(n int) string {
+		b := make([]byte, rnd.Intn(n+1))
+		for i := range b {
+			b[i] = alphabet[rnd.Intn(len(alphabet))]
+		}
+		return string(b)
+	}
+	encoded := 0
+	for i := 0; i < 20000; i++ {
+		d := NewDocument(nil)
+		d.Rect(0, 0, 1, 1).Attr(random(6), random(6))
+		d.ForeignObject(0, 0, 1, 1, "").Content = random(12)
+		var buf bytes.Buffer
+		if err := d.Encode(&buf); err != nil {
+			continue
+		}
+		if err := wellFormed(buf.Bytes()); err != nil {
+			t.Fatalf("malformed output %q: %v", buf.Bytes(), err)
+		}
+		encoded++
+	}
+	if encoded < 100 {
+		t.Errorf("only %d documents encoded without error", encoded)
+	}
+}
@@ -0,0 +1,214 @@
+package svg
+
+import (
+	"math"
+	"strings"
+)
+
+// FitViewBox sets the document's viewBox to the bounding box of its
+// content, extended by padding on each side, and rounded outwards to
+// integers. Width and Height, if set to plain numbers, are adjusted
+// to the size of the viewBox, so that user units keep their size.
+//
+// In addition to the shapes considered by Container.BBox, <use>
+// elements are resolved, named transformations are expanded, and
+// the extent of texts is estimated from their font size and text
+// anchor, as set using style attributes or classes, and the width
+// reported by DefaultMeasurer. Stroke widths are not taken into
+// account, so padding should be at least half the largest stroke
+// width.
+//
+// If the document contains no content that can be measured, it is
+// left unchanged, and false is returned. False is also returned,
+// after setting the viewBox, if some elements have been skipped,
+// like those having a CSSTransform, or <use> elements referring to
+// unknown IDs, in which case the viewBox may be too small.
+func (d *Document) FitViewBox(padding float64) bool {
+	m, ok := expandTransforms(d.TransformList, d.transforms).matrix()
+	if !ok {
+		return false
+	}
+	b := &bboxBuilder{fit: true, doc: d, font: fontProps{size: defaultFontSize}}
+	b.font = d.fontProps(&d.Object, b.font)
+	b.list(d.ElemList, m, "")
+	if !b.ok {
+		return false
+	}
+	x0 := math.Floor(b.min[0] - padding)
+	y0 := math.Floor(b.min[1] - padding)
+	x1 := math.Ceil(b.max[0] + padding)
+	y1 := math.Ceil(b.max[1] + padding)
+	d.ViewBox = Ints{int(x0), int(y0), int(x1 - x0), int(y1 - y0)}
+	if _, ok := d.Width.(number); ok {
+		d.Width = Number(x1 - x0)
+	}
+	if _, ok := d.Height.(number); ok {
+		d.Height = Number(y1 - y0)
+	}
+	return !b.skipped
+}
+
+// fitElem extends the box by element x, transformed by m,
+// as needed by FitViewBox.
+func (b *bboxBuilder) fitElem(x interface{}, m affine, sel string) {
+	switch v := x.(type) {
+	case *use:
+		b.use(v, m, sel)
+	case container:
+		b.list(v.container().ElemList, m, sel)
+	case *text:
+		if sel == "" {
+			b.text(&v.TextObject, 0, 0, m)
+		}
+	default:
+		if sel == "" {
+			b.shape(x, m)
+		}
+	}
+}
+
+// use extends the box by the element referenced by u.
+func (b *bboxBuilder) use(u *use, m affine, sel string) {
+	if b.ids == nil {
+		b.ids = make(map[string]interface{})
+		collectIDs(b.doc.ElemList, b.ids)
+	}
+	target, ok := b.ids[strings.TrimPrefix(u.Href, "#")]
+	if !ok || b.useDepth == maxUseDepth {
+		b.skipped = true
+		return
+	}
+	m = m.mul(affine{1, 0, 0, 1, u.X, u.Y})
+	b.useDepth++
+	if s, ok := target.(*Symbol); ok {
+		font := b.font
+		b.font = b.doc.fontProps(&s.Object, font)
+		b.list(s.ElemList, m, sel)
+		b.font = font
+	} else {
+		b.list(ElemList{target}, m, sel)
+	}
+	b.useDepth--
+}
+
+// Fractions of the font size used to estimate the
+// vertical extent of text above and below the baseline.
+const (
+	textAscent  = 0.8
+	textDescent = 0.2
+)
+
+// text extends the box by the estimated extent of the text object t,
+// which is placed at x, y, unless it has coordinates of its own.
+// Spans with coordinates of their own are estimated separately.
+func (b *bboxBuilder) text(t *TextObject, x, y float64, m affine) {
+	if t.X != 0 {
+		x = t.X
+	}
+	if t.Y != 0 {
+		y = t.Y
+	}
+	var s strings.Builder
+	for _, d := range t.Data {
+		switch v := d.(type) {
+		case string:
+			s.WriteString(v)
+		case *tspan:
+			font := b.font
+			b.font = b.doc.fontProps(&v.Object, font)
+			if v.X != 0 || v.Y != 0 {
+				b.text(&v.TextObject, x, y, m)
+			} else {
+				s.WriteString(v.text())
+			}
+			b.font = font
+		}
+	}
+	if s.Len() == 0 {
+		return
+	}
+	anchor := b.font.anchor
+	if t.TextAnchor != "" {
+		anchor = t.TextAnchor
+	}
+	fs := b.font.size
+	w := DefaultMeasurer.TextWidth(s.String(), fs)
+	switch anchor {
+	case AnchorMiddle:
+		x -= w / 2
+	case AnchorEnd:
+		x -= w
+	}
+	segs := pointSegs(Points{
+		{x, y - textAscent*fs},
+		{x + w, y - textAscent*fs},
+		{x + w, y + textDescent*fs},
+		{x, y + textDescent*fs},
+	}, true)
+	transformPath(segs, m)
+	if min, max, ok := segsBBox(segs); ok {
+		b.add(min, max)
+	}
+}
+
+// text returns the character data of the span,
+// including that of nested spans.
+func (t *tspan) text() string {
+	var s strings.Builder
+	for _, d := range t.Data {
+		switch v := d.(type) {
+		case string:
+			s.WriteString(v)
+		case *tspan:
+			s.WriteString(v.text())
+		}
+	}
+	return s.String()
+}
+
+// defaultFontSize is the initial value of CSS font-size.
+const defaultFontSize = 16
+
+// fontProps contains the properties used to estimate
+// the extent of texts.
+type fontProps struct {
+	size   float64
+	anchor TextAnchor
+}
+
+// fontProps returns the font properties of obj, as declared within
+// the styles of its classes and its style attribute, or inherited.
+func (d *Document) fontProps(obj *Object, inherited fontProps) fontProps {
+	f := inherited
+	var decls []declaration
+	for _, class := range strings.Fields(obj.Class) {
+		decls = appendDecls(decls, d.styles.classMap[class])
+	}
+	decls = appendDecls(decls, obj.Style)
+	for _, decl := range decls {
+		switch decl.prop {
+		case "font-size":
+			l, err := parseLength(decl.value)
+			if err != nil {
+				continue
+			}
+			v, unit, ok := lengthValue(l)
+			if !ok {
+				continue
+			}
+			switch unit {
+			case "em":
+				f.size = v * inherited.size
+			case "%":
+				f.size = v / 100 * inherited.size
+			default:
+				if px, ok := pxPerUnit[unit]; ok {
+					f.size = v * px
+				}
+			}
+		case "text-anchor":
+			f.anchor = TextAnchor(decl.value)
+		}
+	}
+	return f
+}
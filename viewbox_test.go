@@ -0,0 +1,57 @@
+package svg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFitViewBox(t *testing.T) {
+	d := NewDocument(nil)
+	d.Width = Number(1)
+	d.Rect(0, 0, 10, 10)
+	if !d.FitViewBox(1) {
+		t.Fatal("FitViewBox failed")
+	}
+	if want := (Ints{-1, -1, 12, 12}); !reflect.DeepEqual(d.ViewBox, want) {
+		t.Errorf("rect: viewBox = %v, want %v", d.ViewBox, want)
+	}
+	if d.Width != Number(12) || d.Height != nil {
+		t.Errorf("width, height = %v, %v", d.Width, d.Height)
+	}
+
+	// a label, centered below the rectangle, 4 * 0.55 * 10 wide
+	d.Text(5, 20, "abcd").Anchor(AnchorMiddle).SetStyle("font-size:10px")
+	d.FitViewBox(0)
+	if want := (Ints{-6, 0, 22, 22}); !reflect.DeepEqual(d.ViewBox, want) {
+		t.Errorf("text: viewBox = %v, want %v", d.ViewBox, want)
+	}
+
+	// a symbol placed using a named transformation
+	d.Defs().Symbol("s").Circle(0, 0, 5)
+	var far TransformList
+	far.Translate(100, 0)
+	d.DefineTransform("far", far)
+	d.UseObject(0, 50, "s").UseTransform("far")
+	if !d.FitViewBox(0) {
+		t.Error("FitViewBox reported skipped elements")
+	}
+	if want := (Ints{-6, 0, 111, 55}); !reflect.DeepEqual(d.ViewBox, want) {
+		t.Errorf("use: viewBox = %v, want %v", d.ViewBox, want)
+	}
+
+	d.UseObject(0, 0, "missing")
+	if d.FitViewBox(0) {
+		t.Error("unresolved <use> not reported")
+	}
+}
+
+func TestFitViewBoxEmpty(t *testing.T) {
+	d := NewDocument(nil)
+	d.ViewBox = Ints{0, 0, 1, 1}
+	if d.FitViewBox(0) {
+		t.Error("FitViewBox succeeded on an empty document")
+	}
+	if want := (Ints{0, 0, 1, 1}); !reflect.DeepEqual(d.ViewBox, want) {
+		t.Errorf("viewBox changed to %v", d.ViewBox)
+	}
+}
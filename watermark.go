@@ -0,0 +1,97 @@
+package svg
+
+import (
+	"math"
+)
+
+// Watermark describes a watermark to be added to a document
+// using Document.Watermark.
+type Watermark struct {
+	// Text is displayed as watermark, unless SymbolID is set.
+	Text string
+
+	// SymbolID, if set, is the ID of an element, usually a
+	// <symbol>, referenced by the watermark.
+	SymbolID string
+
+	// Opacity defaults to 0.15.
+	Opacity float64
+
+	// Angle is the rotation of the watermark, in degrees;
+	// negative values result in text ascending to the right.
+	Angle float64
+
+	// Tile, if set, repeats the watermark over the whole viewBox,
+	// otherwise a single instance is placed at its center.
+	Tile bool
+
+	// Spacing is the distance between the centers of the tiles;
+	// it defaults to a third of the larger dimension of the viewBox.
+	Spacing float64
+
+	// Below, if set, places the watermark below the content
+	// of the document, instead of on top of it.
+	Below bool
+}
+
+// Watermark adds a watermark, as described by w, to the document,
+// for example to mark drafts or confidential documents.
+// The viewBox must have been set before. Text watermarks are styled
+// using the class "watermark", as returned by MakeStyle.
+// The returned group ignores pointer events, so that it does
+// not interfere with interactive content.
+func (d *Document) Watermark(w *Watermark) *Container {
+	g := new(Group)
+	if w.Below {
+		d.ElemList = append(ElemList{g}, d.ElemList...)
+	} else {
+		d.append(g)
+	}
+	opacity := w.Opacity
+	if opacity == 0 {
+		opacity = 0.15
+	}
+	g.SetStyle("opacity:" + formatFloat(opacity) + ";pointer-events:none")
+
+	id := w.SymbolID
+	if id == "" {
+		id = d.autoID("watermark")
+		t := g.Defs().Text(0, 0, w.Text)
+		t.SetID(id)
+		t.Dy = EmUnits(0.35)
+		t.WithStyle(d.MakeStyle("watermark", "font-size:48px;fill:#888;text-anchor:middle"))
+	}
+
+	var vx, vy, vw, vh float64
+	if len(d.ViewBox) == 4 {
+		vx, vy = float64(d.ViewBox[0]), float64(d.ViewBox[1])
+		vw, vh = float64(d.ViewBox[2]), float64(d.ViewBox[3])
+	}
+	place := func(x, y float64) {
+		u := &use{Href: "#" + id}
+		u.TransformList.Translate(x, y)
+		if w.Angle != 0 {
+			u.TransformList.RotateOrig(w.Angle)
+		}
+		g.append(u)
+	}
+	if !w.Tile {
+		place(vx+vw/2, vy+vh/2)
+		return &g.Container
+	}
+	sp := w.Spacing
+	if sp <= 0 {
+		sp = math.Max(vw, vh) / 3
+	}
+	// stagger every other row
+	for i, y := 0, vy+sp/2; y < vy+vh+sp/2; i, y = i+1, y+sp {
+		x0 := vx + sp/2
+		if i%2 == 1 {
+			x0 = vx
+		}
+		for x := x0; x < vx+vw+sp/2; x += sp {
+			place(x, y)
+		}
+	}
+	return &g.Container
+}
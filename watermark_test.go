@@ -0,0 +1,35 @@
+package svg
+
+import "testing"
+
+func TestWatermarkIDs(t *testing.T) {
+	d := NewDocument(nil)
+	d.ViewBox = Ints{0, 0, 100, 100}
+	d.Watermark(&Watermark{Text: "DRAFT"})
+	d.Watermark(&Watermark{Text: "CONFIDENTIAL", Below: true})
+
+	ids := make(map[string]interface{})
+	collectIDs(d.ElemList, ids)
+	if len(ids) != 2 {
+		t.Fatalf("got IDs %v, want two distinct IDs", ids)
+	}
+	for id, x := range ids {
+		want := x.(*text).Data[0]
+		var n int
+		var check func(el ElemList)
+		check = func(el ElemList) {
+			for _, x := range el {
+				if u, ok := x.(*use); ok && u.Href == "#"+id {
+					n++
+				}
+				if c, ok := x.(container); ok {
+					check(c.container().ElemList)
+				}
+			}
+		}
+		check(d.ElemList)
+		if n != 1 {
+			t.Errorf("%s: %d references to #%s, want 1", want, n, id)
+		}
+	}
+}
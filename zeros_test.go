@@ -0,0 +1,38 @@
+package svg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func encodeString(t *testing.T, d *Document) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := d.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+func TestKeepZero(t *testing.T) {
+	d := NewDocument(nil)
+	d.Rect(0, 1, 2, 3).KeepZero("x", "rx")
+	d.Rect(0, 0, 2, 3)
+	got := encodeString(t, d)
+	want := `<rect y="1" width="2" height="3" x="0" rx="0"></rect><rect width="2" height="3"></rect>`
+	if !strings.Contains(got, want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestExplicitZerosRounded(t *testing.T) {
+	d := NewDocument(&Conf{Precision: 1, ExplicitZeros: true})
+	d.Rect(0.01, 1, 2, 3)
+	d.Circle(0, 0, 1)
+	got := encodeString(t, d)
+	want := `<rect y="1" width="2" height="3" x="0"></rect><circle cx="0" cy="0" r="1"></circle>`
+	if !strings.Contains(got, want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}